@@ -0,0 +1,95 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package problab
+
+import (
+	"fmt"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+	"github.com/zintix-labs/problab/stats"
+)
+
+// MatrixPlan 批次模擬設定：GIDs × BetModes 的每個組合各自模擬 Rounds 局
+type MatrixPlan struct {
+	GIDs     []spec.GID // 空值代表全部已註冊遊戲（Problab.IDs()）
+	BetModes []int      // 空值代表該遊戲的全部 bet mode；任一 mode 超出該遊戲範圍時回傳錯誤
+	Rounds   int        // 每個組合的局數（workers > 1 時為每個 worker 的局數，與 SimMP 一致）
+	Workers  int        // 每個組合的併發機台數
+	Seed     int64      // 批次基準種子
+}
+
+// MatrixSeed 回傳 (baseSeed, gid, betMode) 對應的模擬種子
+//
+// 種子只取決於三者本身，與批次中其他組合無關：增減遊戲或 mode 不會改變既有組合的結果，
+// 且可直接以 cmd/run -seed 重現單一組合。
+func MatrixSeed(baseSeed int64, gid spec.GID, betMode int) int64 {
+	key := uint64(baseSeed) ^ mix63(uint64(gid)<<20|uint64(betMode)&0xFFFFF)
+	return NewSeedMaker(int64(key & mask63)).Next()
+}
+
+// SimMatrix 依序執行所有 game × bet mode 組合並彙整成矩陣報表
+//
+// 執行順序固定（GID 依 plan 順序，mode 由小到大），報表順序與之相同。
+func (p *Problab) SimMatrix(plan MatrixPlan) (*stats.MatrixReport, error) {
+	if plan.Rounds < 1 {
+		return nil, errs.NewWarn("round must > 0")
+	}
+	if plan.Workers < 1 {
+		return nil, errs.NewWarn("workers must > 0")
+	}
+	gids := plan.GIDs
+	if len(gids) == 0 {
+		gids = p.IDs()
+	}
+	report := &stats.MatrixReport{Cells: make([]stats.MatrixCell, 0, len(gids))}
+	for _, gid := range gids {
+		gs, err := p.cat.GameSettingById(gid)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Sprintf("sim matrix err: gid %d", gid))
+		}
+		modes := plan.BetModes
+		if len(modes) == 0 {
+			modes = make([]int, len(gs.BetUnits))
+			for i := range modes {
+				modes[i] = i
+			}
+		}
+		for _, mode := range modes {
+			if mode < 0 || mode >= len(gs.BetUnits) {
+				return nil, errs.NewWarn(fmt.Sprintf("sim matrix err: gid %d has no bet mode %d (bet modes: 0..%d)", gid, mode, len(gs.BetUnits)-1))
+			}
+			seed := MatrixSeed(plan.Seed, gid, mode)
+			sim, err := p.NewSimulatorWithSeed(gid, seed)
+			if err != nil {
+				return nil, errs.Wrap(err, fmt.Sprintf("build simulator err: %d", gid))
+			}
+			var st *stats.StatReport
+			if plan.Workers == 1 {
+				st, _, err = sim.Sim(mode, plan.Rounds, false)
+			} else {
+				st, _, err = sim.SimMP(mode, plan.Rounds, plan.Workers, false)
+			}
+			if err != nil {
+				return nil, errs.Wrap(err, fmt.Sprintf("sim matrix err: gid %d mode %d", gid, mode))
+			}
+			report.Cells = append(report.Cells, stats.NewMatrixCell(st, seed))
+		}
+	}
+	if len(report.Cells) == 0 {
+		return nil, errs.NewWarn("sim matrix err: no game × bet mode combination to run")
+	}
+	return report, nil
+}
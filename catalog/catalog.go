@@ -17,7 +17,6 @@ package catalog
 import (
 	"fmt"
 	"io/fs"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -26,30 +25,52 @@ import (
 )
 
 var (
-	ErrDupID   = errs.NewFatal("duplicate game id")
-	ErrDupName = errs.NewFatal("duplicate game name")
+	ErrDupID      = errs.NewFatal("duplicate game id")
+	ErrDupName    = errs.NewFatal("duplicate game name")
+	ErrDupVariant = errs.NewFatal("duplicate game variant")
+	ErrNoDefault  = errs.NewFatal("missing default game variant")
 )
 
+// Entry 一個可遊玩的遊戲版本。
+//
+// 同一個 GID 可以註冊多個變體（Variant，例如 92% / 94% / 96% 的 RTP 版本），
+// 以 (GID, Variant) 唯一識別；同 GID 的所有變體必須使用相同的遊戲名稱，
+// 且必須有一個 Variant 為空字串的預設變體（否則 Register 回傳 ErrNoDefault）。
 type Entry struct {
 	GID        spec.GID
 	Name       string
 	ConfigName string
+	Variant    string  // 變體名稱（空字串為預設版本）
+	RTP        float64 // 設定的目標 RTP（僅供列舉）
+	// Fingerprint 設定檔指紋（註冊時計算；含優化文件雜湊），空字串表示未計算
+	Fingerprint string
 }
 
 type Summary struct {
-	GID      spec.GID      `json:"gid"`
-	Name     string        `json:"name"`
-	Logic    spec.LogicKey `json:"logic"`
-	BetUnits []int         `json:"bet_units"`
+	GID         spec.GID         `json:"gid"`
+	Name        string           `json:"name"`
+	Logic       spec.LogicKey    `json:"logic"`
+	BetUnits    []int            `json:"bet_units"`
+	Fingerprint string           `json:"fingerprint"` // 預設變體的指紋
+	Variants    []VariantSummary `json:"variants"`    // 所有變體（預設變體在最前）
+}
+
+// VariantSummary 單一變體的摘要
+type VariantSummary struct {
+	Variant     string  `json:"variant"`
+	RTP         float64 `json:"rtp"`
+	Fingerprint string  `json:"fingerprint"`
+	Default     bool    `json:"default,omitempty"`
 }
 
 type Catalog struct {
-	byID   map[spec.GID]Entry
-	byName map[string]Entry
-	ids    []spec.GID          // 用來穩定排序
-	unique map[string]struct{} // 一組遊戲，檔名需唯一
-	config *multiFS
-	frozen bool
+	byID     map[spec.GID]Entry   // 每個 GID 的預設變體
+	byName   map[string]Entry     // 每個名稱的預設變體
+	variants map[spec.GID][]Entry // 每個 GID 的全部變體（依 Variant 排序，預設變體在最前）
+	ids      []spec.GID           // 用來穩定排序
+	unique   map[string]struct{}  // 一組遊戲，設定檔路徑需唯一
+	config   *multiFS
+	frozen   bool
 }
 
 func New(cfg ...fs.FS) (*Catalog, error) {
@@ -58,71 +79,135 @@ func New(cfg ...fs.FS) (*Catalog, error) {
 		return nil, errs.Wrap(err, "can not create catalog")
 	}
 	return &Catalog{
-		byID:   map[spec.GID]Entry{},
-		byName: map[string]Entry{},
-		ids:    make([]spec.GID, 0, 100),
-		unique: map[string]struct{}{},
-		config: multFS,
-		frozen: false,
+		byID:     map[spec.GID]Entry{},
+		byName:   map[string]Entry{},
+		variants: map[spec.GID][]Entry{},
+		ids:      make([]spec.GID, 0, 100),
+		unique:   map[string]struct{}{},
+		config:   multFS,
+		frozen:   false,
 	}, nil
 }
 
+// variantKey (GID, Variant) 唯一鍵
+type variantKey struct {
+	gid     spec.GID
+	variant string
+}
+
 func (c *Catalog) Register(metas ...Entry) error {
 	if c.frozen {
 		return errs.NewWarn("can not register when catalog already frozen")
 	}
-	seenID := map[spec.GID]struct{}{}
-	seenName := map[string]struct{}{}
+	seenKey := map[variantKey]struct{}{}
+	seenName := map[string]spec.GID{} // 名稱 -> GID（同 GID 的變體共用名稱）
+	seenGID := map[spec.GID]string{}  // GID -> 名稱
 	seenCfg := map[string]struct{}{}
-	for _, meta := range metas {
+	for i := range metas {
+		meta := &metas[i]
 		meta.Name = strings.TrimSpace(meta.Name)
 		meta.Name = strings.ToLower(meta.Name)
+		meta.Variant = strings.TrimSpace(meta.Variant)
 		if meta.Name == "" {
 			return errs.NewFatal("game name required")
 		}
-		if err := validFileName(meta.ConfigName); err != nil {
+		if err := validConfigPath(meta.ConfigName); err != nil {
 			return err
 		}
 		if _, ok := c.config.index[meta.ConfigName]; !ok {
 			return errs.NewFatal(fmt.Sprintf("config file not found: %s", meta.ConfigName))
 		}
-		if _, ok := c.byID[meta.GID]; ok {
-			return ErrDupID
+		key := variantKey{gid: meta.GID, variant: meta.Variant}
+		if _, ok := c.GetVariant(meta.GID, meta.Variant); ok {
+			return variantErr(meta)
+		}
+		if _, ok := seenKey[key]; ok {
+			return variantErr(meta)
 		}
-		if _, ok := c.byName[meta.Name]; ok {
+		// 名稱與 GID 必須一一對應（跨變體共用）
+		if e, ok := c.byName[meta.Name]; ok && e.GID != meta.GID {
 			return ErrDupName
 		}
-		if _, ok := c.unique[meta.ConfigName]; ok {
-			return errs.NewFatal(fmt.Sprintf("duplicate config name: %s", meta.ConfigName))
+		if gid, ok := seenName[meta.Name]; ok && gid != meta.GID {
+			return ErrDupName
 		}
-		if _, ok := seenID[meta.GID]; ok {
-			return ErrDupID
+		if e, ok := c.byID[meta.GID]; ok && e.Name != meta.Name {
+			return errs.NewFatal(fmt.Sprintf("game name mismatch across variants: gid %d has %q and %q", meta.GID, e.Name, meta.Name))
 		}
-		if _, ok := seenName[meta.Name]; ok {
-			return ErrDupName
+		if name, ok := seenGID[meta.GID]; ok && name != meta.Name {
+			return errs.NewFatal(fmt.Sprintf("game name mismatch across variants: gid %d has %q and %q", meta.GID, name, meta.Name))
+		}
+		if _, ok := c.unique[meta.ConfigName]; ok {
+			return errs.NewFatal(fmt.Sprintf("duplicate config name: %s", meta.ConfigName))
 		}
 		if _, ok := seenCfg[meta.ConfigName]; ok {
 			return errs.NewFatal(fmt.Sprintf("duplicate config name: %s", meta.ConfigName))
 		}
-		seenID[meta.GID] = struct{}{}
-		seenName[meta.Name] = struct{}{}
+		seenKey[key] = struct{}{}
+		seenName[meta.Name] = meta.GID
+		seenGID[meta.GID] = meta.Name
 		seenCfg[meta.ConfigName] = struct{}{}
 	}
+	// 每個 GID 必須有明確的預設變體（Variant 為空字串），預設版本不可取決於變體名稱的排序
+	for _, meta := range metas {
+		if _, ok := seenKey[variantKey{gid: meta.GID}]; ok {
+			continue
+		}
+		if _, ok := c.byID[meta.GID]; !ok {
+			return errs.Wrap(ErrNoDefault, fmt.Sprintf("gid %d has variant %q but no default variant (variant \"\")", meta.GID, meta.Variant))
+		}
+	}
 	for _, meta := range metas {
 		c.unique[meta.ConfigName] = struct{}{}
-		c.byID[meta.GID] = meta
-		c.byName[meta.Name] = meta
-		c.ids = append(c.ids, meta.GID)
+		vs, ok := c.variants[meta.GID]
+		if !ok {
+			c.ids = append(c.ids, meta.GID)
+		}
+		vs = append(vs, meta)
+		sort.Slice(vs, func(i, j int) bool { return vs[i].Variant < vs[j].Variant })
+		c.variants[meta.GID] = vs
+		c.byID[meta.GID] = vs[0]
+		c.byName[meta.Name] = vs[0]
 	}
 	sort.Slice(c.ids, func(i, j int) bool { return c.ids[i] < c.ids[j] })
 	return nil
 }
 
+// variantErr 重複的 (GID, Variant)：預設變體沿用 ErrDupID
+func variantErr(meta *Entry) error {
+	if meta.Variant == "" {
+		return ErrDupID
+	}
+	return errs.Wrap(ErrDupVariant, fmt.Sprintf("duplicate game variant: gid %d variant %q", meta.GID, meta.Variant))
+}
+
 func (c *Catalog) GetByID(id spec.GID) (Entry, bool) {
 	m, ok := c.byID[id]
 	return m, ok
 }
 
+// GetVariant 取得指定變體；variant 為空字串時回傳預設變體
+func (c *Catalog) GetVariant(id spec.GID, variant string) (Entry, bool) {
+	if variant == "" {
+		return c.GetByID(id)
+	}
+	for _, e := range c.variants[id] {
+		if e.Variant == variant {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Variants 回傳指定 GID 的全部變體（預設變體在最前）
+func (c *Catalog) Variants(id spec.GID) []Entry {
+	vs := c.variants[id]
+	if len(vs) == 0 {
+		return nil
+	}
+	return append([]Entry(nil), vs...)
+}
+
 func (c *Catalog) GetByName(name string) (Entry, bool) {
 	name = strings.TrimSpace(name)
 	name = strings.ToLower(name)
@@ -160,37 +245,30 @@ func (c *Catalog) IsFrozen() bool {
 	return c.frozen
 }
 
-func validFileName(file string) error {
+// validConfigPath 檢查設定檔路徑：相對於來源根目錄、以 / 分隔（目錄即命名空間），
+// 每一段不得以 . 開頭，且必須以 .yaml/.yml/.json 結尾。
+func validConfigPath(file string) error {
 	if file == "" {
 		return errs.NewFatal("empty config filename")
 	}
-	// 1) 不能包含路徑或類似字元
-	if strings.ContainsAny(file, `/\:`) {
-		return errs.NewFatal(fmt.Sprintf("invalid config filename: %q (must be a basename; no / \\\\ :) ", file))
+	// 1) 必須是合法的 fs 路徑（不含 ..、不以 / 開頭），且不含 \ 或 :
+	if !fs.ValidPath(file) || file == "." || strings.ContainsAny(file, `\:`) {
+		return errs.NewFatal(fmt.Sprintf("invalid config path: %q (must be a relative slash path; no .. \\ :)", file))
 	}
 	// 2) 必須以 .yaml/.yml/.json 結尾（大小寫不敏感）
 	lower := strings.ToLower(file)
 	if !(strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml") || strings.HasSuffix(lower, ".json")) {
 		return errs.NewFatal(fmt.Sprintf("invalid config filename: %q (must end with .yaml, .yml, or .json)", file))
 	}
-	// 3) 不能以 . 開頭（防止直接 .yaml / .yml）
-	if strings.HasPrefix(file, ".") {
-		return errs.NewFatal(fmt.Sprintf("invalid config filename: %q (cannot start with '.')", file))
+	// 3) 每一段都不能以 . 開頭（防止直接 .yaml / 隱藏目錄）
+	for _, seg := range strings.Split(file, "/") {
+		if strings.HasPrefix(seg, ".") {
+			return errs.NewFatal(fmt.Sprintf("invalid config path: %q (segments cannot start with '.')", file))
+		}
 	}
 	return nil
 }
 
-func parseGameSettingByExt(filename string, raw []byte) (*spec.GameSetting, error) {
-	switch strings.ToLower(filepath.Ext(filename)) {
-	case ".yaml", ".yml":
-		return spec.GetGameSettingByYAML(raw)
-	case ".json":
-		return spec.GetGameSettingByJSON(raw)
-	default:
-		return nil, errs.NewFatal(fmt.Sprintf("unsupported config format: %q", filename))
-	}
-}
-
 // GameSettingById
 //
 // 會讀取 fs.FS 中的 YAML/JSON 設定、初始化各子設定並執行基本檢查後回傳
@@ -199,15 +277,25 @@ func (c *Catalog) GameSettingById(id spec.GID) (*spec.GameSetting, error) {
 	if !ok {
 		return nil, errs.NewWarn("id dose not exist in catalog")
 	}
+	return c.GameSettingByEntry(e)
+}
+
+// GameSettingByVariant 與 GameSettingById 相同，但讀取指定變體（空字串為預設變體）
+func (c *Catalog) GameSettingByVariant(id spec.GID, variant string) (*spec.GameSetting, error) {
+	e, ok := c.GetVariant(id, variant)
+	if !ok {
+		return nil, errs.NewWarn(fmt.Sprintf("variant dose not exist in catalog: gid %d variant %q", id, variant))
+	}
+	return c.GameSettingByEntry(e)
+}
+
+// GameSettingByEntry 讀取 Entry 對應的設定檔
+func (c *Catalog) GameSettingByEntry(e Entry) (*spec.GameSetting, error) {
 	src, ok := c.config.GetFS(e.ConfigName)
 	if !ok {
 		return nil, errs.NewWarn("file name dose not exist in catalog")
 	}
-	raw, err := fs.ReadFile(src, e.ConfigName)
-	if err != nil {
-		return nil, errs.Wrap(err, "catalog parse file error")
-	}
-	return parseGameSettingByExt(e.ConfigName, raw)
+	return spec.GetGameSettingFromFS(src, e.ConfigName)
 }
 
 // GameSettingByName
@@ -222,11 +310,7 @@ func (c *Catalog) GameSettingByName(name string) (*spec.GameSetting, error) {
 	if !ok {
 		return nil, errs.NewWarn("file name dose not exist in catalog")
 	}
-	raw, err := fs.ReadFile(src, e.ConfigName)
-	if err != nil {
-		return nil, errs.Wrap(err, "catalog parse file error")
-	}
-	return parseGameSettingByExt(e.ConfigName, raw)
+	return spec.GetGameSettingFromFS(src, e.ConfigName)
 }
 
 type multiFS struct {
@@ -255,20 +339,16 @@ func newMultiFS(src ...fs.FS) (*multiFS, error) {
 			if err != nil {
 				return err
 			}
-			if d.IsDir() {
-				// Problab configs are intentionally required to be a *flat* directory.
-				// Only the root "." is allowed. Any subdirectory presence is a contract violation,
-				// even if it contains no yaml/json files.
-				if path == "." {
-					return nil
+			// Subdirectories act as namespaces (e.g. "studio_a/game_12.yaml"); the slash path
+			// relative to the FS root is the config name. Hidden entries are skipped.
+			if path != "." && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return fs.SkipDir
 				}
-				return errs.NewFatal(fmt.Sprintf("config FS must be flat (no subdirectories): %q", path))
+				return nil
 			}
-
-			// WalkDir may return paths like "demo/game_0.yaml" when subdirectories exist.
-			// Even though we already reject subdirectories above, keep this check as a defensive guard.
-			if strings.Contains(path, "/") {
-				return errs.NewFatal(fmt.Sprintf("config FS must be flat (no subdirectories): %q", path))
+			if d.IsDir() {
+				return nil
 			}
 
 			// Only index yaml/json configs; ignore any other assets that may exist in the FS.
@@ -277,7 +357,7 @@ func newMultiFS(src ...fs.FS) (*multiFS, error) {
 				return nil
 			}
 
-			name := path // flat FS guarantees path is a basename
+			name := path
 
 			if prev, ok := m.index[name]; ok {
 				// duplicate across FS: fail fast
@@ -0,0 +1,117 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zintix-labs/problab/catalog"
+)
+
+func TestRegisterVariants(t *testing.T) {
+	cfg := fstest.MapFS{
+		"a.yaml":     {Data: []byte("game_id: 1")},
+		"a_92.yaml":  {Data: []byte("game_id: 1")},
+		"a_96.yaml":  {Data: []byte("game_id: 1")},
+		"b.yaml":     {Data: []byte("game_id: 2")},
+		"dup.yaml":   {Data: []byte("game_id: 1")},
+		"other.yaml": {Data: []byte("game_id: 3")},
+	}
+	c, err := catalog.New(cfg)
+	if err != nil {
+		t.Fatalf("new catalog failed: %v", err)
+	}
+	err = c.Register(
+		catalog.Entry{GID: 1, Name: "Alpha", ConfigName: "a_96.yaml", Variant: "rtp96", RTP: 0.96},
+		catalog.Entry{GID: 1, Name: "alpha", ConfigName: "a.yaml"},
+		catalog.Entry{GID: 1, Name: "alpha", ConfigName: "a_92.yaml", Variant: "rtp92", RTP: 0.92},
+		catalog.Entry{GID: 2, Name: "beta", ConfigName: "b.yaml"},
+	)
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if ids := c.IDs(); len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("variants must share one id: %v", ids)
+	}
+	vs := c.Variants(1)
+	if len(vs) != 3 || vs[0].Variant != "" || vs[1].Variant != "rtp92" || vs[2].Variant != "rtp96" {
+		t.Fatalf("unexpected variants order: %+v", vs)
+	}
+	if e, ok := c.GetByID(1); !ok || e.ConfigName != "a.yaml" {
+		t.Fatalf("default variant must be the unnamed one: %+v", e)
+	}
+	if e, ok := c.GetByName("ALPHA"); !ok || e.Variant != "" {
+		t.Fatalf("name lookup must return default variant: %+v", e)
+	}
+	if e, ok := c.GetVariant(1, "rtp92"); !ok || e.ConfigName != "a_92.yaml" || e.RTP != 0.92 {
+		t.Fatalf("unexpected variant: %+v", e)
+	}
+	if _, ok := c.GetVariant(1, "rtp90"); ok {
+		t.Fatalf("unknown variant must not be found")
+	}
+
+	err = c.Register(catalog.Entry{GID: 1, Name: "alpha", ConfigName: "dup.yaml", Variant: "rtp92"})
+	if !errors.Is(err, catalog.ErrDupVariant) {
+		t.Fatalf("expected duplicate variant error, got %v", err)
+	}
+	if err := c.Register(catalog.Entry{GID: 1, Name: "gamma", ConfigName: "other.yaml", Variant: "x"}); err == nil {
+		t.Fatalf("expected name mismatch across variants")
+	}
+	if err := c.Register(catalog.Entry{GID: 3, Name: "beta", ConfigName: "other.yaml"}); !errors.Is(err, catalog.ErrDupName) {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+	// 沒有預設變體時不得以排序最前的變體充當預設
+	if err := c.Register(catalog.Entry{GID: 3, Name: "gamma", ConfigName: "other.yaml", Variant: "a"}); !errors.Is(err, catalog.ErrNoDefault) {
+		t.Fatalf("expected missing default variant error, got %v", err)
+	}
+	if _, ok := c.GetByID(3); ok {
+		t.Fatalf("failed register must not add entries")
+	}
+}
+
+func TestNestedConfigPaths(t *testing.T) {
+	cfg := fstest.MapFS{
+		"studio_a/game.yaml":  {Data: []byte("game_id: 1")},
+		"studio_b/game.yaml":  {Data: []byte("game_id: 2")},
+		".cache/game.yaml":    {Data: []byte("game_id: 3")},
+		"studio_b/readme.txt": {Data: []byte("ignored")},
+	}
+	c, err := catalog.New(cfg)
+	if err != nil {
+		t.Fatalf("new catalog failed: %v", err)
+	}
+	err = c.Register(
+		catalog.Entry{GID: 1, Name: "a", ConfigName: "studio_a/game.yaml"},
+		catalog.Entry{GID: 2, Name: "b", ConfigName: "studio_b/game.yaml"},
+	)
+	if err != nil {
+		t.Fatalf("same filename in different namespaces must be allowed: %v", err)
+	}
+	if _, ok := c.Cfg().GetFS("studio_b/game.yaml"); !ok {
+		t.Fatalf("nested config must be indexed by its path")
+	}
+	for _, bad := range []string{".cache/game.yaml", "../game.yaml", "/studio_a/game.yaml", `studio_a\game.yaml`} {
+		if err := c.Register(catalog.Entry{GID: 9, Name: "x", ConfigName: bad}); err == nil {
+			t.Fatalf("expected error for config path %q", bad)
+		}
+	}
+
+	dup := fstest.MapFS{"studio_a/game.yaml": {Data: []byte("game_id: 1")}}
+	if _, err := catalog.New(cfg, dup); err == nil {
+		t.Fatalf("expected duplicate path across sources")
+	}
+}
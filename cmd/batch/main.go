@@ -0,0 +1,178 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/demo"
+	"github.com/zintix-labs/problab/spec"
+	"github.com/zintix-labs/problab/stats"
+	"gopkg.in/yaml.v3"
+)
+
+// 批次模擬：games × modes 矩陣
+//
+// 範例：
+//
+//	go run ./cmd/batch -games all -modes all -spins 1000000 -tol tol.yaml
+//
+// tol.yaml：
+//
+//	default: {rtp: 0.965, tolerance: 0.01}
+//	games:
+//	  0: {rtp: 0.965, tolerance: 0.005}
+//
+// 任一組合 RTP 超出容許範圍時以 exit code 1 結束。
+func main() {
+	bindVar()
+
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		log.Fatal(err)
+	}
+	plan := problab.MatrixPlan{
+		Rounds:  cfg.spins,
+		Workers: cfg.worker,
+		Seed:    cfg.seed,
+	}
+	if plan.GIDs, err = parseGIDs(cfg.games); err != nil {
+		log.Fatal(err)
+	}
+	if plan.BetModes, err = parseModes(cfg.modes); err != nil {
+		log.Fatal(err)
+	}
+	tol, err := loadTolerance(cfg.tol)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("[BATCH] [SEED:%d] [SPINS:%d] [WORKERS:%d]\n", cfg.seed, cfg.spins, cfg.worker)
+	mr, err := lab.SimMatrix(plan)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mr.Check(tol.Games, tol.Default)
+
+	switch cfg.out {
+	case "json":
+		err = mr.WriteWith(os.Stdout, &stats.JsonMatrixReportRender{})
+	case "yaml":
+		err = mr.WriteWith(os.Stdout, &stats.YAMLMatrixReportRender{})
+	default:
+		mr.StdOut()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if failed := mr.Failed(); failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d combinations out of RTP tolerance\n", failed, len(mr.Cells))
+		os.Exit(1)
+	}
+}
+
+var cfg *config = new(config)
+
+type config struct {
+	games  string
+	modes  string
+	spins  int
+	worker int
+	seed   int64
+	tol    string
+	out    string
+}
+
+func bindVar() {
+	flag.StringVar(&cfg.games, "games", "all", "comma separated game ids or 'all'")
+	flag.StringVar(&cfg.modes, "modes", "all", "comma separated bet mode indexes or 'all'")
+	flag.IntVar(&cfg.spins, "spins", 1000000, "spins per game x mode (per worker)")
+	flag.IntVar(&cfg.worker, "worker", 1, "number of workers per game x mode")
+	flag.Int64Var(&cfg.seed, "seed", -1, "int64 base seed")
+	flag.StringVar(&cfg.tol, "tol", "", "rtp tolerance yaml file")
+	flag.StringVar(&cfg.out, "out", "table", "output: table|json|yaml")
+
+	flag.Parse()
+
+	if cfg.seed < 1 {
+		seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.seed = seed.Int64()
+	}
+}
+
+// tolerance RTP 容許範圍設定檔
+type tolerance struct {
+	Default *stats.RTPTolerance             `yaml:"default"`
+	Games   map[spec.GID]stats.RTPTolerance `yaml:"games"`
+}
+
+func loadTolerance(path string) (*tolerance, error) {
+	t := new(tolerance)
+	if path == "" {
+		return t, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(raw, t); err != nil {
+		return nil, fmt.Errorf("parse tolerance file %s: %w", path, err)
+	}
+	return t, nil
+}
+
+func parseGIDs(s string) ([]spec.GID, error) {
+	if s == "" || s == "all" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]spec.GID, 0, len(parts))
+	for _, p := range parts {
+		u, err := strconv.ParseUint(strings.TrimSpace(p), 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid game id %q", p)
+		}
+		out = append(out, spec.GID(uint(u)))
+	}
+	return out, nil
+}
+
+func parseModes(s string) ([]int, error) {
+	if s == "" || s == "all" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]int, 0, len(parts))
+	for _, p := range parts {
+		m, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || m < 0 {
+			return nil, fmt.Errorf("invalid bet mode %q", p)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
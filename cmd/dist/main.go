@@ -0,0 +1,159 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/zintix-labs/problab/demo"
+	"github.com/zintix-labs/problab/distsim"
+	"github.com/zintix-labs/problab/spec"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// 分散式模擬協調者
+//
+//   - 預設：以自身執行檔（-serve 模式）啟動 local 個本機子行程作為 Worker
+//   - -remote：額外（或僅）使用既有 server（dev 模式）的 /v1/simshard 作為 Worker
+//   - -serve：子行程模式，自 stdin 讀一個分片，結果寫到 stdout
+func main() {
+	bindVar()
+	if cfg.serve {
+		serve()
+		return
+	}
+	coordinate()
+}
+
+var cfg *config = new(config)
+
+type config struct {
+	id      spec.GID
+	betMode int
+	spins   int
+	shards  int
+	local   int
+	remote  string
+	retries int
+	seed    int64
+	serve   bool
+}
+
+type gidFlag struct{ p *spec.GID }
+
+func (f gidFlag) String() string { return fmt.Sprint(uint(*f.p)) }
+func (f gidFlag) Set(s string) error {
+	u, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		return err
+	}
+	*f.p = spec.GID(uint(u))
+	return nil
+}
+
+func bindVar() {
+	flag.Var(gidFlag{&cfg.id}, "game", "target game id")
+	flag.IntVar(&cfg.betMode, "mode", 0, "bet mode index")
+	flag.IntVar(&cfg.spins, "spins", 10000000, "total spins")
+	flag.IntVar(&cfg.shards, "shards", 0, "number of seeded shards (default: 4 x workers)")
+	flag.IntVar(&cfg.local, "local", runtime.NumCPU(), "number of local worker processes")
+	flag.StringVar(&cfg.remote, "remote", "", "comma separated server base urls, e.g. http://localhost:5808")
+	flag.IntVar(&cfg.retries, "retries", 2, "max retries per failed shard")
+	flag.Int64Var(&cfg.seed, "seed", -1, "int64 base seed")
+	flag.BoolVar(&cfg.serve, "serve", false, "worker mode: read one shard from stdin and write the record to stdout")
+
+	flag.Parse()
+
+	if cfg.seed < 1 && !cfg.serve {
+		seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.seed = seed.Int64()
+	}
+}
+
+func serve() {
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := distsim.ServeStdio(lab, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func coordinate() {
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ent, ok := lab.EntryById(cfg.id)
+	if !ok {
+		log.Fatalf("gid not found: %d", cfg.id)
+	}
+
+	workers := make([]distsim.Worker, 0, cfg.local)
+	if cfg.local > 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for range cfg.local {
+			workers = append(workers, &distsim.ProcessWorker{Path: exe, Args: []string{"-serve"}})
+		}
+	}
+	for _, u := range strings.Split(cfg.remote, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			workers = append(workers, &distsim.HTTPWorker{BaseURL: u})
+		}
+	}
+	coord, err := distsim.NewCoordinator(cfg.retries, workers...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shards := cfg.shards
+	if shards < 1 {
+		shards = 4 * len(workers)
+	}
+	plan, err := distsim.Plan(cfg.id, cfg.betMode, cfg.spins, shards, cfg.seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	green := "\033[1;32m"
+	reset := "\033[0m"
+	p := message.NewPrinter(language.English)
+	p.Printf("%s[WORKERS:%d] [SHARDS:%d] [GAME:%s] [PLAYMODE:%d] [SPINS:%d] [SEED:%d]%s\n", green, len(workers), shards, ent.Name, cfg.betMode, cfg.spins, cfg.seed, reset)
+
+	st, used, err := coord.Run(context.Background(), plan)
+	if err != nil {
+		log.Fatal(err)
+	}
+	st.StdOut(used)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/demo"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// PAR 表輸出
+//
+// 範例：
+//
+//	go run ./cmd/par -game 0 -mode 0 -spins 10000000 -worker 8 -seed 1 -o game_0_par.csv
+//
+// 輸出為分段 CSV（每段以 "# 標題" 開頭、段落間空一列），可直接以試算表開啟。
+func main() {
+	game := flag.Int("game", 0, "target game id")
+	mode := flag.Int("mode", 0, "bet mode index")
+	spins := flag.Int("spins", 1000000, "spins per worker")
+	worker := flag.Int("worker", 1, "number of workers")
+	seed := flag.Int64("seed", 1, "simulation seed")
+	out := flag.String("o", "", "output csv file (default stdout)")
+	flag.Parse()
+
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		log.Fatal(err)
+	}
+	sheet, err := lab.PARSheet(problab.PARPlan{
+		GID:     spec.GID(*game),
+		BetMode: *mode,
+		Rounds:  *spins,
+		Workers: *worker,
+		Seed:    *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := sheet.WriteCSV(w); err != nil {
+		log.Fatal(err)
+	}
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "par sheet written: %s\n", *out)
+	}
+}
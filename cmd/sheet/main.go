@@ -0,0 +1,218 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// 輪帶 / 賠付表與試算表 CSV 互轉
+//
+//	go run ./cmd/sheet export -config game_0.yaml -mode 0 -set 0 -reels reels.csv -pay pay.csv
+//	go run ./cmd/sheet import -config game_0.yaml -mode 0 -set 0 -reels reels.csv -pay pay.csv -w
+//	go run ./cmd/sheet import -config game_0.yaml -mode 0 -lib base -reels reels.csv -w   # 寫入 library.reel_sets.base
+//
+// 圖標名稱依指定模式的 symbol_used（或 symbols）驗證。import 會在寫入前對修改後的設定執行完整驗證，
+// 有 Fatal 問題時不寫入；未指定 -w 時輸出到 stdout。import 只改寫有變化的位元組（排版與註解保留），
+// library 區塊若定義在 includes 的檔案中，改寫的是該檔案。
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd := os.Args[1]
+	fsFlags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	config := fsFlags.String("config", "", "game setting yaml")
+	mode := fsFlags.Int("mode", 0, "game mode index (symbols are validated against this mode)")
+	set := fsFlags.Int("set", 0, "reel set index in reel_set_group")
+	lib := fsFlags.String("lib", "", "library reel set name (instead of -set)")
+	reels := fsFlags.String("reels", "", "reels csv file")
+	pay := fsFlags.String("pay", "", "pay table csv file")
+	write := fsFlags.Bool("w", false, "import: write result back to -config")
+	_ = fsFlags.Parse(os.Args[2:])
+	if *config == "" || (*reels == "" && *pay == "") {
+		usage()
+	}
+
+	gs, err := spec.GetGameSettingFromFS(os.DirFS(filepath.Dir(*config)), filepath.Base(*config))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *mode < 0 || *mode >= len(gs.GameModeSettings) {
+		log.Fatalf("mode %d out of range", *mode)
+	}
+	m := &gs.GameModeSettings[*mode]
+
+	switch cmd {
+	case "export":
+		if *reels != "" {
+			rs, err := reelSet(gs, m, *set, *lib)
+			if err != nil {
+				log.Fatal(err)
+			}
+			writeFile(*reels, func(w io.Writer) error { return spec.WriteReelsCSV(w, rs.Reels, &m.SymbolSetting) })
+		}
+		if *pay != "" {
+			writeFile(*pay, func(w io.Writer) error { return spec.WritePayTableCSV(w, &m.SymbolSetting) })
+		}
+	case "import":
+		dir, base := filepath.Dir(*config), filepath.Base(*config)
+		fsLoad := spec.FSLoader(os.DirFS(dir))
+		orig := map[string][]byte{}
+		files := map[string][]byte{} // 來源路徑 -> 修改後內容（library 區塊可能定義在 includes 的檔案）
+		load := func(name string) ([]byte, error) {
+			if raw, ok := files[name]; ok {
+				return raw, nil
+			}
+			return fsLoad(name)
+		}
+		patch := func(file, path string, value any) {
+			raw, err := load(file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if _, ok := orig[file]; !ok {
+				orig[file] = raw
+			}
+			if files[file], err = spec.PatchYAML(raw, path, value); err != nil {
+				log.Fatalf("%s: %v", file, err)
+			}
+		}
+		cfgRaw, err := os.ReadFile(*config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files[base] = cfgRaw
+		orig[base] = cfgRaw
+		libFile := func(section, name string) string {
+			file, err := spec.LibraryFile(cfgRaw, base, fsLoad, section, name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return file
+		}
+		if *reels != "" {
+			rs, err := readWith(*reels, func(r io.Reader) ([]spec.Reel, error) { return spec.ReadReelsCSV(r, &m.SymbolSetting) })
+			if err != nil {
+				log.Fatal(err)
+			}
+			if *lib != "" {
+				patch(libFile("reel_sets", *lib), fmt.Sprintf("library.reel_sets.%s.reels", *lib), rs)
+			} else if *set < len(m.GenScreenSetting.ReelSetGroup) && m.GenScreenSetting.ReelSetGroup[*set].Ref != "" {
+				log.Fatalf("reel set %d references library %q; use -lib %s", *set, m.GenScreenSetting.ReelSetGroup[*set].Ref, m.GenScreenSetting.ReelSetGroup[*set].Ref)
+			} else {
+				patch(base, fmt.Sprintf("game_mode_settings[%d].gen_screen_setting.reel_set_group[%d].reels", *mode, *set), rs)
+			}
+		}
+		if *pay != "" {
+			pt, err := readWith(*pay, func(r io.Reader) ([][]int, error) { return spec.ReadPayTableCSV(r, &m.SymbolSetting) })
+			if err != nil {
+				log.Fatal(err)
+			}
+			if ref := m.SymbolSetting.PayTableRef; ref != "" {
+				patch(libFile("pay_tables", ref), fmt.Sprintf("library.pay_tables.%s", ref), pt)
+			} else {
+				patch(base, fmt.Sprintf("game_mode_settings[%d].symbol_setting.pay_table", *mode), pt)
+			}
+		}
+		fatal := false
+		for _, ci := range spec.ValidateConfig(files[base], base, load, nil) {
+			ci.File = *config
+			fmt.Fprintln(os.Stderr, ci.String())
+			fatal = fatal || ci.Level == errs.Fatal
+		}
+		if fatal {
+			log.Fatal("patched config is invalid, nothing written")
+		}
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			raw := files[name]
+			if !*write {
+				if len(files) > 1 {
+					fmt.Printf("# --- %s\n", name)
+				}
+				os.Stdout.Write(raw)
+				continue
+			}
+			file := filepath.Join(dir, filepath.FromSlash(name))
+			if bytes.Equal(raw, orig[name]) {
+				fmt.Fprintf(os.Stderr, "unchanged %s\n", file)
+				continue
+			}
+			if err := os.WriteFile(file, raw, 0o644); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Fprintf(os.Stderr, "updated %s\n", file)
+		}
+	default:
+		usage()
+	}
+}
+
+func reelSet(gs *spec.GameSetting, m *spec.GameModeSetting, set int, lib string) (*spec.ReelSet, error) {
+	if lib != "" {
+		rs, ok := gs.Library.ReelSets[lib]
+		if !ok {
+			return nil, errs.NewFatal(fmt.Sprintf("library reel set not found: %s", lib))
+		}
+		return &rs, nil
+	}
+	if set < 0 || set >= len(m.GenScreenSetting.ReelSetGroup) {
+		return nil, errs.NewFatal(fmt.Sprintf("reel set %d out of range", set))
+	}
+	return &m.GenScreenSetting.ReelSetGroup[set], nil
+}
+
+func readWith[T any](file string, fn func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return zero, err
+	}
+	v, err := fn(bytes.NewReader(raw))
+	if err != nil {
+		return zero, errs.Wrap(err, file)
+	}
+	return v, nil
+}
+
+func writeFile(file string, fn func(io.Writer) error) {
+	var b bytes.Buffer
+	if err := fn(&b); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(file, b.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", file)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: sheet export|import -config game.yaml [-mode 0] [-set 0 | -lib name] [-reels reels.csv] [-pay pay.csv] [-w]")
+	os.Exit(2)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/zintix-labs/problab/demo/demo_configs"
 	"github.com/zintix-labs/problab/demo/demo_logic"
 	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/flow"
 	"github.com/zintix-labs/problab/server"
 	"github.com/zintix-labs/problab/server/logger"
 	"github.com/zintix-labs/problab/server/svrcfg"
@@ -41,12 +42,14 @@ func main() {
 type config struct {
 	LogMode     string
 	SlotBufSize int
+	GRPCAddr    string
 }
 
 func loadConfigFromFlags() (*svrcfg.SvrCfg, error) {
 	cfg := new(config)
 	flag.StringVar(&cfg.LogMode, "log-mode", "ModeDev", "log mode: ModeDev|ModeProd|ModeSilence")
 	flag.IntVar(&cfg.SlotBufSize, "buf", 3, "number of machine instances per game")
+	flag.StringVar(&cfg.GRPCAddr, "grpc", "", "grpc listen address (e.g. :5809), empty to disable")
 
 	flag.Parse()
 
@@ -55,7 +58,7 @@ func loadConfigFromFlags() (*svrcfg.SvrCfg, error) {
 	lab, err := problab.NewAuto(
 		core.Default(),
 		problab.Configs(demo_configs.FS),
-		problab.Logics(demo_logic.Logics),
+		problab.Logics(demo_logic.Logics, flow.Logics),
 	)
 	if err != nil {
 		return nil, err
@@ -65,6 +68,7 @@ func loadConfigFromFlags() (*svrcfg.SvrCfg, error) {
 		SlotBufSize: cfg.SlotBufSize,
 		Problab:     lab,
 		Mode:        svrcfg.ModeDev,
+		GRPCAddr:    cfg.GRPCAddr,
 	}
 	return sCfg, nil
 }
@@ -0,0 +1,232 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// 設定檔驗證工具
+//
+//	go run ./cmd/validate demo/demo_configs          # 驗證目錄下所有 yaml/yml/json
+//	go run ./cmd/validate game_0.yaml game_1.yaml    # 驗證指定檔案
+//	go run ./cmd/validate -schema > gamesetting.schema.json
+//	go run ./cmd/validate -rules                     # 列出語意規則
+//	go run ./cmd/validate -off mask_bet_type -warn line_table_duplicate configs/
+//	go run ./cmd/validate -dump game_1.yaml              # 輸出展開 includes / extends / 引用後的完整設定
+//	go run ./cmd/validate -root configs configs/studio_a/game_3.yaml  # 以 configs 為根解析 includes（如 ../shared.yaml）
+//
+// includes 的解析根目錄與執行期（Problab.RegisterAll）相同：目錄參數即設定來源根目錄，
+// 指定檔案時預設為檔案所在目錄；以 -root 指定時所有參數都以該目錄為根（參數須位於其中）。
+// 隱藏目錄與隱藏檔（. 開頭）與 RegisterAll 一樣略過。
+//
+// 每個問題輸出一行 file:line:col: path: message；有任何 Fatal 問題時 exit code 為 1。
+// 只含 includes / library 的共用區塊檔會被略過（由引用它的設定檔一併檢查）。
+func main() {
+	schema := flag.Bool("schema", false, "print JSON Schema of GameSetting and exit")
+	asJSON := flag.Bool("json", false, "print issues as JSON")
+	listRules := flag.Bool("rules", false, "list semantic rules and exit")
+	off := flag.String("off", "", "comma separated rules to disable")
+	warn := flag.String("warn", "", "comma separated rules to downgrade to warn")
+	dump := flag.Bool("dump", false, "print fully expanded configs and exit")
+	root := flag.String("root", "", "config root for resolving includes (default: the directory argument, or the file's directory)")
+	flag.Parse()
+
+	if *listRules {
+		for _, r := range spec.Rules() {
+			desc := r.Desc
+			if r.Invariant {
+				desc += " (invariant, cannot be disabled)"
+			}
+			fmt.Printf("%-22s %-5s %s\n", r.Name, errs.ErrLv(r.Level), desc)
+		}
+		return
+	}
+	levels := spec.RuleLevels{}
+	for _, name := range splitList(*off) {
+		levels[name] = errs.None
+	}
+	for _, name := range splitList(*warn) {
+		levels[name] = errs.Warn
+	}
+	if err := levels.Valid(); err != nil {
+		log.Fatal(err)
+	}
+
+	if *schema {
+		b, err := spec.GameSettingSchemaJSON()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(b))
+		return
+	}
+
+	targets := flag.Args()
+	if len(targets) == 0 {
+		targets = []string{"."}
+	}
+	files, err := collect(targets, *root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *dump {
+		for _, f := range files {
+			raw, err := fs.ReadFile(f.fsys, f.name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if spec.IsLibraryConfig(raw) {
+				continue
+			}
+			gs, err := spec.GetGameSettingFromFS(f.fsys, f.name)
+			if err != nil {
+				log.Fatalf("%s: %v", f.path, err)
+			}
+			b, err := gs.Expanded()
+			if err != nil {
+				log.Fatalf("%s: %v", f.path, err)
+			}
+			fmt.Printf("# %s\n---\n%s", f.path, b)
+		}
+		return
+	}
+
+	issues := make([]spec.ConfigIssue, 0)
+	for _, f := range files {
+		raw, err := fs.ReadFile(f.fsys, f.name)
+		if err != nil {
+			issues = append(issues, spec.ConfigIssue{File: f.path, Level: errs.Fatal, Message: err.Error()})
+			continue
+		}
+		if spec.IsLibraryConfig(raw) {
+			continue
+		}
+		for _, ci := range spec.ValidateConfig(raw, f.name, spec.FSLoader(f.fsys), levels) {
+			ci.File = f.path
+			issues = append(issues, ci)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(issues); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		for _, ci := range issues {
+			fmt.Println(ci.String())
+		}
+		fmt.Fprintf(os.Stderr, "%d file(s) checked, %d issue(s)\n", len(files), len(issues))
+	}
+	for _, ci := range issues {
+		if ci.Level == errs.Fatal {
+			os.Exit(1)
+		}
+	}
+}
+
+func splitList(s string) []string {
+	out := make([]string, 0)
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// configFile 待檢查的設定檔：fsys 為解析 includes 的根目錄，name 為檔案在其中的路徑
+type configFile struct {
+	path string // 命令列上的路徑（輸出用）
+	fsys fs.FS
+	name string
+}
+
+// collect 展開目錄，收集所有 yaml/yml/json 檔案並決定各自的根目錄（root 非空時一律以 root 為根）
+func collect(targets []string, root string) ([]configFile, error) {
+	files := make([]configFile, 0, len(targets))
+	add := func(base, path string) error {
+		absBase, err := filepath.Abs(base)
+		if err != nil {
+			return err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(absBase, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%s is outside root %s", path, base)
+		}
+		files = append(files, configFile{path: path, fsys: os.DirFS(base), name: filepath.ToSlash(rel)})
+		return nil
+	}
+	for _, t := range targets {
+		info, err := os.Stat(t)
+		if err != nil {
+			return nil, err
+		}
+		base := root
+		if !info.IsDir() {
+			if base == "" {
+				base = filepath.Dir(t)
+			}
+			if err := add(base, t); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if base == "" {
+			base = t
+		}
+		err = filepath.WalkDir(t, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// 與 RegisterAll 相同：隱藏目錄與隱藏檔略過
+			if strings.HasPrefix(d.Name(), ".") && path != t {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				return add(base, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/zintix-labs/problab/demo/optimal"
 	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/flow"
 	"github.com/zintix-labs/problab/server/logger"
 	"github.com/zintix-labs/problab/server/svrcfg"
 )
@@ -47,7 +48,7 @@ func NewProbLab() (*problab.Problab, error) {
 	return problab.NewAuto(
 		core.Default(),
 		problab.Configs(demo_configs.FS),
-		problab.Logics(demo_logic.Logics),
+		problab.Logics(demo_logic.Logics, flow.Logics),
 		problab.WithOptimalFS(optimal.FS),
 	)
 }
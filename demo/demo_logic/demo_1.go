@@ -15,9 +15,11 @@
 package demo_logic
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/buf"
 	"github.com/zintix-labs/problab/sdk/ops"
 	"github.com/zintix-labs/problab/sdk/slot"
@@ -62,6 +64,11 @@ func buildGame0001(g *slot.Game) (slot.GameLogic, error) {
 	fix.fillReelsIdx = make([]int, g.GameSetting.GameModeSettings[0].ScreenSetting.Columns)
 	fix.screenFillPos = make([]int, g.GameSetting.GameModeSettings[0].ScreenSetting.Columns)
 	fix.symbolTypes = g.GameSetting.GameModeSettings[0].SymbolSetting.SymbolTypes
+	for i := range g.GameSetting.GameModeSettings {
+		if _, ok := g.GameSetting.GameModeSettings[i].GenScreenSetting.ReelSetByName(refillReelSet); !ok {
+			return nil, errs.NewFatal(fmt.Sprintf("mode %d: reel set %q required", i, refillReelSet))
+		}
+	}
 	g1 := &game0001{fixed: fix}
 	g1.ext = g1.newext(g.GameSetting.GameModeSettings[0].ScreenSetting.ScreenSize, g.IsSim)
 	return g1, nil
@@ -71,6 +78,9 @@ func buildGame0001(g *slot.Game) (slot.GameLogic, error) {
 // ** 此遊戲需要的額外結構宣告: Fixed設定宣告 **
 // ============================================================
 
+// refillReelSet 消除後補盤使用的輪帶組名稱
+const refillReelSet = "refill"
+
 type fixed0001 struct {
 	MaxStep       int `yaml:"max_step"`
 	FreeRounds    int `yaml:"free_rounds"`
@@ -149,7 +159,7 @@ func (g *game0001) getBaseResult(r *buf.SpinRequest, gh *slot.Game) *buf.GameMod
 	sc := mode.ScreenCalculator
 	gmr := mode.GameModeResult
 	maxStep := g.fixed.MaxStep
-	fillReelSet := &mode.GameModeSetting.GenScreenSetting.ReelSetGroup[1]
+	fillReelSet, _ := mode.GameModeSetting.GenScreenSetting.ReelSetByName(refillReelSet)
 	betMult := r.BetMult
 	fix := g.fixed
 	g.resetIdx()
@@ -206,7 +216,7 @@ func (g *game0001) getFreeResult(r *buf.SpinRequest, gh *slot.Game) *buf.GameMod
 	sc := mode.ScreenCalculator
 	gmr := mode.GameModeResult
 	maxStep := g.fixed.MaxStep
-	fillReelSet := &mode.GameModeSetting.GenScreenSetting.ReelSetGroup[1]
+	fillReelSet, _ := mode.GameModeSetting.GenScreenSetting.ReelSetByName(refillReelSet)
 	fix := g.fixed
 
 	betMult := r.BetMult
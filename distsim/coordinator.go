@@ -0,0 +1,158 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distsim
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/recorder"
+	"github.com/zintix-labs/problab/stats"
+)
+
+// Coordinator 分派分片、重試失敗分片並合併結果
+type Coordinator struct {
+	Workers []Worker
+	Retries int // 每個分片失敗後最多重試次數（不含第一次）
+}
+
+func NewCoordinator(retries int, workers ...Worker) (*Coordinator, error) {
+	if len(workers) == 0 {
+		return nil, errs.NewWarn("workers must > 0")
+	}
+	for i, w := range workers {
+		if w == nil {
+			return nil, errs.Warnf("worker %d is nil", i)
+		}
+	}
+	if retries < 0 {
+		return nil, errs.NewWarn("retries must be non-negative integer")
+	}
+	return &Coordinator{Workers: workers, Retries: retries}, nil
+}
+
+type attempt struct {
+	shard Shard
+	tries int
+}
+
+// Run 執行整個 plan，回傳合併後（已 Done）的統計報表與用時
+//
+// 結果依分片 Index 排序後合併，與哪個 Worker 完成、完成順序無關。
+func (c *Coordinator) Run(ctx context.Context, plan []Shard) (*stats.StatReport, time.Duration, error) {
+	if len(plan) == 0 {
+		return nil, 0, errs.NewWarn("empty plan")
+	}
+	for i, sh := range plan {
+		if sh.Index != i {
+			return nil, 0, errs.Warnf("plan shard %d has index %d", i, sh.Index)
+		}
+		if err := sh.Valid(); err != nil {
+			return nil, 0, err
+		}
+	}
+	start := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 佇列容量 = 分片數：排隊中 + 執行中的分片總數不會超過 len(plan)，重新排隊不會阻塞
+	queue := make(chan attempt, len(plan))
+	for _, sh := range plan {
+		queue <- attempt{shard: sh}
+	}
+	results := make([]*recorder.SpinRecorder, len(plan))
+	done := make(chan struct{})
+
+	mu := new(sync.Mutex)
+	remaining := len(plan)
+	var failed error
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(c.Workers))
+	for _, w := range c.Workers {
+		go func(w Worker) {
+			defer wg.Done()
+			for {
+				var a attempt
+				select {
+				case <-ctx.Done():
+					return
+				case a = <-queue:
+				}
+				rec, err := w.Run(ctx, a.shard)
+				if err == nil {
+					err = check(a.shard, rec)
+				}
+				mu.Lock()
+				switch {
+				case err == nil:
+					results[a.shard.Index] = rec
+					remaining--
+					if remaining == 0 {
+						close(done)
+					}
+				case ctx.Err() != nil:
+					// 已取消（完成或其他分片失敗），不再重試
+				case a.tries < c.Retries:
+					queue <- attempt{shard: a.shard, tries: a.tries + 1}
+				default:
+					if failed == nil {
+						failed = errs.Wrap(err, fmt.Sprintf("shard %d failed after %d attempts", a.shard.Index, a.tries+1))
+					}
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(w)
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	cancel()
+	wg.Wait()
+
+	if failed != nil {
+		return nil, 0, failed
+	}
+	if remaining > 0 {
+		return nil, 0, errs.Wrap(ctx.Err(), "distributed sim canceled")
+	}
+	merged, err := recorder.MergeSpinRecorder(results)
+	if err != nil {
+		return nil, 0, err
+	}
+	st := merged.Done()
+	st.Done()
+	return st, time.Since(start), nil
+}
+
+// check 確認 Worker 回傳的紀錄員確實對應該分片
+func check(sh Shard, rec *recorder.SpinRecorder) error {
+	if rec == nil || rec.Basic == nil {
+		return errs.Fatalf("shard %d: empty record", sh.Index)
+	}
+	if rec.GameId != sh.GameId || rec.BetMode != sh.BetMode {
+		return errs.Fatalf("shard %d: record mismatch gid=%d bet_mode=%d", sh.Index, rec.GameId, rec.BetMode)
+	}
+	if rec.Basic.Rounds != sh.Rounds {
+		return errs.Fatalf("shard %d: expected %d rounds, got %d", sh.Index, sh.Rounds, rec.Basic.Rounds)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package distsim 提供多行程 / 多節點的分散式模擬。
+//
+// 協調者（Coordinator）把一次模擬切成多個「帶種子的分片」（Shard），
+// 分派給 Worker（本機子行程或透過既有 server 的 HTTP 端點），
+// 收回各分片序列化後的 recorder.SpinRecorder，再以 recorder.MergeSpinRecorder 合併。
+//
+// 可重現性：分片的切法與種子只由 (總局數, 分片數, baseSeed) 決定，
+// 與 Worker 數量、分派順序、重試次數無關，因此合併後的 StatReport 可重現。
+package distsim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/recorder"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// MaxShardRounds 單一分片允許的最大局數，避免單次請求佔用 Worker 過久
+const MaxShardRounds int = 10_000_000
+
+// Shard 分散式模擬的最小工作單位
+type Shard struct {
+	Index   int      `json:"index"`
+	GameId  spec.GID `json:"gid"`
+	BetMode int      `json:"bet_mode"`
+	Rounds  int      `json:"rounds"`
+	Seed    int64    `json:"seed"`
+}
+
+// Valid 基本參數檢查
+func (sh Shard) Valid() error {
+	if sh.Index < 0 {
+		return errs.NewWarn("shard index must be non-negative integer")
+	}
+	if sh.BetMode < 0 {
+		return errs.NewWarn("bet_mode must be non-negative integer")
+	}
+	if sh.Rounds < 1 || sh.Rounds > MaxShardRounds {
+		return errs.Warnf("shard rounds must be between 1 and %d, got %d", MaxShardRounds, sh.Rounds)
+	}
+	return nil
+}
+
+// Plan 將總局數切成 shards 個分片，並以 baseSeed 經 problab.SeedMaker 依序派生各分片種子。
+//
+// 餘數平均分給前面的分片，確保所有分片局數差距不超過 1。
+func Plan(gid spec.GID, betMode int, rounds int, shards int, baseSeed int64) ([]Shard, error) {
+	if shards < 1 {
+		return nil, errs.NewWarn("shards must > 0")
+	}
+	if rounds < shards {
+		return nil, errs.Warnf("rounds (%d) must be >= shards (%d)", rounds, shards)
+	}
+	sm := problab.NewSeedMaker(baseSeed)
+	per, rem := rounds/shards, rounds%shards
+	plan := make([]Shard, shards)
+	for i := range shards {
+		n := per
+		if i < rem {
+			n++
+		}
+		plan[i] = Shard{
+			Index:   i,
+			GameId:  gid,
+			BetMode: betMode,
+			Rounds:  n,
+			Seed:    sm.Next(),
+		}
+		if err := plan[i].Valid(); err != nil {
+			return nil, errs.Wrap(err, fmt.Sprintf("plan shard %d failed", i))
+		}
+	}
+	return plan, nil
+}
+
+// RunShard 在本行程內執行單一分片，回傳尚未 Done 的紀錄員
+//
+// 本機子行程（ServeStdio）與 HTTP 端點共用此入口，確保不同傳輸方式結果一致。
+func RunShard(lab *problab.Problab, sh Shard) (*recorder.SpinRecorder, error) {
+	if lab == nil {
+		return nil, errs.NewFatal("problab is required")
+	}
+	if err := sh.Valid(); err != nil {
+		return nil, err
+	}
+	sim, err := lab.NewSimulatorWithSeed(sh.GameId, sh.Seed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("build simulator err: %d", sh.GameId))
+	}
+	return sim.SimRecord(sh.BetMode, sh.Rounds)
+}
+
+// ServeStdio 子行程 Worker 入口：自 r 讀取一個 Shard(JSON)，執行後將紀錄員 JSON 寫入 w
+func ServeStdio(lab *problab.Problab, r io.Reader, w io.Writer) error {
+	sh := Shard{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&sh); err != nil {
+		return errs.Wrap(err, "decode shard err")
+	}
+	rec, err := RunShard(lab, sh)
+	if err != nil {
+		return err
+	}
+	b, err := recorder.EncodeSpinRecorder(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return errs.Wrap(err, "write spin record err")
+	}
+	return nil
+}
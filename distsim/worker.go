@@ -0,0 +1,125 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distsim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/recorder"
+)
+
+// ShardPath 既有 server 上執行分片的端點（僅 dev 模式開放）
+const ShardPath string = "/v1/simshard"
+
+// Worker 執行單一分片並回傳紀錄員
+//
+// 同一個 Worker 同時只會被協調者派發一個分片；需要更高併發請建立多個 Worker。
+type Worker interface {
+	Run(ctx context.Context, sh Shard) (*recorder.SpinRecorder, error)
+}
+
+// LocalWorker 在協調者行程內直接執行分片（不經序列化以外的傳輸），適合測試
+type LocalWorker struct {
+	Lab *problab.Problab
+}
+
+func (lw *LocalWorker) Run(ctx context.Context, sh Shard) (*recorder.SpinRecorder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	rec, err := RunShard(lw.Lab, sh)
+	if err != nil {
+		return nil, err
+	}
+	// 走一次序列化，確保與跨行程的行為一致
+	b, err := recorder.EncodeSpinRecorder(rec)
+	if err != nil {
+		return nil, err
+	}
+	return recorder.DecodeSpinRecorder(b)
+}
+
+// ProcessWorker 每個分片啟動一個本機子行程
+//
+// 子行程需自 stdin 讀取 Shard JSON，並將紀錄員 JSON 寫到 stdout（見 ServeStdio）。
+type ProcessWorker struct {
+	Path string
+	Args []string
+}
+
+func (pw *ProcessWorker) Run(ctx context.Context, sh Shard) (*recorder.SpinRecorder, error) {
+	in, err := json.Marshal(sh)
+	if err != nil {
+		return nil, errs.Wrap(err, "encode shard err")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, pw.Path, pw.Args...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		msg := strings.TrimSpace(stderr.String())
+		return nil, errs.WrapWithExtra(err, fmt.Sprintf("worker process failed on shard %d", sh.Index), msg)
+	}
+	return recorder.DecodeSpinRecorder(stdout.Bytes())
+}
+
+// HTTPWorker 透過既有 server 的 ShardPath 端點執行分片
+type HTTPWorker struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func (hw *HTTPWorker) Run(ctx context.Context, sh Shard) (*recorder.SpinRecorder, error) {
+	in, err := json.Marshal(sh)
+	if err != nil {
+		return nil, errs.Wrap(err, "encode shard err")
+	}
+	url := strings.TrimRight(hw.BaseURL, "/") + ShardPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(in))
+	if err != nil {
+		return nil, errs.Wrap(err, "build shard request err")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := hw.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("worker %s failed on shard %d", hw.BaseURL, sh.Index))
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.Wrap(err, "read shard response err")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errs.Fatalf("worker %s failed on shard %d: %d %s", hw.BaseURL, sh.Index, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return recorder.DecodeSpinRecorder(body)
+}
@@ -0,0 +1,96 @@
+package distsim_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zintix-labs/problab/demo"
+	"github.com/zintix-labs/problab/distsim"
+	"github.com/zintix-labs/problab/recorder"
+)
+
+// flakyWorker 前 fails 次呼叫失敗，之後轉交給內部 Worker
+type flakyWorker struct {
+	inner distsim.Worker
+	fails atomic.Int64
+}
+
+func (f *flakyWorker) Run(ctx context.Context, sh distsim.Shard) (*recorder.SpinRecorder, error) {
+	if f.fails.Add(-1) >= 0 {
+		return nil, errors.New("boom")
+	}
+	return f.inner.Run(ctx, sh)
+}
+
+func TestPlanSplitsRounds(t *testing.T) {
+	plan, err := distsim.Plan(0, 0, 10, 3, 42)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	total := 0
+	for i, sh := range plan {
+		if sh.Index != i {
+			t.Fatalf("unexpected index %d at %d", sh.Index, i)
+		}
+		total += sh.Rounds
+	}
+	if total != 10 || plan[0].Rounds != 4 || plan[2].Rounds != 3 {
+		t.Fatalf("unexpected split: %+v", plan)
+	}
+	again, _ := distsim.Plan(0, 0, 10, 3, 42)
+	if !reflect.DeepEqual(plan, again) {
+		t.Fatalf("plan must be deterministic")
+	}
+	if _, err := distsim.Plan(0, 0, 2, 3, 42); err == nil {
+		t.Fatalf("expected error when rounds < shards")
+	}
+}
+
+func TestCoordinatorReproducible(t *testing.T) {
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		t.Fatalf("new problab failed: %v", err)
+	}
+	plan, err := distsim.Plan(0, 0, 2000, 8, 7)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+
+	one, _ := distsim.NewCoordinator(0, &distsim.LocalWorker{Lab: lab})
+	st1, _, err := one.Run(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	flaky := &flakyWorker{inner: &distsim.LocalWorker{Lab: lab}}
+	flaky.fails.Store(3)
+	many, _ := distsim.NewCoordinator(3, flaky, &distsim.LocalWorker{Lab: lab}, &distsim.LocalWorker{Lab: lab})
+	st2, _, err := many.Run(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("run with retries failed: %v", err)
+	}
+
+	if st1.Summary.Rounds != 2000 {
+		t.Fatalf("expected 2000 rounds, got %d", st1.Summary.Rounds)
+	}
+	if !reflect.DeepEqual(st1.Summary, st2.Summary) || !reflect.DeepEqual(st1.Dist, st2.Dist) {
+		t.Fatalf("merged report not reproducible:\n%+v\n%+v", st1.Summary, st2.Summary)
+	}
+}
+
+func TestCoordinatorRetryExhausted(t *testing.T) {
+	lab, err := demo.NewProbLab()
+	if err != nil {
+		t.Fatalf("new problab failed: %v", err)
+	}
+	plan, _ := distsim.Plan(0, 0, 100, 2, 7)
+	flaky := &flakyWorker{inner: &distsim.LocalWorker{Lab: lab}}
+	flaky.fails.Store(100)
+	c, _ := distsim.NewCoordinator(1, flaky)
+	if _, _, err := c.Run(context.Background(), plan); err == nil {
+		t.Fatalf("expected error after retries exhausted")
+	}
+}
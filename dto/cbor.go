@@ -0,0 +1,915 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dto
+
+// CBOR（RFC 8949）精簡二進位格式：
+//
+// 與 JSON 使用同一份 DTO 結構與 json tag（欄位名稱、omitempty、"-" 規則相同），
+// 因此 ExtendResult（經 RegisterExtendRender 還原後的 struct）與 cp（經 RegisterCheckpoint 編碼的 JSON）
+// 都能直接轉成 CBOR，不需要各遊戲另外註冊編碼器。結構定義見 SpinSchemaCDDL。
+//
+// 對應規則：
+//   - struct / map → map（text key）；struct 依欄位宣告順序輸出，map 依 key 排序（輸出可重現）
+//   - slice / array → array；[]byte → byte string；nil slice / map / 指標 → null
+//   - 整數 → major type 0/1；浮點數 → float64
+//   - json.RawMessage 與其他 json.Marshaler → 先取 JSON 再轉成對應的 CBOR 結構
+//
+// 解碼直接寫入目標 DTO，只接受定長（definite length）編碼，tag 會被略過；
+// json.RawMessage 與其他 json.Unmarshaler 先解出通用值再交給 UnmarshalJSON（cp 走這裡）。
+// 長度先與剩餘資料比對、巢狀深度上限為 cborMaxDepth，惡意長度或過深資料不會造成大量配置。
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/zintix-labs/problab/errs"
+)
+
+// ContentTypeCBOR CBOR 的媒體類型，用於 Accept / Content-Type 協商
+const ContentTypeCBOR = "application/cbor"
+
+// SpinSchemaCDDL SpinRequest / SpinResult 的 CBOR 結構定義（CDDL, RFC 8610）
+//
+//go:embed spin.cddl
+var SpinSchemaCDDL string
+
+// cborMaxDepth 解碼時允許的最大巢狀深度
+const cborMaxDepth = 64
+
+// EncodeCBOR 以 json tag 規則把 v 編碼成 CBOR
+func EncodeCBOR(v any) ([]byte, error) {
+	e := &cborEncoder{buf: make([]byte, 0, 512)}
+	if err := e.value(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// DecodeCBOR 把 CBOR 直接解碼到 v（欄位對應同 json.Unmarshal，且拒絕未知欄位與重複 key）
+func DecodeCBOR(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errs.NewWarn("cbor: decode target must be a non-nil pointer")
+	}
+	d := &cborDecoder{data: data}
+	if err := d.decode(rv.Elem(), 0); err != nil {
+		return err
+	}
+	if d.off != len(d.data) {
+		return errs.NewWarn("cbor: trailing data after top-level item")
+	}
+	return nil
+}
+
+// AcceptsCBOR 判斷客戶端是否偏好 CBOR 回應
+//
+// 只有 Accept 明確列出 application/cbor，且其 q 值高於 application/json
+// （同分時較前者優先）才回傳 true；未帶 Accept 或只接受 JSON 時維持 JSON。
+func AcceptsCBOR(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return false
+	}
+	qCBOR, qJSON := -1.0, -1.0 // -1 表示未列出
+	cborFirst := false
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				q = f
+			}
+		}
+		switch {
+		case mt == ContentTypeCBOR && qCBOR < 0:
+			qCBOR = q
+			cborFirst = qJSON < 0
+		case mt == "application/json" && qJSON < 0:
+			qJSON = q
+		}
+	}
+	if qCBOR <= 0 {
+		return false
+	}
+	return qCBOR > qJSON || (qCBOR == qJSON && cborFirst)
+}
+
+// isCBORContent 判斷請求 body 是否為 CBOR
+func isCBORContent(r *http.Request) bool {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mt == ContentTypeCBOR
+}
+
+// ============================================================
+// ** Encoder **
+// ============================================================
+
+const (
+	cborUint   byte = 0
+	cborNeg    byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborTag    byte = 6
+	cborSimple byte = 7
+
+	cborFalse   byte = 0xf4
+	cborTrue    byte = 0xf5
+	cborNull    byte = 0xf6
+	cborUndef   byte = 0xf7
+	cborFloat16 byte = 0xf9
+	cborFloat32 byte = 0xfa
+	cborFloat64 byte = 0xfb
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+type cborEncoder struct {
+	buf []byte
+}
+
+// head 寫入 major type 與長度/數值
+func (e *cborEncoder) head(major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, m|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, m|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, m|25), uint16(n))
+	case n <= math.MaxUint32:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, m|26), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, m|27), n)
+	}
+}
+
+func (e *cborEncoder) int(n int64) {
+	if n < 0 {
+		e.head(cborNeg, uint64(-1-n))
+		return
+	}
+	e.head(cborUint, uint64(n))
+}
+
+func (e *cborEncoder) float(f float64) {
+	e.buf = binary.BigEndian.AppendUint64(append(e.buf, cborFloat64), math.Float64bits(f))
+}
+
+func (e *cborEncoder) text(s string) {
+	e.head(cborText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *cborEncoder) value(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf = append(e.buf, cborNull)
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		return e.marshaler(v.Interface().(json.Marshaler))
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		return e.value(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, cborTrue)
+		} else {
+			e.buf = append(e.buf, cborFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(cborUint, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.float(v.Float())
+	case reflect.String:
+		e.text(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf = append(e.buf, cborNull)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.head(cborBytes, uint64(v.Len()))
+			e.buf = append(e.buf, v.Bytes()...)
+			return nil
+		}
+		return e.array(v)
+	case reflect.Array:
+		return e.array(v)
+	case reflect.Map:
+		return e.mapValue(v)
+	case reflect.Struct:
+		return e.structValue(v)
+	default:
+		return errs.NewWarn("cbor: unsupported type " + v.Type().String())
+	}
+	return nil
+}
+
+func (e *cborEncoder) array(v reflect.Value) error {
+	n := v.Len()
+	e.head(cborArray, uint64(n))
+	for i := 0; i < n; i++ {
+		if err := e.value(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *cborEncoder) mapValue(v reflect.Value) error {
+	if v.IsNil() {
+		e.buf = append(e.buf, cborNull)
+		return nil
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return errs.NewWarn("cbor: unsupported map key type " + v.Type().Key().String())
+	}
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+	e.head(cborMap, uint64(len(keys)))
+	for _, k := range keys {
+		e.text(k.String())
+		if err := e.value(v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *cborEncoder) structValue(v reflect.Value) error {
+	fields := cachedFields(v.Type())
+	n := 0
+	for i := range fields {
+		if !fields[i].skip(v) {
+			n++
+		}
+	}
+	e.head(cborMap, uint64(n))
+	for i := range fields {
+		f := &fields[i]
+		if f.skip(v) {
+			continue
+		}
+		e.text(f.name)
+		if err := e.value(v.FieldByIndex(f.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshaler 取 JSON 後轉成 CBOR（json.RawMessage 的 cp 走這裡）
+func (e *cborEncoder) marshaler(m json.Marshaler) error {
+	js, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(js)) == 0 {
+		e.buf = append(e.buf, cborNull)
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var x any
+	if err := dec.Decode(&x); err != nil {
+		return errs.NewWarn("cbor: invalid json payload: " + err.Error())
+	}
+	return e.generic(x)
+}
+
+// generic 編碼 JSON 解出的通用值
+func (e *cborEncoder) generic(x any) error {
+	switch t := x.(type) {
+	case nil:
+		e.buf = append(e.buf, cborNull)
+	case bool:
+		return e.value(reflect.ValueOf(t))
+	case string:
+		e.text(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			e.int(i)
+		} else if u, err := strconv.ParseUint(string(t), 10, 64); err == nil {
+			e.head(cborUint, u)
+		} else {
+			f, err := t.Float64()
+			if err != nil {
+				return errs.NewWarn("cbor: invalid number " + string(t))
+			}
+			e.float(f)
+		}
+	case []any:
+		e.head(cborArray, uint64(len(t)))
+		for _, it := range t {
+			if err := e.generic(it); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		e.head(cborMap, uint64(len(keys)))
+		for _, k := range keys {
+			e.text(k)
+			if err := e.generic(t[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return e.value(reflect.ValueOf(x))
+	}
+	return nil
+}
+
+// ============================================================
+// ** struct 欄位（json tag 規則） **
+// ============================================================
+
+type cborField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// skip 是否因 omitempty 而省略
+func (f *cborField) skip(v reflect.Value) bool {
+	if !f.omitEmpty {
+		return false
+	}
+	fv, err := v.FieldByIndexErr(f.index)
+	return err != nil || isEmptyValue(fv)
+}
+
+var fieldCache sync.Map // reflect.Type -> []cborField
+
+func cachedFields(t reflect.Type) []cborField {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]cborField)
+	}
+	fs := appendFields(nil, t, nil)
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+// appendFields 依 json tag 收集欄位；未加 tag 的嵌入 struct 攤平（同 encoding/json）
+func appendFields(fs []cborField, t reflect.Type, parent []int) []cborField {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		index := append(append([]int(nil), parent...), i)
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fs = appendFields(fs, ft, index)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fs = append(fs, cborField{
+			name:      name,
+			index:     index,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	return fs
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// ============================================================
+// ** Decoder **
+// ============================================================
+
+type cborDecoder struct {
+	data []byte
+	off  int
+}
+
+func (d *cborDecoder) errEOF() error {
+	return errs.NewWarn("cbor: unexpected end of data")
+}
+
+// head 讀取 major type 與其參數
+func (d *cborDecoder) head() (byte, byte, uint64, error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, d.errEOF()
+	}
+	ib := d.data[d.off]
+	d.off++
+	major, ai := ib>>5, ib&0x1f
+	var size int
+	switch {
+	case ai < 24:
+		return major, ai, uint64(ai), nil
+	case ai == 24:
+		size = 1
+	case ai == 25:
+		size = 2
+	case ai == 26:
+		size = 4
+	case ai == 27:
+		size = 8
+	case ai == 31:
+		return 0, 0, 0, errs.NewWarn("cbor: indefinite length is not supported")
+	default:
+		return 0, 0, 0, errs.NewWarn("cbor: reserved additional info " + strconv.Itoa(int(ai)))
+	}
+	if len(d.data)-d.off < size {
+		return 0, 0, 0, d.errEOF()
+	}
+	b := d.data[d.off : d.off+size]
+	d.off += size
+	switch size {
+	case 1:
+		return major, ai, uint64(b[0]), nil
+	case 2:
+		return major, ai, uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return major, ai, uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return major, ai, binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// bytes 讀取 n bytes（長度先與剩餘資料比對，避免惡意長度造成大量配置）
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, d.errEOF()
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// decode 依目標型別解出下一個值
+func (d *cborDecoder) decode(v reflect.Value, depth int) error {
+	if depth > cborMaxDepth {
+		return errs.NewWarn("cbor: nesting too deep")
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(jsonUnmarshalerType) {
+		return d.unmarshaler(v.Addr().Interface().(json.Unmarshaler), depth)
+	}
+	start := d.off
+	major, ai, n, err := d.head()
+	if err != nil {
+		return err
+	}
+	if major == cborTag {
+		return d.decode(v, depth+1)
+	}
+	if major == cborSimple && (ai == cborNull&0x1f || ai == cborUndef&0x1f) {
+		// null：指標 / slice / map / interface 設為 nil，其餘維持原值（同 json.Unmarshal）
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			v.SetZero()
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		d.off = start
+		return d.decode(v.Elem(), depth+1)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		d.off = start
+		it, err := d.item(depth)
+		if err != nil {
+			return err
+		}
+		if it != nil {
+			v.Set(reflect.ValueOf(it))
+		}
+		return nil
+	case reflect.Bool:
+		if major == cborSimple && (ai == cborTrue&0x1f || ai == cborFalse&0x1f) {
+			v.SetBool(ai == cborTrue&0x1f)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if major == cborUint || major == cborNeg {
+			if n > math.MaxInt64 {
+				return d.typeErr("integer overflow", v)
+			}
+			x := int64(n)
+			if major == cborNeg {
+				x = -1 - x
+			}
+			if v.OverflowInt(x) {
+				return d.typeErr("integer overflow", v)
+			}
+			v.SetInt(x)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if major == cborUint {
+			if v.OverflowUint(n) {
+				return d.typeErr("integer overflow", v)
+			}
+			v.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case major == cborUint:
+			v.SetFloat(float64(n))
+			return nil
+		case major == cborNeg:
+			v.SetFloat(-1 - float64(n))
+			return nil
+		case major == cborSimple && ai >= cborFloat16&0x1f && ai <= cborFloat64&0x1f:
+			d.off = start
+			f, err := d.item(depth)
+			if err != nil {
+				return err
+			}
+			if v.OverflowFloat(f.(float64)) {
+				return d.typeErr("float overflow", v)
+			}
+			v.SetFloat(f.(float64))
+			return nil
+		}
+	case reflect.String:
+		if major == cborText {
+			b, err := d.text(n)
+			if err != nil {
+				return err
+			}
+			v.SetString(string(b))
+			return nil
+		}
+	case reflect.Slice:
+		if major == cborBytes && v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.bytes(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		if major == cborArray {
+			// 每個元素至少 1 byte
+			if n > uint64(len(d.data)-d.off) {
+				return d.errEOF()
+			}
+			s := reflect.MakeSlice(v.Type(), int(n), int(n))
+			for i := range int(n) {
+				if err := d.decode(s.Index(i), depth+1); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Array:
+		if major == cborArray {
+			if n != uint64(v.Len()) {
+				return d.typeErr("array length mismatch", v)
+			}
+			for i := range v.Len() {
+				if err := d.decode(v.Index(i), depth+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if major == cborMap && v.Type().Key().Kind() == reflect.String {
+			return d.mapValue(v, n, depth)
+		}
+	case reflect.Struct:
+		if major == cborMap {
+			return d.structValue(v, n, depth)
+		}
+	}
+	return d.typeErr("cannot decode major type "+strconv.Itoa(int(major)), v)
+}
+
+func (d *cborDecoder) typeErr(msg string, v reflect.Value) error {
+	return errs.NewWarn("cbor: " + msg + " into Go value of type " + v.Type().String())
+}
+
+// text 讀取 n bytes 的 UTF-8 文字
+func (d *cborDecoder) text(n uint64) ([]byte, error) {
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		return nil, errs.NewWarn("cbor: invalid utf-8 text")
+	}
+	return b, nil
+}
+
+// key 讀取 map 的 text key
+func (d *cborDecoder) key() (string, error) {
+	major, _, n, err := d.head()
+	if err != nil {
+		return "", err
+	}
+	if major != cborText {
+		return "", errs.NewWarn("cbor: map key must be text")
+	}
+	b, err := d.text(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// mapValue 解出 map[string]T
+func (d *cborDecoder) mapValue(v reflect.Value, n uint64, depth int) error {
+	// 每組 key/value 至少 2 bytes
+	if n > uint64(len(d.data)-d.off)/2 {
+		return d.errEOF()
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(v.Type(), int(n)))
+	}
+	kt, et := v.Type().Key(), v.Type().Elem()
+	seen := make(map[string]struct{}, n)
+	for range n {
+		k, err := d.key()
+		if err != nil {
+			return err
+		}
+		if _, dup := seen[k]; dup {
+			return errs.NewWarn("cbor: duplicate map key " + strconv.Quote(k))
+		}
+		seen[k] = struct{}{}
+		ev := reflect.New(et).Elem()
+		if err := d.decode(ev, depth+1); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(k).Convert(kt), ev)
+	}
+	return nil
+}
+
+// structValue 依 json tag 把 map 解進 struct；未知欄位與重複 key 皆拒絕
+func (d *cborDecoder) structValue(v reflect.Value, n uint64, depth int) error {
+	if n > uint64(len(d.data)-d.off)/2 {
+		return d.errEOF()
+	}
+	fields := cachedFields(v.Type())
+	var seen uint64 // 欄位數超過 64 時不檢查重複（DTO 遠小於此）
+	for range n {
+		k, err := d.key()
+		if err != nil {
+			return err
+		}
+		i := fieldIndex(fields, k)
+		if i < 0 {
+			return errs.NewWarn("cbor: unknown field " + strconv.Quote(k))
+		}
+		if i < 64 {
+			if seen&(1<<uint(i)) != 0 {
+				return errs.NewWarn("cbor: duplicate map key " + strconv.Quote(k))
+			}
+			seen |= 1 << uint(i)
+		}
+		fv, err := fieldByIndexAlloc(v, fields[i].index)
+		if err != nil {
+			return err
+		}
+		if err := d.decode(fv, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldIndex 找出 key 對應的欄位（先精確比對，再不分大小寫，同 encoding/json）
+func fieldIndex(fields []cborField, k string) int {
+	for i := range fields {
+		if fields[i].name == k {
+			return i
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, k) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldByIndexAlloc 取得巢狀欄位，途中遇到 nil 的嵌入指標時配置
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for k, i := range index {
+		if k > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errs.NewWarn("cbor: cannot set embedded pointer to unexported struct " + v.Type().Elem().String())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, nil
+}
+
+// unmarshaler 解出通用值後以 JSON 交給 UnmarshalJSON（json.RawMessage 的 cp 走這裡）
+func (d *cborDecoder) unmarshaler(u json.Unmarshaler, depth int) error {
+	it, err := d.item(depth)
+	if err != nil {
+		return err
+	}
+	js, err := json.Marshal(it)
+	if err != nil {
+		return errs.NewWarn("cbor: " + err.Error())
+	}
+	if err := u.UnmarshalJSON(js); err != nil {
+		return errs.NewWarn("cbor: " + err.Error())
+	}
+	return nil
+}
+
+// item 解出一個通用值（與 json.Unmarshal 到 any 的型別相容）
+func (d *cborDecoder) item(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errs.NewWarn("cbor: nesting too deep")
+	}
+	major, ai, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return n, nil
+	case cborNeg:
+		if n > math.MaxInt64 {
+			return nil, errs.NewWarn("cbor: negative integer overflow")
+		}
+		return -1 - int64(n), nil
+	case cborBytes:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case cborText:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, errs.NewWarn("cbor: invalid utf-8 text")
+		}
+		return string(b), nil
+	case cborArray:
+		// 每個元素至少 1 byte
+		if n > uint64(len(d.data)-d.off) {
+			return nil, d.errEOF()
+		}
+		arr := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			it, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, it)
+		}
+		return arr, nil
+	case cborMap:
+		if n > uint64(len(d.data)-d.off)/2 {
+			return nil, d.errEOF()
+		}
+		m := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := k.(string)
+			if !ok {
+				return nil, errs.NewWarn("cbor: map key must be text")
+			}
+			if _, dup := m[ks]; dup {
+				return nil, errs.NewWarn("cbor: duplicate map key " + strconv.Quote(ks))
+			}
+			if m[ks], err = d.item(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		return d.item(depth + 1)
+	default: // cborSimple
+		switch ai {
+		case cborFalse & 0x1f:
+			return false, nil
+		case cborTrue & 0x1f:
+			return true, nil
+		case cborNull & 0x1f, cborUndef & 0x1f:
+			return nil, nil
+		case cborFloat16 & 0x1f:
+			return halfToFloat(uint16(n)), nil
+		case cborFloat32 & 0x1f:
+			return float64(math.Float32frombits(uint32(n))), nil
+		case cborFloat64 & 0x1f:
+			return math.Float64frombits(n), nil
+		}
+		return nil, errs.NewWarn("cbor: unsupported simple value " + strconv.Itoa(int(n)))
+	}
+}
+
+// halfToFloat IEEE 754 半精度轉 float64
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
@@ -24,15 +24,17 @@ import (
 )
 
 type SpinResult struct {
-	GameName  string              `json:"game"`                // 遊戲名稱
-	GameID    spec.GID            `json:"gameid"`              // 遊戲編號
-	TotalWin  int                 `json:"win"`                 // 總贏分
-	Bet       int                 `json:"bet"`                 // 本次押注
-	BetMode   int                 `json:"betmode"`             // 押注類型
-	BetMult   int                 `json:"betmult"`             // 押注倍數
-	GameModes []GameModeResultDTO `json:"gamemodes,omitempty"` // 每個遊戲模式的完整結構
-	IsGameEnd bool                `json:"isend"`               // 遊戲結束旗標
-	State     SpinState           `json:"spin_state"`          // 遊戲狀態
+	GameName    string              `json:"game"`                // 遊戲名稱
+	GameID      spec.GID            `json:"gameid"`              // 遊戲編號
+	Variant     string              `json:"variant,omitempty"`   // 實際使用的遊戲變體（空字串為預設版本）
+	TotalWin    int                 `json:"win"`                 // 總贏分
+	Bet         int                 `json:"bet"`                 // 本次押注
+	BetMode     int                 `json:"betmode"`             // 押注類型
+	BetMult     int                 `json:"betmult"`             // 押注倍數
+	GameModes   []GameModeResultDTO `json:"gamemodes,omitempty"` // 每個遊戲模式的完整結構
+	IsGameEnd   bool                `json:"isend"`               // 遊戲結束旗標
+	State       SpinState           `json:"spin_state"`          // 遊戲狀態
+	Fingerprint string              `json:"fingerprint"`         // 設定檔指紋（回放時原樣帶回 SpinRequest.Fingerprint）
 }
 
 // GameModeResultDTO 為對外輸出的 GameModeResult 序列化結構。
@@ -59,8 +61,11 @@ type ActResultDTO struct {
 	RoundAccWin int `json:"roundaccwin"`
 	StepAccWin  int `json:"stepaccwin"`
 	ActWin      int `json:"actwin"`
+	BaseWin     int `json:"basewin"` // 套用累進乘數前的贏分
+	Mult        int `json:"mult"`    // 累進乘數（actwin = basewin * mult）
 
 	Screen  []int16               `json:"screen,omitempty"`
+	Layout  []int16               `json:"layout,omitempty"` // 可變高度模式：各軸高度（不含頂部橫向輪帶；遮罩格在 screen 中為 -1）
 	Details []CalcScreenDetailDTO `json:"details,omitempty"`
 
 	ExtendResult any `json:"ext,omitempty"` // 未輸出模式下存struct指標 轉到DTO時轉 map[string]any(Json) 或 []byte
@@ -68,16 +73,47 @@ type ActResultDTO struct {
 
 // CalcScreenDetail 盤面算分細項
 type CalcScreenDetailDTO struct {
-	Win          int     `json:"win"`       // 本DetailCalcResult輸贏
-	SymbolID     int16   `json:"symbol"`    // 圖標ID
-	LineID       int     `json:"line"`      // Line專用 : 線表ID
-	Count        int     `json:"count"`     // 計算數量 直接對應PayTable (Line: 連線長度, Cluster: 集群圖標數量, Collect: 收集數量)
-	Combinations int     `json:"comb"`      // Way專用 : 組合數量
-	Direction    uint8   `json:"direction"` // 方向，0: 左到右，1: 右到左 (Way Line用)
+	Win          int     `json:"win"`            // 本DetailCalcResult輸贏
+	SymbolID     int16   `json:"symbol"`         // 圖標ID
+	LineID       int     `json:"line"`           // Line專用 : 線表ID
+	Count        int     `json:"count"`          // 計算數量 直接對應PayTable (Line: 連線長度, Cluster: 集群圖標數量, Collect: 收集數量)
+	Combinations int     `json:"comb"`           // Way專用 : 組合數量
+	Direction    uint8   `json:"direction"`      // 方向，0: 左到右，1: 右到左 (Way Line用)
+	Multiplier   int     `json:"mult,omitempty"` // 套用的合併乘數（win 已含乘數；Way 為各 way 乘數總和；未套用時省略）
 	HitMap       []int16 `json:"hits"`
 }
 
+// Verbosity spin 回應的詳細程度（SpinRequest.Verbosity）
+//
+//   - summary：總贏分、各模式總計與狀態（不含 acts）
+//   - screens：另含每個 act 的盤面與贏分（不含 details / hits / ext）
+//   - full：完整輸出（空字串視為 full）
+//
+// 較低的等級在建構 DTO 時直接略過對應的拷貝與轉換，而不是建完再刪除欄位。
+type Verbosity string
+
+const (
+	VerbosityFull    Verbosity = "full"
+	VerbosityScreens Verbosity = "screens"
+	VerbositySummary Verbosity = "summary"
+)
+
+// Valid 是否為合法的詳細程度（空字串視為 full）
+func (v Verbosity) Valid() bool {
+	switch v {
+	case "", VerbosityFull, VerbosityScreens, VerbositySummary:
+		return true
+	}
+	return false
+}
+
+// NewSpinResultDTO 建立完整輸出的 SpinResult（同 NewSpinResultDTOWith(sr, VerbosityFull)）
 func NewSpinResultDTO(sr *buf.SpinResult) (SpinResult, error) {
+	return NewSpinResultDTOWith(sr, VerbosityFull)
+}
+
+// NewSpinResultDTOWith 依詳細程度建立 SpinResult
+func NewSpinResultDTOWith(sr *buf.SpinResult, v Verbosity) (SpinResult, error) {
 	if sr == nil {
 		return SpinResult{}, errs.NewWarn("spin result is nil")
 	}
@@ -94,27 +130,29 @@ func NewSpinResultDTO(sr *buf.SpinResult) (SpinResult, error) {
 	}
 
 	dto := SpinResult{
-		GameName:  sr.GameName,
-		GameID:    sr.GameID,
-		TotalWin:  sr.TotalWin,
-		Bet:       sr.Bet,
-		BetMode:   sr.BetMode,
-		BetMult:   sr.BetMult,
-		IsGameEnd: sr.IsGameEnd,
-		State:     state,
+		GameName:    sr.GameName,
+		GameID:      sr.GameID,
+		Variant:     sr.Variant,
+		TotalWin:    sr.TotalWin,
+		Bet:         sr.Bet,
+		BetMode:     sr.BetMode,
+		BetMult:     sr.BetMult,
+		IsGameEnd:   sr.IsGameEnd,
+		State:       state,
+		Fingerprint: sr.Fingerprint,
 	}
 
 	if len(sr.GameModeList) > 0 {
 		dto.GameModes = make([]GameModeResultDTO, len(sr.GameModeList))
 		for i, gm := range sr.GameModeList {
-			dto.GameModes[i] = newGameModeResultDTO(sr.Logic, gm)
+			dto.GameModes[i] = newGameModeResultDTO(sr.Logic, gm, v)
 		}
 	}
 
 	return dto, nil
 }
 
-func newGameModeResultDTO(lkey spec.LogicKey, gmr *buf.GameModeResult) GameModeResultDTO {
+func newGameModeResultDTO(lkey spec.LogicKey, gmr *buf.GameModeResult, v Verbosity) GameModeResultDTO {
 	if gmr == nil {
 		return GameModeResultDTO{}
 	}
@@ -125,11 +163,16 @@ func newGameModeResultDTO(lkey spec.LogicKey, gmr *buf.GameModeResult) GameModeR
 		IsModeEnd:  gmr.IsModeEnd,
 		Trigger:    gmr.Trigger,
 	}
-	snap := snapshotGameMode(gmr)
-	if len(gmr.ActResults) > 0 {
-		dto.ActResults = make([]ActResultDTO, len(gmr.ActResults))
-		for i, a := range gmr.ActResults {
-			dto.ActResults[i] = newActResultDTO(i, a, gmr, snap)
+	if v == VerbositySummary || len(gmr.ActResults) == 0 {
+		return dto
+	}
+	full := v != VerbosityScreens
+	snap := snapshotGameMode(gmr, full)
+	dto.ActResults = make([]ActResultDTO, len(gmr.ActResults))
+	for i, a := range gmr.ActResults {
+		dto.ActResults[i] = newActResultDTO(i, a, snap)
+		if full {
+			dto.ActResults[i].Details = newDetailDto(a, gmr, snap)
 			dto.ActResults[i].ExtendResult = renderExtendResult(lkey, a.ExtendResult)
 		}
 	}
@@ -137,7 +180,7 @@ func newGameModeResultDTO(lkey spec.LogicKey, gmr *buf.GameModeResult) GameModeR
 	return dto
 }
 
-func newActResultDTO(id int, act buf.ActResult, gmr *buf.GameModeResult, snap *gameModeSnapshot) ActResultDTO {
+func newActResultDTO(id int, act buf.ActResult, snap *gameModeSnapshot) ActResultDTO {
 	dto := ActResultDTO{
 		ActType: act.ActType,
 		Id:      id,
@@ -153,9 +196,11 @@ func newActResultDTO(id int, act buf.ActResult, gmr *buf.GameModeResult, snap *g
 		RoundAccWin: act.RoundAccWin,
 		StepAccWin:  act.StepAccWin,
 		ActWin:      act.ActWin,
+		BaseWin:     act.BaseWin,
+		Mult:        act.Mult,
 
-		Screen:  getScreenDtoFromSnap(act.ScreenStart, snap),
-		Details: newDetailDto(act, gmr, snap),
+		Screen: getScreenDtoFromSnap(act.ScreenStart, snap),
+		Layout: getLayoutDtoFromSnap(act.LayoutStart, snap),
 	}
 	return dto
 }
@@ -176,6 +221,7 @@ func newDetailDto(a buf.ActResult, gmr *buf.GameModeResult, snap *gameModeSnapsh
 			Count:        gmr.Details[startIdx+i].Count,
 			Combinations: gmr.Details[startIdx+i].Combinations,
 			Direction:    gmr.Details[startIdx+i].Direction,
+			Multiplier:   gmr.Details[startIdx+i].Multiplier,
 			HitMap:       hitMapFromSnap(gmr.Details[startIdx+i], snap),
 		}
 	}
@@ -190,17 +236,26 @@ type gameModeSnapshot struct {
 	Screens    []int16
 	Details    []buf.CalcScreenDetail // 依你實際型別
 	HitsFlat   []int16
+	Layouts    []int16
 	ScreenSize int
+	Cols       int
 }
 
-func snapshotGameMode(gmr *buf.GameModeResult) *gameModeSnapshot {
+// withDetails 為 false 時只拷貝盤面（screens 等級不需要細項與命中位置）
+func snapshotGameMode(gmr *buf.GameModeResult, withDetails bool) *gameModeSnapshot {
 	s := gameModeSnapshot{
 		ScreenSize: gmr.ScreenSize,
+		Cols:       gmr.Cols,
 	}
 	// 一次性深拷貝
 	s.Screens = append([]int16(nil), gmr.Screens...)
-	s.HitsFlat = append([]int16(nil), gmr.HitsFlat...)
-	s.Details = append([]buf.CalcScreenDetail(nil), gmr.Details...) // 如果 Details 內沒有指標/切片，這樣就夠
+	if len(gmr.Layouts) > 0 {
+		s.Layouts = append([]int16(nil), gmr.Layouts...)
+	}
+	if withDetails {
+		s.HitsFlat = append([]int16(nil), gmr.HitsFlat...)
+		s.Details = append([]buf.CalcScreenDetail(nil), gmr.Details...) // 如果 Details 內沒有指標/切片，這樣就夠
+	}
 	return &s
 }
 
@@ -215,6 +270,17 @@ func getScreenDtoFromSnap(start int, snap *gameModeSnapshot) []int16 {
 	return snap.Screens[start:end] // 不拷貝
 }
 
+func getLayoutDtoFromSnap(start int, snap *gameModeSnapshot) []int16 {
+	if start < 0 {
+		return nil
+	}
+	end := start + snap.Cols
+	if end > len(snap.Layouts) {
+		return nil
+	}
+	return snap.Layouts[start:end] // 不拷貝
+}
+
 func hitMapFromSnap(d buf.CalcScreenDetail, snap *gameModeSnapshot) []int16 {
 	hs := d.HitsFlatStart
 	he := hs + d.HitsFlatLen
@@ -31,6 +31,8 @@ type SpinRequest struct {
 	UID       string   `json:"uid"`                  // 唯一識別碼
 	GameName  string   `json:"game"`                 // 要玩的遊戲
 	GameId    spec.GID `json:"gid"`                  // 遊戲機台編號
+	Variant   string   `json:"variant,omitempty"`    // 可選：遊戲變體（例如不同 RTP 版本）；省略時使用營運商預設，再退回遊戲預設變體
+	Operator  string   `json:"operator,omitempty"`   // 可選：營運商代碼（用於套用營運商的預設變體）
 	Bet       int      `json:"bet"`                  // 投注額
 	BetMode   int      `json:"bet_mode"`             // 投注模式(走BetUnit[i])
 	BetMult   int      `json:"bet_mult"`             // 投注倍數(BetUnit[0]的幾倍)
@@ -41,14 +43,18 @@ type SpinRequest struct {
 	//   - 若 has_choice 為 false（或未提供），則 choice 必須省略；否則視為 request 格式錯誤。
 	//   - 若 has_choice 為 true，則視為有選擇；choice 若省略則視為 0。
 	StartState *StartState `json:"start_state,omitempty"` // 可選：由業務端帶入的引擎狀態（nil=新局；帶 start_b64u=回放/續玩）。
+	// Fingerprint 可選：回放時帶入當初回應中的設定檔指紋；與機台目前的指紋不符時拒絕，避免以不同設定重現舊局。
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Verbosity 可選：回應的詳細程度（summary / screens / full），省略時為 full。
+	Verbosity Verbosity `json:"verbosity,omitempty"`
 }
 
 // DecodeSpinRequest 會把 HTTP 請求解碼成 SpinRequest。
 //
 // 支援：
-//   - GET：從 query string 讀取參數（uid/game/gid/bet/bet_mode/bet_mult/cycle/choice/has_choice）。
+//   - GET：從 query string 讀取參數（uid/game/gid/variant/operator/bet/bet_mode/bet_mult/cycle/choice/has_choice/fingerprint/verbosity）。
 //     注意：GET 建議僅用於「新局」或簡單測試；巢狀狀態（start_state/cp/jp）建議使用 POST。
-//   - POST：從 JSON body 反序列化（支援 start_state）。
+//   - POST：從 JSON body 反序列化（支援 start_state）；Content-Type 為 application/cbor 時改以 CBOR 解碼（見 DecodeCBOR）。
 //
 // StartState（start_state）語意：
 //   - start_state 缺省 / 為 null / 為空物件：視為「新局」。
@@ -78,6 +84,10 @@ func DecodeSpinRequest(r *http.Request) (*SpinRequest, error) {
 		q := r.URL.Query()
 		req.UID = q.Get("uid")
 		req.GameName = q.Get("game")
+		req.Variant = q.Get("variant")
+		req.Operator = q.Get("operator")
+		req.Fingerprint = q.Get("fingerprint")
+		req.Verbosity = Verbosity(q.Get("verbosity"))
 
 		if s := q.Get("gid"); s != "" {
 			u, err := strconv.ParseUint(s, 10, 0)
@@ -141,6 +151,16 @@ func DecodeSpinRequest(r *http.Request) (*SpinRequest, error) {
 		// 防止 body 過大（預設 1MiB）
 		const maxBody = 1 << 20
 		body := io.LimitReader(r.Body, maxBody)
+		if isCBORContent(r) {
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return nil, fmt.Errorf("read body: %w", err)
+			}
+			if err := DecodeCBOR(data, req); err != nil {
+				return nil, fmt.Errorf("invalid cbor: %w", err)
+			}
+			return req, nil
+		}
 		dec := json.NewDecoder(body)
 		dec.DisallowUnknownFields()
 		if err := dec.Decode(req); err != nil {
@@ -19,7 +19,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/spec"
 )
 
 func TestDecodeSpinRequestGET(t *testing.T) {
@@ -67,3 +72,340 @@ func TestDecodeSpinRequestRejectsUnknownFields(t *testing.T) {
 		t.Fatalf("expected error for unknown field")
 	}
 }
+
+func TestCBORKnownEncodings(t *testing.T) {
+	cases := []struct {
+		v    any
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{-1, []byte{0x20}},
+		{500, []byte{0x19, 0x01, 0xf4}},
+		{"a", []byte{0x61, 'a'}},
+		{[]int16{1, -2}, []byte{0x82, 0x01, 0x21}},
+		{map[string]int{"b": 2, "a": 1}, []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'b', 0x02}},
+		{json.RawMessage(`{"x":true}`), []byte{0xa1, 0x61, 'x', 0xf5}},
+		{[]int(nil), []byte{0xf6}},
+	}
+	for _, c := range cases {
+		got, err := EncodeCBOR(c.v)
+		if err != nil {
+			t.Fatalf("encode %v: %v", c.v, err)
+		}
+		if !bytes.Equal(got, c.want) {
+			t.Fatalf("encode %v: got % x, want % x", c.v, got, c.want)
+		}
+	}
+}
+
+type cborTestExt struct {
+	Hits  []int `json:"hits"`
+	Bonus int   `json:"bonus,omitempty"`
+	inner int
+}
+
+func TestCBORSpinResultMatchesJSON(t *testing.T) {
+	res := SpinResult{
+		GameName: "demo",
+		GameID:   7,
+		TotalWin: 30,
+		Bet:      10,
+		BetMult:  1,
+		GameModes: []GameModeResultDTO{{
+			TotalWin: 30,
+			ActResults: []ActResultDTO{{
+				ActType:      "gen_screen",
+				Mult:         1,
+				Screen:       []int16{1, -1, 3},
+				Details:      []CalcScreenDetailDTO{{Win: 30, SymbolID: 2, HitMap: []int16{0, 2}}},
+				ExtendResult: &cborTestExt{Hits: []int{4}, inner: 1},
+			}},
+		}},
+		IsGameEnd: true,
+		State: SpinState{
+			StartCoreSnapB64U: "AAA",
+			AfterCoreSnapB64U: "BBB",
+			Checkpoint:        json.RawMessage(`{"ver":1,"left":[3,-2.5]}`),
+		},
+	}
+	b, err := EncodeCBOR(res)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	d := &cborDecoder{data: b}
+	item, err := d.item(0)
+	if err != nil || d.off != len(b) {
+		t.Fatalf("decode: %v (off %d/%d)", err, d.off, len(b))
+	}
+	got, _ := json.Marshal(item)
+
+	js, _ := json.Marshal(res)
+	var generic any
+	_ = json.Unmarshal(js, &generic)
+	want, _ := json.Marshal(generic)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("cbor and json differ:\n cbor %s\n json %s", got, want)
+	}
+	if len(b) >= len(js) {
+		t.Fatalf("cbor should be smaller than json: %d >= %d", len(b), len(js))
+	}
+}
+
+func TestDecodeSpinRequestCBOR(t *testing.T) {
+	body, err := EncodeCBOR(map[string]any{
+		"uid":      "u3",
+		"game":     "demo",
+		"gid":      11,
+		"bet":      5,
+		"bet_mode": 1,
+		"bet_mult": 2,
+		"cycle":    0,
+		"start_state": map[string]any{
+			"start_b64u": "AAA",
+			"cp":         map[string]any{"ver": 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/spin", bytes.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeCBOR)
+	req, err := DecodeSpinRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.UID != "u3" || req.GameId != 11 || req.BetMode != 1 || req.BetMult != 2 {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if req.StartState == nil || req.StartState.StartCoreSnapB64U != "AAA" || string(req.StartState.Checkpoint) != `{"ver":1}` {
+		t.Fatalf("unexpected start state: %+v", req.StartState)
+	}
+
+	// 未知欄位、截斷資料皆拒絕
+	bad, _ := EncodeCBOR(map[string]any{"gid": 1, "unknown": true})
+	for _, data := range [][]byte{bad, body[:len(body)-1]} {
+		r := httptest.NewRequest(http.MethodPost, "/spin", bytes.NewReader(data))
+		r.Header.Set("Content-Type", ContentTypeCBOR)
+		if _, err := DecodeSpinRequest(r); err == nil {
+			t.Fatalf("expected error for % x", data)
+		}
+	}
+}
+
+func TestDecodeCBORRoundTrip(t *testing.T) {
+	req := SpinRequest{
+		UID: "u4", GameName: "demo", GameId: 3, Variant: "rtp96", Bet: -5, BetMode: 1, BetMult: 2, Cycle: 1,
+		Choice: 0, HasChoice: true, Fingerprint: "sha256:ab", Verbosity: VerbositySummary,
+		StartState: &StartState{StartCoreSnapB64U: "AAA", Checkpoint: json.RawMessage(`{"left":[3,-2.5],"ver":1}`)},
+	}
+	b, err := EncodeCBOR(req)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var got SpinRequest
+	if err := DecodeCBOR(b, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, req) {
+		t.Fatalf("round trip differs:\n got %+v %+v\nwant %+v %+v", got, got.StartState, req, req.StartState)
+	}
+
+	// 與 JSON 相同：null 清空指標、大小寫不同的 key 仍對應欄位
+	got.StartState = &StartState{}
+	null, _ := EncodeCBOR(map[string]any{"start_state": nil, "UID": "x"})
+	if err := DecodeCBOR(null, &got); err != nil || got.StartState != nil || got.UID != "x" {
+		t.Fatalf("unexpected null / case-insensitive decode: %v %+v", err, got)
+	}
+}
+
+func TestDecodeCBORMalformed(t *testing.T) {
+	valid, _ := EncodeCBOR(map[string]any{"gid": 1})
+	cases := map[string][]byte{
+		"empty":             {},
+		"truncated":         valid[:len(valid)-1],
+		"trailing":          append(append([]byte(nil), valid...), 0x00),
+		"not a map":         {0x01},
+		"indefinite map":    {0xbf, 0xff},
+		"reserved info":     {0x1c},
+		"int key":           {0xa1, 0x01, 0x01},
+		"unknown field":     {0xa1, 0x61, 'x', 0x01},
+		"duplicate key":     {0xa2, 0x63, 'g', 'i', 'd', 0x01, 0x63, 'g', 'i', 'd', 0x02},
+		"case duplicate":    {0xa2, 0x63, 'g', 'i', 'd', 0x01, 0x63, 'G', 'I', 'D', 0x02},
+		"invalid utf-8":     {0xa1, 0x63, 'u', 'i', 'd', 0x61, 0xff},
+		"string for int":    {0xa1, 0x63, 'b', 'e', 't', 0x61, '1'},
+		"negative gid":      {0xa1, 0x63, 'g', 'i', 'd', 0x20},
+		"int overflow":      {0xa1, 0x63, 'b', 'e', 't', 0x1b, 0x80, 0, 0, 0, 0, 0, 0, 0},
+		"bad cp value":      {0xa1, 0x6b, 's', 't', 'a', 'r', 't', '_', 's', 't', 'a', 't', 'e', 0xa1, 0x62, 'c', 'p', 0xf9, 0x7e, 0x00},
+		"simple for string": {0xa1, 0x63, 'u', 'i', 'd', 0xf0},
+	}
+	for name, data := range cases {
+		var req SpinRequest
+		if err := DecodeCBOR(data, &req); err == nil {
+			t.Fatalf("%s: expected error for % x", name, data)
+		}
+	}
+	if err := DecodeCBOR(valid, SpinRequest{}); err == nil {
+		t.Fatalf("expected error for non-pointer target")
+	}
+}
+
+func TestDecodeCBORLengthBombs(t *testing.T) {
+	// 宣告極大長度但資料很短：必須在配置前拒絕
+	huge := []byte{0x1b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	bombs := map[string][]byte{
+		"array":  append([]byte{0xa1, 0x6b, 's', 't', 'a', 'r', 't', '_', 's', 't', 'a', 't', 'e', 0xa1, 0x64, 'h', 'i', 't', 's', 0x9b}, huge[1:]...),
+		"map":    append([]byte{0xbb}, huge[1:]...),
+		"text":   append([]byte{0xa1, 0x63, 'u', 'i', 'd', 0x7b}, huge[1:]...),
+		"cp map": append([]byte{0xa1, 0x6b, 's', 't', 'a', 'r', 't', '_', 's', 't', 'a', 't', 'e', 0xa1, 0x62, 'c', 'p', 0xbb}, huge[1:]...),
+		"cp arr": append([]byte{0xa1, 0x6b, 's', 't', 'a', 'r', 't', '_', 's', 't', 'a', 't', 'e', 0xa1, 0x62, 'c', 'p', 0x9a}, 0xff, 0xff, 0xff, 0xff),
+	}
+	for name, data := range bombs {
+		var req SpinRequest
+		if err := DecodeCBOR(data, &req); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDecodeCBORDeepNesting(t *testing.T) {
+	nested := func(depth int, open byte) []byte {
+		b := []byte{0xa1, 0x6b, 's', 't', 'a', 'r', 't', '_', 's', 't', 'a', 't', 'e', 0xa1, 0x62, 'c', 'p'}
+		for range depth {
+			b = append(b, open)
+			if open == 0xa1 {
+				b = append(b, 0x61, 'k')
+			}
+		}
+		return append(b, 0x01)
+	}
+	for _, open := range []byte{0x81, 0xa1, 0xc1} { // array / map / tag
+		var req SpinRequest
+		if err := DecodeCBOR(nested(10, open), &req); err != nil {
+			t.Fatalf("shallow nesting %#x must decode: %v", open, err)
+		}
+		if err := DecodeCBOR(nested(100000, open), &req); err == nil || !strings.Contains(err.Error(), "too deep") {
+			t.Fatalf("deep nesting %#x must be rejected, got %v", open, err)
+		}
+	}
+}
+
+// FuzzDecodeCBOR 任意輸入不得 panic；成功解出的請求重新編碼後必須解回相同的值
+func FuzzDecodeCBOR(f *testing.F) {
+	seed, _ := EncodeCBOR(SpinRequest{
+		UID: "u", GameName: "g", GameId: 1, Bet: 1, BetMult: 1,
+		StartState: &StartState{StartCoreSnapB64U: "AAA", Checkpoint: json.RawMessage(`{"a":[1,2.5,"x",null,true]}`)},
+	})
+	f.Add(seed)
+	f.Add([]byte{0xa1, 0x63, 'g', 'i', 'd', 0x01})
+	f.Add([]byte{0x9b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	f.Add([]byte{0xbf, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var req SpinRequest
+		if err := DecodeCBOR(data, &req); err != nil {
+			return
+		}
+		b, err := EncodeCBOR(req)
+		if err != nil {
+			t.Fatalf("re-encode: %v", err)
+		}
+		var again SpinRequest
+		if err := DecodeCBOR(b, &again); err != nil {
+			t.Fatalf("decode re-encoded: %v", err)
+		}
+		if !reflect.DeepEqual(again, req) {
+			t.Fatalf("round trip differs:\n got %+v\nwant %+v", again, req)
+		}
+	})
+}
+
+func TestAcceptsCBOR(t *testing.T) {
+	cases := map[string]bool{
+		"":                                   false,
+		"application/json":                   false,
+		"*/*":                                false,
+		"application/cbor":                   true,
+		"application/cbor, application/json": true,
+		"application/json, application/cbor": false,
+		"application/json;q=0.5, application/cbor": true,
+		"application/cbor;q=0.2, application/json": false,
+		"application/cbor;q=0":                     false,
+	}
+	for accept, want := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/spin", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		if got := AcceptsCBOR(r); got != want {
+			t.Fatalf("Accept %q: got %v, want %v", accept, got, want)
+		}
+	}
+}
+
+func testVerbositySpin(t *testing.T) *buf.SpinResult {
+	t.Helper()
+	gms := &spec.GameModeSetting{
+		ScreenSetting: spec.ScreenSetting{Columns: 3, Rows: 1},
+		HitSetting:    spec.HitSetting{BetTypeStr: "line_ltr", LineTable: [][]int16{{0, 0, 0}}},
+		SymbolSetting: spec.SymbolSetting{SymbolUsedStr: []string{"H1"}, PayTable: [][]int{{0, 2, 5}}},
+	}
+	if err := gms.ScreenSetting.Init(); err != nil {
+		t.Fatalf("screen init error: %v", err)
+	}
+	if err := gms.HitSetting.Init(); err != nil {
+		t.Fatalf("hit setting init error: %v", err)
+	}
+	gmr := buf.NewGameModeResult(0, gms, 4, 4)
+	gmr.RecordDetail(5, 0, 0, 3, 0, 0, []int16{0, 1, 2})
+	gmr.AddAct(buf.FinishRound, "base", []int16{0, 0, 0}, &buf.NoExtend{})
+
+	sr := buf.NewSpinResult(&spec.GameSetting{GameName: "demo", GameID: 7, BetUnits: []int{1}})
+	sr.AppendModeResult(gmr)
+	sr.End()
+	return sr
+}
+
+func TestSpinResultVerbosity(t *testing.T) {
+	sr := testVerbositySpin(t)
+
+	full, err := NewSpinResultDTOWith(sr, "")
+	if err != nil {
+		t.Fatalf("full: %v", err)
+	}
+	a := full.GameModes[0].ActResults[0]
+	if len(a.Screen) != 3 || len(a.Details) != 1 || len(a.Details[0].HitMap) != 3 {
+		t.Fatalf("full: unexpected act %+v", a)
+	}
+
+	screens, err := NewSpinResultDTOWith(sr, VerbosityScreens)
+	if err != nil {
+		t.Fatalf("screens: %v", err)
+	}
+	a = screens.GameModes[0].ActResults[0]
+	if len(a.Screen) != 3 || a.ActWin != 5 || a.Details != nil || a.ExtendResult != nil {
+		t.Fatalf("screens: unexpected act %+v", a)
+	}
+
+	summary, err := NewSpinResultDTOWith(sr, VerbositySummary)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	gm := summary.GameModes[0]
+	if summary.TotalWin != full.TotalWin || gm.TotalWin != 5 || gm.ActResults != nil {
+		t.Fatalf("summary: unexpected result %+v", summary)
+	}
+
+	for _, v := range []Verbosity{"", VerbosityFull, VerbosityScreens, VerbositySummary} {
+		if !v.Valid() {
+			t.Fatalf("%q should be valid", v)
+		}
+	}
+	if Verbosity("all").Valid() {
+		t.Fatalf("unexpected valid verbosity")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/spin?gid=1&verbosity=summary", nil)
+	req, err := DecodeSpinRequest(r)
+	if err != nil || req.Verbosity != VerbositySummary {
+		t.Fatalf("decode verbosity: %+v %v", req, err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package problab
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// ConfigFingerprint 回傳遊戲的完整指紋：設定檔指紋（spec.GameSetting.Fingerprint）再加上優化文件的雜湊。
+//
+// 未啟用優化（或沒有 optimalFS）時，結果等於設定檔指紋本身；
+// 啟用時依 gachas / seed_bank 的宣告順序把「路徑 + 檔案 SHA-256」一併納入（缺檔記為 -），任何一個檔案改動都會改變指紋。
+// 同一份設定與優化文件永遠得到同一個指紋，可用來證明某局或某份報表出自哪一份設定。
+func ConfigFingerprint(gs *spec.GameSetting, optimalFS fs.FS) (string, error) {
+	if gs == nil {
+		return "", errs.NewWarn("game setting is nil")
+	}
+	fp, err := gs.Fingerprint()
+	if err != nil {
+		return "", errs.Wrap(err, "config fingerprint err")
+	}
+	opt := gs.OptimalSetting
+	if !opt.UseOptimal || optimalFS == nil {
+		return fp, nil
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "config %s\n", fp)
+	files := make([]string, 0, len(opt.Gachas)+len(opt.SeedBank))
+	files = append(files, opt.Gachas...)
+	files = append(files, opt.SeedBank...)
+	for _, name := range files {
+		b, err := fs.ReadFile(optimalFS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			// 缺檔不在此報錯（建機台載入時才會失敗），但仍反映在指紋上
+			fmt.Fprintf(h, "%s -\n", name)
+			continue
+		}
+		if err != nil {
+			return "", errs.Wrap(err, fmt.Sprintf("config fingerprint err: read optimal file %s", name))
+		}
+		sum := sha256.Sum256(b)
+		fmt.Fprintf(h, "%s %s\n", name, hex.EncodeToString(sum[:]))
+	}
+	return spec.FingerprintPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
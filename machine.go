@@ -75,6 +75,7 @@ type OptimalRuntime struct {
 type Machine struct {
 	gameName    string           // 遊戲名稱（來自 GameSetting.GameName，主要用於觀測/日誌）
 	gameId      spec.GID         // 遊戲 ID（Catalog 內唯一；用於路由與查表）
+	variant     string           // 遊戲變體（同一 gameId 的不同版本；空字串為預設版本）
 	core        *core.Core       // RNG 核心（PRNG + Snapshot/Restore 合約；熱路徑會頻繁取樣）
 	gh          *slot.Game       // 遊戲執行核心（Slot 邏輯入口；由 LogicRegistry + GameSetting 組裝）
 	BetUnits    []int            // 押注單位（由遊戲設定衍生；通常給外部列舉 UI/測試）
@@ -83,6 +84,7 @@ type Machine struct {
 	mu          sync.Mutex       // 防併發鎖：保護可重用 buffers 與核心狀態一致性
 	initseed    int64            // 出生 seed（便於追溯；完整重現請用 Snapshot/Restore）
 	optimal     *OptimalRuntime  // 優化運行時數據（nil 表示未啟用優化）
+	fingerprint string           // 設定檔指紋（ConfigFingerprint；蓋在每個 SpinResult 上，回放時比對）
 }
 
 // newMachine 以「隨機 seed」建立 Machine。
@@ -92,12 +94,12 @@ type Machine struct {
 //   - 同時保留可追溯性（seed 會被記錄在 Machine.initseed）
 //
 // seed 只保證了新建的Machine起點，如果需要在任意局後將機台"重設"到任意Core節點，請利用Snapshot Restore來操作
-func newMachine(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, isSim bool, optimalFS fs.FS) (*Machine, error) {
+func newMachine(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, isSim bool, optimalFS fs.FS, fp string) (*Machine, error) {
 	seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 	if err != nil {
 		return nil, errs.Wrap(err, "new crypto seed error in go std lib")
 	}
-	return newMachineWithSeed(gs, reg, cf, seed.Int64(), isSim, optimalFS)
+	return newMachineWithSeed(gs, reg, cf, seed.Int64(), isSim, optimalFS, fp)
 }
 
 // newMachineWithSeed 以指定 seed 建立 Machine。
@@ -109,10 +111,12 @@ func newMachine(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFacto
 //  2. slot.NewGame(gs, reg, core, isSim) 依設定 + registry 建出 Slot 遊戲執行核心
 //  3. 初始化 Machine 需要的 buffers（SpinRequest/SpinResult）
 //  4. 如果啟用優化（UseOptimal = true），從 optimalFS 加載 Gacha 和 SeedBank
-func newMachineWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, isSim bool, optimalFS fs.FS) (*Machine, error) {
+//  5. 將設定檔指紋（ConfigFingerprint，由呼叫端每個設定只算一次後傳入）蓋在 SpinResult 上
+func newMachineWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, isSim bool, optimalFS fs.FS, fp string) (*Machine, error) {
 	m := &Machine{
 		gameName:    gs.GameName,
 		gameId:      spec.GID(gs.GameID),
+		variant:     gs.Variant,
 		core:        core.New(cf.New(seed)),
 		gh:          nil,
 		BetUnits:    nil,
@@ -120,6 +124,7 @@ func newMachineWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.P
 		SpinResult:  nil,
 		initseed:    seed,
 		optimal:     nil,
+		fingerprint: fp,
 	}
 	var err error
 	m.gh, err = slot.NewGame(gs, reg, m.core, isSim)
@@ -139,6 +144,8 @@ func newMachineWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.P
 		m.optimal = optimal
 	}
 
+	m.SpinResult.Fingerprint = m.fingerprint
+
 	return m, nil
 }
 
@@ -223,7 +230,7 @@ func (m *Machine) Spin(r *dto.SpinRequest) (dto.SpinResult, error) {
 	}
 
 	// 7. dto
-	return dto.NewSpinResultDTO(sr)
+	return dto.NewSpinResultDTOWith(sr, r.Verbosity)
 }
 
 // SpinInternal 直接取得內部 SpinResult；常用於模擬器或測試
@@ -266,6 +273,16 @@ func (m *Machine) valid(req *dto.SpinRequest) error {
 	if m.gameName != req.GameName {
 		return errs.NewWarn("game name is not matched")
 	}
+	if req.Variant != "" && req.Variant != m.variant {
+		return errs.NewWarn("game variant is not matched")
+	}
+	// 回放時帶入的指紋必須與本機台一致，避免用不同設定重現舊局
+	if req.Fingerprint != "" && req.Fingerprint != m.fingerprint {
+		return errs.NewWarn(fmt.Sprintf("config fingerprint mismatch: want %s, machine %s", req.Fingerprint, m.fingerprint))
+	}
+	if !req.Verbosity.Valid() {
+		return errs.NewWarn("invalid verbosity: " + string(req.Verbosity))
+	}
 	if req.BetMode < 0 || req.BetMode >= len(m.BetUnits) {
 		return errs.NewWarn("bet mode out of range")
 	}
@@ -44,6 +44,7 @@ type MachinePool struct {
 	initSeed      int64
 	seedMaker     *SeedMaker
 	optimalFS     fs.FS            // 優化文件系統（可選）
+	fp            string           // 設定檔指紋（建池時傳入，補機不重算）
 	pool          chan *Machine    // 可用機台的通道，用於取得和歸還機台
 	broken        chan *Machine    // 壞掉機台的通道，用於送修或丟棄壞掉機台
 	done          chan struct{}    // 關閉訊號：關閉後不再允許借機/歸還/補機
@@ -66,7 +67,7 @@ type MachinePool struct {
 // 初始化內容包含：
 //   - 建立 pool（可用機台）與 broken（壞機台）兩個 channel
 //   - 預先建立 n 台機台並放入 pool，以便立即提供服務
-func newMachinePool(n int, gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, optimalFS fs.FS) (*MachinePool, error) {
+func newMachinePool(n int, gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, optimalFS fs.FS, fp string) (*MachinePool, error) {
 	n = max(1, n) // 確保機台數量至少為1
 	p := &MachinePool{
 		gameName:  gs.GameName,
@@ -77,6 +78,7 @@ func newMachinePool(n int, gs *spec.GameSetting, reg *slot.LogicRegistry, cf cor
 		initSeed:  seed,
 		seedMaker: NewSeedMaker(seed),
 		optimalFS: optimalFS,
+		fp:        fp,
 		pool:      make(chan *Machine, n),   // 建立有緩衝的機台通道，容量為 n
 		broken:    make(chan *Machine, 100), // 建立有緩衝的壞掉機台通道，容量固定為100
 		done:      make(chan struct{}),
@@ -92,7 +94,7 @@ func newMachinePool(n int, gs *spec.GameSetting, reg *slot.LogicRegistry, cf cor
 
 	// 上架機台，將 n 台新機台放入池中
 	for i := 0; i < n; i++ {
-		m, err := newMachineWithSeed(gs, reg, cf, p.seedMaker.Next(), false, optimalFS)
+		m, err := newMachineWithSeed(gs, reg, cf, p.seedMaker.Next(), false, optimalFS, fp)
 		if err != nil {
 			return nil, err
 		}
@@ -213,7 +215,7 @@ func (p *MachinePool) Spin(ctx context.Context, req *dto.SpinRequest) (dto dto.S
 			}
 
 			// 2) 補一台新機台（維持容量）
-			newMachine, buildErr := newMachineWithSeed(p.gs, p.logic, p.cf, p.seedMaker.Next(), false, p.optimalFS)
+			newMachine, buildErr := newMachineWithSeed(p.gs, p.logic, p.cf, p.seedMaker.Next(), false, p.optimalFS, p.fp)
 			p.rebuild.Add(1)
 			if buildErr != nil {
 				err = errs.NewFatal(fmt.Sprintf("machine %s can not build", p.gameName))
@@ -291,6 +293,7 @@ func (mp *MachinePool) Fatals() int {
 type MachinePoolMetrics struct {
 	GameName string   `json:"game_name"`
 	GameID   spec.GID `json:"game_id"`
+	Variant  string   `json:"variant,omitempty"`
 
 	PoolSize      int    `json:"pool_size"`      // 目標容量（初始化指定）
 	Available     int    `json:"available"`      // 當下可借出的機台數（len(pool)）
@@ -312,6 +315,7 @@ func (mp *MachinePool) Metrics() MachinePoolMetrics {
 	return MachinePoolMetrics{
 		GameName:      mp.gameName,
 		GameID:        mp.gameId,
+		Variant:       mp.gs.Variant,
 		PoolSize:      mp.poolsize,
 		Available:     len(mp.pool),
 		Inflight:      int(mp.inflight.Load()),
@@ -0,0 +1,168 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package par 產生 PAR（Probability Accounting Report）表，供法規送審與合作方使用。
+//
+// 表格內容由 GameSetting（輪帶、賠付表）與統計結果（模擬或精確計算）組成，
+// 輸出為分段 CSV，可直接以試算表開啟。
+package par
+
+import (
+	"slices"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/sdk/buf"
+)
+
+// ComboKey 得分組合：模式 × 圖標 × 數量
+type ComboKey struct {
+	Mode   int   `json:"mode"`
+	Symbol int16 `json:"symbol"`
+	Count  int   `json:"count"`
+}
+
+// Combo 單一得分組合的命中頻率與 RTP 貢獻
+type Combo struct {
+	ComboKey
+	HitFreq float64 `json:"hit_freq"` // 每局至少命中一次的機率
+	AvgHits float64 `json:"avg_hits"` // 每局平均命中次數
+	RTP     float64 `json:"rtp"`      // 對總 RTP 的貢獻
+}
+
+// Feature 每局進入指定模式（免費遊戲等）的機率
+type Feature struct {
+	Mode int     `json:"mode"`
+	Rate float64 `json:"rate"`
+}
+
+type comboCount struct {
+	hits     int
+	spins    int
+	win      int
+	lastSpin int
+}
+
+// Collector 從 SpinResult 的算分細項累計每個得分組合的命中次數與贏分（模擬來源）
+//
+// 不可併發使用；多機台請各自建立後以 Merge 合併。
+type Collector struct {
+	Spins    int
+	TotalBet int
+
+	combos    map[ComboKey]*comboCount
+	modeSpins []int // 每個模式進入的局數
+	modeLast  []int
+}
+
+// NewCollector 建立收集器，modes 為遊戲模式數量
+func NewCollector(modes int) *Collector {
+	return &Collector{
+		combos:    make(map[ComboKey]*comboCount, 64),
+		modeSpins: make([]int, modes),
+		modeLast:  make([]int, modes),
+	}
+}
+
+// Record 紀錄一局結果
+func (c *Collector) Record(sr *buf.SpinResult) {
+	c.Spins++
+	c.TotalBet += sr.Bet
+	for _, gmr := range sr.GameModeList {
+		if id := gmr.GameModeId; id >= 0 && id < len(c.modeSpins) && c.modeLast[id] != c.Spins {
+			c.modeLast[id] = c.Spins
+			c.modeSpins[id]++
+		}
+		for i := range gmr.Details {
+			d := &gmr.Details[i]
+			if d.Win == 0 {
+				continue
+			}
+			k := ComboKey{Mode: gmr.GameModeId, Symbol: d.SymbolID, Count: d.Count}
+			cc, ok := c.combos[k]
+			if !ok {
+				cc = &comboCount{}
+				c.combos[k] = cc
+			}
+			cc.hits++
+			cc.win += d.Win
+			if cc.lastSpin != c.Spins {
+				cc.lastSpin = c.Spins
+				cc.spins++
+			}
+		}
+	}
+}
+
+// Merge 合併另一個收集器的計數
+func (c *Collector) Merge(o *Collector) error {
+	if len(o.modeSpins) != len(c.modeSpins) {
+		return errs.NewFatal("collector merge err: mode count mismatch")
+	}
+	c.Spins += o.Spins
+	c.TotalBet += o.TotalBet
+	for i, v := range o.modeSpins {
+		c.modeSpins[i] += v
+	}
+	for k, v := range o.combos {
+		cc, ok := c.combos[k]
+		if !ok {
+			cc = &comboCount{}
+			c.combos[k] = cc
+		}
+		cc.hits += v.hits
+		cc.spins += v.spins
+		cc.win += v.win
+	}
+	return nil
+}
+
+// Combos 依模式、圖標、數量排序回傳各組合的頻率與 RTP 貢獻
+func (c *Collector) Combos() []Combo {
+	out := make([]Combo, 0, len(c.combos))
+	if c.Spins == 0 {
+		return out
+	}
+	spins := float64(c.Spins)
+	for k, v := range c.combos {
+		cb := Combo{ComboKey: k, HitFreq: float64(v.spins) / spins, AvgHits: float64(v.hits) / spins}
+		if c.TotalBet > 0 {
+			cb.RTP = float64(v.win) / float64(c.TotalBet)
+		}
+		out = append(out, cb)
+	}
+	slices.SortFunc(out, func(a, b Combo) int { return compareKey(a.ComboKey, b.ComboKey) })
+	return out
+}
+
+// Features 回傳每個非主模式（id > 0）的進入機率
+func (c *Collector) Features() []Feature {
+	out := make([]Feature, 0, len(c.modeSpins))
+	if c.Spins == 0 {
+		return out
+	}
+	for id := 1; id < len(c.modeSpins); id++ {
+		out = append(out, Feature{Mode: id, Rate: float64(c.modeSpins[id]) / float64(c.Spins)})
+	}
+	return out
+}
+
+func compareKey(a, b ComboKey) int {
+	if a.Mode != b.Mode {
+		return a.Mode - b.Mode
+	}
+	if a.Symbol != b.Symbol {
+		return int(a.Symbol) - int(b.Symbol)
+	}
+	return a.Count - b.Count
+}
@@ -0,0 +1,393 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package par
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+	"github.com/zintix-labs/problab/stats"
+)
+
+// 組合數據來源
+const (
+	SourceSimulation = "simulation"
+	SourceExact      = "exact"
+)
+
+// VIZ 波動指數使用的信賴水準 z 值（90%）
+const VIZ float64 = 1.645
+
+// Input PAR 表的輸入
+type Input struct {
+	Setting  *spec.GameSetting
+	BetMode  int
+	Report   *stats.StatReport // 整體統計（RTP、波動、最大贏分）；nil 時略過 Summary 區段
+	Combos   []Combo           // 得分組合（模擬可由 Collector.Combos 取得，精確計算由呼叫端提供）
+	Features []Feature
+	Source   string // SourceSimulation / SourceExact
+	Seed     int64  // 模擬種子（僅記錄）
+}
+
+// Section 表格中的一個區段
+type Section struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Sheet PAR 表
+type Sheet struct {
+	Sections []Section
+}
+
+// Build 由設定與統計結果產生 PAR 表
+//
+// 區段：Game、Summary、Pay Table、Reel Symbol Counts、Reel Strips、Combinations、Features。
+func Build(in Input) (*Sheet, error) {
+	gs := in.Setting
+	if gs == nil {
+		return nil, errs.NewWarn("par build err: game setting required")
+	}
+	if in.BetMode < 0 || in.BetMode >= len(gs.BetUnits) {
+		return nil, errs.NewWarn("par build err: bet mode out of range")
+	}
+	// 優先使用統計報表上的指紋（含優化文件），否則以設定檔本身計算
+	fp := ""
+	if in.Report != nil && in.Report.Summary != nil {
+		fp = in.Report.Summary.Fingerprint
+	}
+	if fp == "" {
+		var err error
+		if fp, err = gs.Fingerprint(); err != nil {
+			return nil, errs.Wrap(err, "par build err: fingerprint")
+		}
+	}
+	sh := &Sheet{Sections: make([]Section, 0, 8)}
+	sh.add(gameSection(in, fp))
+	if in.Report != nil {
+		sh.add(summarySection(in))
+	}
+	for i := range gs.GameModeSettings {
+		sh.add(payTableSection(gs, i))
+	}
+	for i := range gs.GameModeSettings {
+		sh.add(reelCountSection(gs, i))
+	}
+	for i := range gs.GameModeSettings {
+		for j := range gs.GameModeSettings[i].GenScreenSetting.ReelSetGroup {
+			sh.add(reelStripSection(gs, i, j))
+		}
+	}
+	if len(in.Combos) > 0 {
+		sh.add(comboSection(in))
+	}
+	if len(in.Features) > 0 {
+		sh.add(featureSection(in))
+	}
+	return sh, nil
+}
+
+// WriteCSV 依序輸出所有區段：標題列、表頭、資料列，區段之間以空白列分隔
+func (sh *Sheet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	for i, s := range sh.Sections {
+		if i > 0 {
+			if err := cw.Write([]string{}); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write([]string{"# " + s.Title}); err != nil {
+			return err
+		}
+		if err := cw.Write(s.Header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(s.Rows); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Section 依標題取得區段
+func (sh *Sheet) Section(title string) (Section, bool) {
+	for _, s := range sh.Sections {
+		if s.Title == title {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+func (sh *Sheet) add(s Section) {
+	if len(s.Rows) > 0 {
+		sh.Sections = append(sh.Sections, s)
+	}
+}
+
+func gameSection(in Input, fp string) Section {
+	gs := in.Setting
+	rows := [][]string{
+		{"Game Name", gs.GameName},
+		{"Game ID", strconv.Itoa(int(gs.GameID))},
+		{"Logic", string(gs.LogicKey)},
+		{"Config Fingerprint", fp},
+		{"Bet Mode", strconv.Itoa(in.BetMode)},
+		{"Bet Unit", strconv.Itoa(gs.BetUnits[in.BetMode])},
+		{"Max Win Limit", strconv.Itoa(gs.MaxWinLimit)},
+		{"Game Modes", strconv.Itoa(len(gs.GameModeSettings))},
+	}
+	if in.Source != "" {
+		rows = append(rows, []string{"Data Source", in.Source})
+	}
+	if in.Source == SourceSimulation {
+		rows = append(rows, []string{"Seed", strconv.FormatInt(in.Seed, 10)})
+	}
+	return Section{Title: "Game", Header: []string{"Item", "Value"}, Rows: rows}
+}
+
+func summarySection(in Input) Section {
+	st := in.Report
+	st.Done()
+	s := st.Summary
+	rows := [][]string{
+		{"Rounds", strconv.Itoa(s.Rounds)},
+		{"RTP", ffmt(s.RTP)},
+		{"RTP 95% CI Low", ffmt(s.RtpCI.Lo)},
+		{"RTP 95% CI High", ffmt(s.RtpCI.Hi)},
+		{"Base Game RTP", ratio(s.BaseWin, s.TotalBet)},
+		{"Feature RTP", ratio(s.FreeWin, s.TotalBet)},
+		{"Hit Frequency", ffmt(s.HitRate)},
+		{"Hit Frequency (1 in N)", oneIn(s.HitRate)},
+		{"Feature Trigger Rate", ffmt(s.TriggerRate)},
+		{"Feature Trigger (1 in N)", oneIn(s.TriggerRate)},
+		{"Std Dev (x bet)", ffmt(s.Std)},
+		{"Volatility Index (90%)", ffmt(VIZ * s.Std)},
+		{"Max Win", strconv.Itoa(s.MaxWin)},
+		{"Max Win (x bet)", ffmt(s.MaxWinMult)},
+	}
+	return Section{Title: "Summary", Header: []string{"Item", "Value"}, Rows: rows}
+}
+
+func payTableSection(gs *spec.GameSetting, mode int) Section {
+	ss := &gs.GameModeSettings[mode].SymbolSetting
+	width := 0
+	for _, row := range ss.PayTable {
+		width = max(width, lastPaid(row)+1)
+	}
+	header := []string{"Mode", "Symbol", "Roles"}
+	for c := 1; c <= width; c++ {
+		header = append(header, strconv.Itoa(c))
+	}
+	rows := make([][]string, 0, len(ss.PayTable))
+	for s, row := range ss.PayTable {
+		if lastPaid(row) < 0 {
+			continue
+		}
+		r := []string{modeName(gs, mode), ss.SymbolUsedStr[s], strings.Join(ss.SymbolRoles[s].Names(), "|")}
+		for c := range width {
+			r = append(r, strconv.Itoa(row[c]))
+		}
+		rows = append(rows, r)
+	}
+	return Section{Title: fmt.Sprintf("Pay Table (%s)", modeName(gs, mode)), Header: header, Rows: rows}
+}
+
+func reelCountSection(gs *spec.GameSetting, mode int) Section {
+	m := &gs.GameModeSettings[mode]
+	names := m.SymbolSetting.SymbolUsedStr
+	header := append([]string{"Reel Set", "Name", "Weight", "Reel", "Stops", "Total Weight", "Measure"}, names...)
+	rows := make([][]string, 0)
+	for j, rs := range m.GenScreenSetting.ReelSetGroup {
+		for k, reel := range rs.Reels {
+			stops := make([]int, len(names))
+			weights := make([]int, len(names))
+			total, uniform := 0, true
+			for p, sym := range reel.ReelSymbols {
+				w := 1
+				if p < len(reel.ReelWeights) {
+					w = reel.ReelWeights[p]
+				}
+				if int(sym) >= 0 && int(sym) < len(names) {
+					stops[sym]++
+					weights[sym] += w
+				}
+				total += w
+				uniform = uniform && w == 1
+			}
+			base := []string{strconv.Itoa(j), rs.Name, strconv.Itoa(rs.Weight), strconv.Itoa(k + 1), strconv.Itoa(len(reel.ReelSymbols)), strconv.Itoa(total)}
+			rows = append(rows, append(append(base, "stops"), itoas(stops)...))
+			if !uniform {
+				rows = append(rows, append(append(append([]string(nil), base...), "weight"), itoas(weights)...))
+			}
+		}
+	}
+	return Section{Title: fmt.Sprintf("Reel Symbol Counts (%s)", modeName(gs, mode)), Header: header, Rows: rows}
+}
+
+func reelStripSection(gs *spec.GameSetting, mode, set int) Section {
+	m := &gs.GameModeSettings[mode]
+	rs := m.GenScreenSetting.ReelSetGroup[set]
+	names := m.SymbolSetting.SymbolUsedStr
+	header := []string{"Stop"}
+	length := 0
+	weighted := false
+	for k, reel := range rs.Reels {
+		header = append(header, fmt.Sprintf("Reel %d", k+1))
+		length = max(length, len(reel.ReelSymbols))
+		for _, w := range reel.ReelWeights {
+			weighted = weighted || w != 1
+		}
+	}
+	if weighted {
+		for k := range rs.Reels {
+			header = append(header, fmt.Sprintf("Weight %d", k+1))
+		}
+	}
+	rows := make([][]string, length)
+	for p := range length {
+		r := []string{strconv.Itoa(p)}
+		for _, reel := range rs.Reels {
+			cell := ""
+			if p < len(reel.ReelSymbols) {
+				if s := int(reel.ReelSymbols[p]); s >= 0 && s < len(names) {
+					cell = names[s]
+				}
+			}
+			r = append(r, cell)
+		}
+		if weighted {
+			for _, reel := range rs.Reels {
+				cell := ""
+				if p < len(reel.ReelWeights) {
+					cell = strconv.Itoa(reel.ReelWeights[p])
+				}
+				r = append(r, cell)
+			}
+		}
+		rows[p] = r
+	}
+	title := fmt.Sprintf("Reel Strips (%s / set %d)", modeName(gs, mode), set)
+	if rs.Name != "" {
+		title = fmt.Sprintf("Reel Strips (%s / set %d %s)", modeName(gs, mode), set, rs.Name)
+	}
+	return Section{Title: title, Header: header, Rows: rows}
+}
+
+// payColumn 回傳細項顆數對應的 pay_table 欄位（-1 表示無對應）；
+// cluster 的 Count 為集群大小，依 pay_tiers 換算並與算分一樣截斷到最後一欄
+func payColumn(hs *spec.HitSetting, count int, rowLen int) int {
+	if rowLen == 0 {
+		return -1
+	}
+	if spec.IsBetTypeCluster(hs.BetType) {
+		col := hs.Cluster.PayColumn(count)
+		if col < 0 {
+			return -1
+		}
+		return min(col, rowLen-1)
+	}
+	if count < 1 || count > rowLen {
+		return -1
+	}
+	return count - 1
+}
+
+func comboSection(in Input) Section {
+	gs := in.Setting
+	header := []string{"Mode", "Symbol", "Count", "Pay", "Hit Frequency", "1 in N", "Avg Hits per Spin", "RTP Contribution"}
+	rows := make([][]string, 0, len(in.Combos)+1)
+	sum := 0.0
+	for _, c := range in.Combos {
+		sym, pay := strconv.Itoa(int(c.Symbol)), ""
+		if c.Mode >= 0 && c.Mode < len(gs.GameModeSettings) {
+			gm := &gs.GameModeSettings[c.Mode]
+			ss := &gm.SymbolSetting
+			if int(c.Symbol) >= 0 && int(c.Symbol) < ss.SymbolCount {
+				sym = ss.SymbolUsedStr[c.Symbol]
+				if col := payColumn(&gm.HitSetting, c.Count, len(ss.PayTable[c.Symbol])); col >= 0 {
+					pay = strconv.Itoa(ss.PayTable[c.Symbol][col])
+				}
+			}
+		}
+		rows = append(rows, []string{modeName(gs, c.Mode), sym, strconv.Itoa(c.Count), pay, ffmt(c.HitFreq), oneIn(c.HitFreq), ffmt(c.AvgHits), ffmt(c.RTP)})
+		sum += c.RTP
+	}
+	if in.Report != nil {
+		// 不經由算分細項的贏分（例如邏輯直接給付的 scatter 獎金）
+		other := in.Report.Summary.RTP - sum
+		rows = append(rows, []string{"", "(other wins)", "", "", "", "", "", ffmt(other)})
+	}
+	return Section{Title: "Combinations", Header: header, Rows: rows}
+}
+
+func featureSection(in Input) Section {
+	gs := in.Setting
+	rows := make([][]string, 0, len(in.Features))
+	for _, f := range in.Features {
+		rows = append(rows, []string{modeName(gs, f.Mode), ffmt(f.Rate), oneIn(f.Rate)})
+	}
+	return Section{Title: "Features", Header: []string{"Mode", "Entry Rate", "1 in N"}, Rows: rows}
+}
+
+func modeName(gs *spec.GameSetting, mode int) string {
+	if mode >= 0 && mode < len(gs.GameModeSettings) && gs.GameModeSettings[mode].Name != "" {
+		return gs.GameModeSettings[mode].Name
+	}
+	return fmt.Sprintf("mode %d", mode)
+}
+
+func lastPaid(row []int) int {
+	for i := len(row) - 1; i >= 0; i-- {
+		if row[i] != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func itoas(v []int) []string {
+	out := make([]string, len(v))
+	for i, x := range v {
+		out[i] = strconv.Itoa(x)
+	}
+	return out
+}
+
+func ffmt(v float64) string {
+	return strconv.FormatFloat(v, 'g', 10, 64)
+}
+
+func ratio(a, b int) string {
+	if b == 0 {
+		return "0"
+	}
+	return ffmt(float64(a) / float64(b))
+}
+
+func oneIn(p float64) string {
+	if p <= 0 || math.IsNaN(p) {
+		return ""
+	}
+	return ffmt(1 / p)
+}
@@ -0,0 +1,146 @@
+package par
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/spec"
+)
+
+const parYAML = `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - name: base
+    screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0, 1, 0]
+            - symbols: [0, 1]
+            - {symbols: [0, 1], weights: [3, 1]}
+    symbol_setting:
+      symbol_used: [H1, W1]
+      pay_table: [[0, 0, 5], [0, 0, 0]]
+    hit_setting:
+      bet_type: way_ltr
+  - extends: base
+`
+
+func spin(bet int, modes ...*buf.GameModeResult) *buf.SpinResult {
+	return &buf.SpinResult{Bet: bet, GameModeList: modes}
+}
+
+func TestCollectorAndSheet(t *testing.T) {
+	gs, err := spec.GetGameSettingByYAML([]byte(parYAML))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	c := NewCollector(2)
+	hit := buf.CalcScreenDetail{Win: 50, SymbolID: 0, Count: 3}
+	c.Record(spin(10, &buf.GameModeResult{GameModeId: 0, Details: []buf.CalcScreenDetail{hit, hit}}))
+	c.Record(spin(10, &buf.GameModeResult{GameModeId: 0}, &buf.GameModeResult{GameModeId: 1, Details: []buf.CalcScreenDetail{hit}}))
+	c.Record(spin(10, &buf.GameModeResult{GameModeId: 0}))
+	o := NewCollector(2)
+	o.Record(spin(10, &buf.GameModeResult{GameModeId: 0, Details: []buf.CalcScreenDetail{hit}}))
+	if err := c.Merge(o); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	combos := c.Combos()
+	if len(combos) != 2 {
+		t.Fatalf("expected 2 combos, got %+v", combos)
+	}
+	base := combos[0]
+	if base.Mode != 0 || base.HitFreq != 0.5 || base.AvgHits != 0.75 || base.RTP != 150.0/40 {
+		t.Fatalf("unexpected base combo: %+v", base)
+	}
+	if f := c.Features(); len(f) != 1 || f[0].Rate != 0.25 {
+		t.Fatalf("unexpected features: %+v", f)
+	}
+
+	sh, err := Build(Input{Setting: gs, Combos: combos, Features: c.Features(), Source: SourceSimulation})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	counts, ok := sh.Section("Reel Symbol Counts (base)")
+	if !ok || len(counts.Rows) != 4 { // 3 軸 stops + 第 3 軸 weight
+		t.Fatalf("unexpected reel counts: %+v", counts)
+	}
+	if got := strings.Join(counts.Rows[0][7:], ","); got != "2,1" {
+		t.Fatalf("reel 1 symbol counts: %s", got)
+	}
+	if got := strings.Join(counts.Rows[3][6:], ","); got != "weight,3,1" {
+		t.Fatalf("reel 3 weights: %s", got)
+	}
+	var b bytes.Buffer
+	if err := sh.WriteCSV(&b); err != nil {
+		t.Fatalf("write csv failed: %v", err)
+	}
+	fp, _ := gs.Fingerprint()
+	if !strings.Contains(b.String(), "Config Fingerprint,"+fp) || !strings.Contains(b.String(), "base,H1,3,5,0.5,2,0.75,3.75") {
+		t.Fatalf("unexpected csv:\n%s", b.String())
+	}
+}
+
+// clusterParYAML 分段派彩：5-6 顆為第 1 欄，7 顆以上為第 2 欄
+const clusterParYAML = `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 3}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0, 1]
+            - symbols: [0, 1]
+            - symbols: [0, 1]
+    symbol_setting:
+      symbol_used: [H1, H2]
+      pay_table: [[10, 30], [0, 1]]
+    hit_setting:
+      bet_type: cluster
+      cluster: {pay_tiers: [5, 7]}
+`
+
+func TestComboPayClusterTiers(t *testing.T) {
+	gs, err := spec.GetGameSettingByYAML([]byte(clusterParYAML))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	combo := func(sym int16, count int) Combo {
+		return Combo{ComboKey: ComboKey{Symbol: sym, Count: count}}
+	}
+	sh, err := Build(Input{Setting: gs, Combos: []Combo{combo(0, 5), combo(0, 6), combo(0, 9), combo(1, 4)}, Source: SourceSimulation})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	sec, ok := sh.Section("Combinations")
+	if !ok || len(sec.Rows) != 4 {
+		t.Fatalf("unexpected combinations: %+v", sec)
+	}
+	for i, want := range []string{"10", "10", "30", ""} {
+		if got := sec.Rows[i][3]; got != want {
+			t.Fatalf("row %d (count %s): pay %q, want %q", i, sec.Rows[i][2], got, want)
+		}
+	}
+
+	// 無分段時集群大小逐顆對應，超出列長以最後一欄派彩
+	hs := &spec.HitSetting{BetType: spec.BetTypeCluster}
+	if c := payColumn(hs, 9, 3); c != 2 {
+		t.Fatalf("clamped column %d, want 2", c)
+	}
+	if c := payColumn(&gs.GameModeSettings[0].HitSetting, 4, 2); c != -1 {
+		t.Fatalf("below first tier must have no column, got %d", c)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package problab
+
+import (
+	"fmt"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/par"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// PARPlan PAR 表模擬設定
+type PARPlan struct {
+	GID     spec.GID
+	BetMode int
+	Rounds  int   // 每個 worker 的局數（與 SimMP 一致）
+	Workers int   // 併發機台數
+	Seed    int64 // 模擬種子
+}
+
+// PARSheet 以模擬結果產生指定遊戲 × bet mode 的 PAR 表
+func (p *Problab) PARSheet(plan PARPlan) (*par.Sheet, error) {
+	if plan.Workers < 1 {
+		return nil, errs.NewWarn("workers must > 0")
+	}
+	gs, err := p.cat.GameSettingById(plan.GID)
+	if err != nil {
+		return nil, err
+	}
+	sim, err := p.NewSimulatorWithSeed(plan.GID, plan.Seed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("build simulator err: %d", plan.GID))
+	}
+	st, col, _, err := sim.SimPAR(plan.BetMode, plan.Rounds, plan.Workers, false)
+	if err != nil {
+		return nil, err
+	}
+	return par.Build(par.Input{
+		Setting:  gs,
+		BetMode:  plan.BetMode,
+		Report:   st,
+		Combos:   col.Combos(),
+		Features: col.Features(),
+		Source:   par.SourceSimulation,
+		Seed:     plan.Seed,
+	})
+}
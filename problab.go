@@ -105,6 +105,9 @@ type Problab struct {
 	cf        core.PRNGFactory
 	sum       []catalog.Summary
 	optimalFS fs.FS
+	rules     spec.RuleLevels   // 語意規則嚴重度覆寫
+	warnings  []spec.Violation  // RegisterAll 收集到的非致命違規
+	opVariant map[string]string // 營運商 -> 預設變體名稱（SlotRuntime 路由用）
 }
 
 // ProblabOption 是 Problab 的選項函數類型。
@@ -118,6 +121,27 @@ func WithOptimalFS(optimalFS fs.FS) ProblabOption {
 	}
 }
 
+// WithRuleLevels 覆寫語意規則（spec.Rules）的嚴重度；errs.None 代表停用該規則。
+// Invariant 規則不可降級（New 會回傳錯誤）。
+func WithRuleLevels(levels spec.RuleLevels) ProblabOption {
+	return func(p *Problab) {
+		p.rules = levels
+	}
+}
+
+// WithOperatorVariants 設定各營運商的預設變體（operator -> variant）。
+//
+// SlotRuntime 路由 Spin 時，SpinRequest 未指定 variant 會先套用 operator 的預設變體；
+// 該遊戲沒有這個變體時，退回遊戲的預設變體。
+func WithOperatorVariants(m map[string]string) ProblabOption {
+	return func(p *Problab) {
+		p.opVariant = make(map[string]string, len(m))
+		for op, v := range m {
+			p.opVariant[op] = v
+		}
+	}
+}
+
 // New 建立一個 Problab instance。
 //
 // 這是「組裝階段（registration/build）」的入口：
@@ -160,6 +184,9 @@ func New(cf core.PRNGFactory, cfgs []fs.FS, logics []*slot.LogicRegistry, opts .
 	for _, opt := range opts {
 		opt(lab)
 	}
+	if err := lab.rules.Valid(); err != nil {
+		return nil, err
+	}
 
 	return lab, nil
 }
@@ -192,7 +219,9 @@ func (p *Problab) Register(ents ...catalog.Entry) error {
 //  1. Fail-fast：任何一個檔案讀取/解析/基本檢查失敗，都會立刻回傳 error（不會忽略、也不會繼續掃完）。
 //  2. 原子性：只有當「全部檔案」都成功解析並通過基本檢查時，才會呼叫 Register(...) 一次性寫入。
 //     因此不會出現只註冊了一半、導致 catalog 處於半完成狀態的情況。
-//  3. 穩定性：會依檔名排序後再處理，確保行為 determinism（方便重現與除錯）。
+//  3. 穩定性：會依路徑字典序遞迴處理，確保行為 determinism（方便重現與除錯）。
+//  4. 命名空間：子目錄即命名空間（例如 studio_a/game_12.yaml），不同目錄可有同名檔案；
+//     重複檢查只看 GID（含變體）與遊戲名稱，錯誤訊息帶上設定檔在來源中的路徑。隱藏目錄與檔案（. 開頭）會略過。
 //
 // 注意：
 //   - RegisterAll 只負責「把設定檔宣告的遊戲資訊放進 Catalog」。
@@ -206,89 +235,117 @@ func (p *Problab) RegisterAll() error {
 	}
 
 	entries := make([]catalog.Entry, 0, 64)
-	seenID := map[spec.GID]string{}
-	seenName := map[string]string{}
+	violations := make([]spec.Violation, 0)
+	type variantKey struct {
+		id      spec.GID
+		variant string
+	}
+	seenID := map[variantKey]string{}
+	seenName := map[string]spec.GID{}
+	seenGID := map[spec.GID]string{}
 
 	for _, src := range sources {
 		walkErr := fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
-			if d.IsDir() {
-				if path == "." {
-					return nil
+			// 子目錄即命名空間（例如 studio_a/game_12.yaml）；隱藏目錄與隱藏檔略過
+			if strings.HasPrefix(d.Name(), ".") && path != "." {
+				if d.IsDir() {
+					return fs.SkipDir
 				}
-				return errs.NewFatal(fmt.Sprintf("configs must be flat (no subdir): %q", path))
-			}
-
-			base := filepath.Base(path)
-			if strings.Contains(path, "/") && path != base {
-				return errs.NewFatal(fmt.Sprintf("configs must be flat (nested path): %q", path))
+				return nil
 			}
-			if strings.HasPrefix(base, ".") {
+			if d.IsDir() {
 				return nil
 			}
 
-			ext := strings.ToLower(filepath.Ext(base))
+			ext := strings.ToLower(filepath.Ext(path))
 			if ext != ".yaml" && ext != ".yml" && ext != ".json" {
 				return nil
 			}
 
 			raw, rerr := fs.ReadFile(src, path)
 			if rerr != nil {
-				return errs.NewFatal(fmt.Sprintf("read config failed: %s", base))
+				return errs.NewFatal(fmt.Sprintf("read config failed: %s", path))
 			}
 
-			var (
-				gs   *spec.GameSetting
-				gerr error
-			)
-			switch ext {
-			case ".yaml", ".yml":
-				gs, gerr = spec.GetGameSettingByYAML(raw)
-			case ".json":
-				gs, gerr = spec.GetGameSettingByJSON(raw)
-			default:
-				return errs.NewFatal(fmt.Sprintf("unsupported config format: %q", base))
+			// 共用區塊檔（只有 includes / library）由其他設定檔引入，本身不是遊戲
+			if spec.IsLibraryConfig(raw) {
+				return nil
 			}
+			gs, gerr := spec.GetGameSettingFromFS(src, path)
 			if gerr != nil {
-				return errs.NewFatal(fmt.Sprintf("parse gamesetting failed: %s %s", base, gerr.Error()))
+				// 附上逐項問題（含行號與 YAML 路徑），避免只看到第一個錯誤
+				issues := spec.ValidateConfig(raw, path, spec.FSLoader(src), p.rules)
+				if len(issues) == 0 {
+					return errs.NewFatal(fmt.Sprintf("parse gamesetting failed: %s %s", path, gerr.Error()))
+				}
+				lines := make([]string, len(issues))
+				for i, ci := range issues {
+					ci.File = path
+					lines[i] = ci.String()
+				}
+				return errs.NewFatal(fmt.Sprintf("parse gamesetting failed: %s\n%s", path, strings.Join(lines, "\n")))
+			}
+
+			// 語意規則：收集全部違規，走完所有設定檔後再一次回報
+			for _, v := range spec.CheckRules(gs, p.rules) {
+				v.File = path
+				violations = append(violations, v)
 			}
 
 			name := strings.TrimSpace(gs.GameName)
 			if name == "" {
-				return errs.NewFatal(fmt.Sprintf("game name required: %s", base))
+				return errs.NewFatal(fmt.Sprintf("game name required: %s", path))
 			}
 
+			// 同一個 game_id 可有多個變體（variant），但 (game_id, variant) 必須唯一，且變體間共用同一個遊戲名稱
 			id := spec.GID(gs.GameID)
-			if prev, ok := seenID[id]; ok {
-				return errs.NewFatal(fmt.Sprintf("duplicate game id: %d (config=%s and %s)", id, prev, base))
+			key := variantKey{id: id, variant: gs.Variant}
+			if prev, ok := seenID[key]; ok {
+				if gs.Variant == "" {
+					return errs.NewFatal(fmt.Sprintf("duplicate game id: %d (config=%s and %s)", id, prev, path))
+				}
+				return errs.NewFatal(fmt.Sprintf("duplicate game variant: %d/%s (config=%s and %s)", id, gs.Variant, prev, path))
 			}
 			if _, ok := p.cat.GetByID(id); ok {
-				return errs.NewFatal(fmt.Sprintf("game id already registered: %d (config=%s)", id, base))
+				return errs.NewFatal(fmt.Sprintf("game id already registered: %d (config=%s)", id, path))
 			}
-			seenID[id] = base
+			seenID[key] = path
 
 			nameKey := strings.ToLower(name)
-			if prev, ok := seenName[nameKey]; ok {
-				return errs.NewFatal(fmt.Sprintf("duplicate game name: %s (config=%s and %s)", nameKey, prev, base))
+			if gid, ok := seenName[nameKey]; ok && gid != id {
+				return errs.NewFatal(fmt.Sprintf("duplicate game name: %s (config=%s and game id %d)", nameKey, path, gid))
+			}
+			if prev, ok := seenGID[id]; ok && prev != nameKey {
+				return errs.NewFatal(fmt.Sprintf("game name mismatch across variants: game id %d has %s and %s (config=%s)", id, prev, nameKey, path))
 			}
 			if _, ok := p.cat.GetByName(name); ok {
-				return errs.NewFatal(fmt.Sprintf("game name already registered: %s (config=%s)", name, base))
+				return errs.NewFatal(fmt.Sprintf("game name already registered: %s (config=%s)", name, path))
 			}
-			seenName[nameKey] = base
+			seenName[nameKey] = id
+			seenGID[id] = nameKey
 
 			if gs.LogicKey == "" {
-				return errs.NewFatal(fmt.Sprintf("logic key required: %s", base))
+				return errs.NewFatal(fmt.Sprintf("logic key required: %s", path))
 			}
 			if !p.reg.IsExist(gs.LogicKey) {
-				return errs.NewFatal(fmt.Sprintf("logic not registered: logic_key=%s (config=%s)", gs.LogicKey, base))
+				return errs.NewFatal(fmt.Sprintf("logic not registered: logic_key=%s (config=%s)", gs.LogicKey, path))
+			}
+
+			fp, ferr := ConfigFingerprint(gs, p.optimalFS)
+			if ferr != nil {
+				return errs.Wrap(ferr, fmt.Sprintf("fingerprint failed: %s", path))
 			}
 
 			entries = append(entries, catalog.Entry{
-				GID:        id,
-				Name:       name,
-				ConfigName: base,
+				GID:         id,
+				Name:        name,
+				ConfigName:  path,
+				Variant:     gs.Variant,
+				RTP:         gs.RTP,
+				Fingerprint: fp,
 			})
 			return nil
 		})
@@ -297,11 +354,34 @@ func (p *Problab) RegisterAll() error {
 		}
 	}
 
+	if spec.HasFatal(violations) {
+		lines := make([]string, len(violations))
+		for i, v := range violations {
+			lines[i] = v.String()
+		}
+		return errs.NewFatal(fmt.Sprintf("game setting rule violations (%d):\n%s", len(violations), strings.Join(lines, "\n")))
+	}
+
 	if len(entries) == 0 {
 		return errs.NewFatal("no config files found to register")
 	}
+	// 有變體的遊戲必須有一份不帶 variant 的預設設定檔
+	for _, e := range entries {
+		if _, ok := seenID[variantKey{id: e.GID}]; !ok {
+			return errs.NewFatal(fmt.Sprintf("missing default variant: game id %d has variant %q but no config without variant (config=%s)", e.GID, e.Variant, e.ConfigName))
+		}
+	}
+
+	if err := p.cat.Register(entries...); err != nil {
+		return err
+	}
+	p.warnings = append(p.warnings, violations...)
+	return nil
+}
 
-	return p.cat.Register(entries...)
+// Warnings 回傳 RegisterAll 收集到的非致命規則違規（Warn / Log 等級）。
+func (p *Problab) Warnings() []spec.Violation {
+	return p.warnings
 }
 
 func (p *Problab) Freeze() {
@@ -334,15 +414,35 @@ func (p *Problab) Summary() ([]catalog.Summary, error) {
 	ids := p.cat.IDs()
 	cs := make([]catalog.Summary, 0, len(ids))
 	for _, id := range ids {
-		gs, err := p.cat.GameSettingById(id)
-		if err != nil {
-			return nil, errs.NewFatal("parse game setting failed")
-		}
-		s := catalog.Summary{
-			GID:      id,
-			Name:     gs.GameName,
-			Logic:    gs.LogicKey,
-			BetUnits: gs.BetUnits,
+		var s catalog.Summary
+		// 變體依 Variant 排序，第一個為預設變體，Summary 的基本欄位取自預設變體
+		for i, ent := range p.cat.Variants(id) {
+			gs, err := p.cat.GameSettingByEntry(ent)
+			if err != nil {
+				return nil, errs.NewFatal("parse game setting failed")
+			}
+			// 手動 Register 的 Entry 沒有指紋，於此補算
+			fp := ent.Fingerprint
+			if fp == "" {
+				if fp, err = ConfigFingerprint(gs, p.optimalFS); err != nil {
+					return nil, err
+				}
+			}
+			if i == 0 {
+				s = catalog.Summary{
+					GID:         id,
+					Name:        gs.GameName,
+					Logic:       gs.LogicKey,
+					BetUnits:    gs.BetUnits,
+					Fingerprint: fp,
+				}
+			}
+			s.Variants = append(s.Variants, catalog.VariantSummary{
+				Variant:     ent.Variant,
+				RTP:         gs.RTP,
+				Fingerprint: fp,
+				Default:     i == 0,
+			})
 		}
 		cs = append(cs, s)
 	}
@@ -364,11 +464,11 @@ func (p *Problab) NewMachine(id spec.GID, isSim bool) (*Machine, error) {
 	if !p.cat.IsFrozen() {
 		return nil, errs.NewFatal("catalog is not frozen yet")
 	}
-	gs, err := p.cat.GameSettingById(id)
+	gs, fp, err := p.variantSetting(id, "")
 	if err != nil {
 		return nil, err
 	}
-	return newMachine(gs, p.reg, p.cf, isSim, p.optimalFS)
+	return newMachine(gs, p.reg, p.cf, isSim, p.optimalFS, fp)
 }
 
 // NewMachineWithSeed 與 NewMachine 相同，但由呼叫端指定初始 seed。
@@ -381,11 +481,23 @@ func (p *Problab) NewMachineWithSeed(id spec.GID, seed int64, isSim bool) (*Mach
 	if !p.cat.IsFrozen() {
 		return nil, errs.NewFatal("catalog is not frozen yet")
 	}
-	gs, err := p.cat.GameSettingById(id)
+	gs, fp, err := p.variantSetting(id, "")
 	if err != nil {
 		return nil, err
 	}
-	return newMachineWithSeed(gs, p.reg, p.cf, seed, isSim, p.optimalFS)
+	return newMachineWithSeed(gs, p.reg, p.cf, seed, isSim, p.optimalFS, fp)
+}
+
+// NewMachineByVariant 與 NewMachineWithSeed 相同，但建立指定變體的機台（variant 為空字串時使用預設變體）。
+func (p *Problab) NewMachineByVariant(id spec.GID, variant string, seed int64, isSim bool) (*Machine, error) {
+	if !p.cat.IsFrozen() {
+		return nil, errs.NewFatal("catalog is not frozen yet")
+	}
+	gs, fp, err := p.variantSetting(id, variant)
+	if err != nil {
+		return nil, err
+	}
+	return newMachineWithSeed(gs, p.reg, p.cf, seed, isSim, p.optimalFS, fp)
 }
 
 func (p *Problab) NewMachineByJSON(raw []byte, seed int64) (*Machine, error) {
@@ -399,7 +511,11 @@ func (p *Problab) NewMachineByJSON(raw []byte, seed int64) (*Machine, error) {
 	if err := p.validCfg(cfg); err != nil {
 		return nil, err
 	}
-	return newMachineWithSeed(cfg, p.reg, p.cf, seed, true, p.optimalFS)
+	fp, err := ConfigFingerprint(cfg, p.optimalFS)
+	if err != nil {
+		return nil, err
+	}
+	return newMachineWithSeed(cfg, p.reg, p.cf, seed, true, p.optimalFS, fp)
 }
 
 func (p *Problab) NewMachineByYAML(raw []byte, seed int64) (*Machine, error) {
@@ -413,7 +529,42 @@ func (p *Problab) NewMachineByYAML(raw []byte, seed int64) (*Machine, error) {
 	if err := p.validCfg(cfg); err != nil {
 		return nil, err
 	}
-	return newMachineWithSeed(cfg, p.reg, p.cf, seed, true, p.optimalFS)
+	fp, err := ConfigFingerprint(cfg, p.optimalFS)
+	if err != nil {
+		return nil, err
+	}
+	return newMachineWithSeed(cfg, p.reg, p.cf, seed, true, p.optimalFS, fp)
+}
+
+// variantSetting 讀取指定變體（空字串為預設變體）的設定與指紋
+func (p *Problab) variantSetting(id spec.GID, variant string) (*spec.GameSetting, string, error) {
+	ent, ok := p.cat.GetVariant(id, variant)
+	if !ok {
+		if variant == "" {
+			return nil, "", errs.NewWarn("id dose not exist in catalog")
+		}
+		return nil, "", errs.NewWarn(fmt.Sprintf("variant dose not exist in catalog: gid %d variant %q", id, variant))
+	}
+	return p.entrySetting(ent)
+}
+
+// entrySetting 讀取目錄項目的設定與指紋
+//
+// 指紋在 RegisterAll 時已計算並存於 Entry（每個項目只算一次，建機台 / 補機不再重算）；
+// 以 Register 手動註冊且未帶指紋的項目才在此補算。
+func (p *Problab) entrySetting(ent catalog.Entry) (*spec.GameSetting, string, error) {
+	gs, err := p.cat.GameSettingByEntry(ent)
+	if err != nil {
+		return nil, "", err
+	}
+	if ent.Fingerprint != "" {
+		return gs, ent.Fingerprint, nil
+	}
+	fp, err := ConfigFingerprint(gs, p.optimalFS)
+	if err != nil {
+		return nil, "", err
+	}
+	return gs, fp, nil
 }
 
 func (p *Problab) validCfg(cfg *spec.GameSetting) error {
@@ -438,22 +589,34 @@ func (p *Problab) NewSimulator(id spec.GID) (*Simulator, error) {
 	if !p.cat.IsFrozen() {
 		return nil, errs.NewFatal("catalog is not frozen yet")
 	}
-	gs, err := p.cat.GameSettingById(id)
+	gs, fp, err := p.variantSetting(id, "")
 	if err != nil {
 		return nil, err
 	}
-	return newSimulator(gs, p.reg, p.cf, p.optimalFS)
+	return newSimulator(gs, p.reg, p.cf, p.optimalFS, fp)
 }
 
 func (p *Problab) NewSimulatorWithSeed(id spec.GID, seed int64) (*Simulator, error) {
 	if !p.cat.IsFrozen() {
 		return nil, errs.NewFatal("catalog is not frozen yet")
 	}
-	gs, err := p.cat.GameSettingById(id)
+	gs, fp, err := p.variantSetting(id, "")
+	if err != nil {
+		return nil, err
+	}
+	return newSimulatorWithSeed(gs, p.reg, p.cf, seed, p.optimalFS, fp)
+}
+
+// NewSimulatorByVariant 與 NewSimulatorWithSeed 相同，但模擬指定變體（variant 為空字串時使用預設變體）。
+func (p *Problab) NewSimulatorByVariant(id spec.GID, variant string, seed int64) (*Simulator, error) {
+	if !p.cat.IsFrozen() {
+		return nil, errs.NewFatal("catalog is not frozen yet")
+	}
+	gs, fp, err := p.variantSetting(id, variant)
 	if err != nil {
 		return nil, err
 	}
-	return newSimulatorWithSeed(gs, p.reg, p.cf, seed, p.optimalFS)
+	return newSimulatorWithSeed(gs, p.reg, p.cf, seed, p.optimalFS, fp)
 }
 
 func (p *Problab) NewSimulatorByJSON(raw []byte, seed int64) (*Simulator, error) {
@@ -467,7 +630,11 @@ func (p *Problab) NewSimulatorByJSON(raw []byte, seed int64) (*Simulator, error)
 	if err := p.validCfg(cfg); err != nil {
 		return nil, err
 	}
-	return newSimulatorWithSeed(cfg, p.reg, p.cf, seed, p.optimalFS)
+	fp, err := ConfigFingerprint(cfg, p.optimalFS)
+	if err != nil {
+		return nil, err
+	}
+	return newSimulatorWithSeed(cfg, p.reg, p.cf, seed, p.optimalFS, fp)
 }
 
 func (p *Problab) NewSimulatorByYAML(raw []byte, seed int64) (*Simulator, error) {
@@ -481,7 +648,11 @@ func (p *Problab) NewSimulatorByYAML(raw []byte, seed int64) (*Simulator, error)
 	if err := p.validCfg(cfg); err != nil {
 		return nil, err
 	}
-	return newSimulatorWithSeed(cfg, p.reg, p.cf, seed, p.optimalFS)
+	fp, err := ConfigFingerprint(cfg, p.optimalFS)
+	if err != nil {
+		return nil, err
+	}
+	return newSimulatorWithSeed(cfg, p.reg, p.cf, seed, p.optimalFS, fp)
 }
 
 func (p *Problab) BuildRuntime(poolSize int) (*SlotRuntime, error) {
@@ -495,7 +666,7 @@ func (p *Problab) BuildRuntime(poolSize int) (*SlotRuntime, error) {
 
 	rt := &SlotRuntime{
 		pb:       p,
-		pools:    make(map[spec.GID]*MachinePool, len(ids)),
+		pools:    make(map[spec.GID][]*MachinePool, len(ids)),
 		ids:      ids,
 		done:     make(chan struct{}),
 		poolSize: max(1, poolSize),
@@ -504,22 +675,24 @@ func (p *Problab) BuildRuntime(poolSize int) (*SlotRuntime, error) {
 	}
 	rt.reason.Store("")
 
-	// 2. 先全建好（fail-fast + cleanup）
+	// 2. 先全建好（fail-fast + cleanup）；每個變體一個 pool，順序與 cat.Variants 一致（[0] 為預設變體）
 	for _, id := range ids {
-		gs, err := p.cat.GameSettingById(id)
-		if err != nil {
-			return nil, err
-		}
+		for _, ent := range p.cat.Variants(id) {
+			gs, fp, err := p.entrySetting(ent)
+			if err != nil {
+				return nil, err
+			}
 
-		seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-		if err != nil {
-			return nil, errs.NewFatal("rand seed failed: " + err.Error())
-		}
-		mp, err := newMachinePool(rt.poolSize, gs, p.reg, p.cf, seed.Int64(), p.optimalFS)
-		if err != nil {
-			return nil, err
+			seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+			if err != nil {
+				return nil, errs.NewFatal("rand seed failed: " + err.Error())
+			}
+			mp, err := newMachinePool(rt.poolSize, gs, p.reg, p.cf, seed.Int64(), p.optimalFS, fp)
+			if err != nil {
+				return nil, err
+			}
+			rt.pools[id] = append(rt.pools[id], mp)
 		}
-		rt.pools[id] = mp
 	}
 	rt.Health() // set health data
 	return rt, nil
@@ -0,0 +1,58 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/stats"
+)
+
+// EncodeSpinRecorder 將紀錄員狀態序列化為 JSON，供跨行程傳遞後再以 MergeSpinRecorder 合併。
+//
+// 注意：Dist.Bucket 屬於可由 BetUnit 重建的查表結構，不會被序列化。
+func EncodeSpinRecorder(s *SpinRecorder) ([]byte, error) {
+	if s == nil || s.Basic == nil || s.Dist == nil || s.Player == nil {
+		return nil, errs.NewFatal("encode spin record err : incomplete record")
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, errs.Wrap(err, "encode spin record err")
+	}
+	return b, nil
+}
+
+// DecodeSpinRecorder 由 EncodeSpinRecorder 的輸出還原紀錄員，並重建 Dist.Bucket。
+func DecodeSpinRecorder(raw []byte) (*SpinRecorder, error) {
+	s := new(SpinRecorder)
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, errs.Wrap(err, "decode spin record err")
+	}
+	if s.Basic == nil || s.Dist == nil || s.Player == nil {
+		return nil, errs.NewFatal("decode spin record err : incomplete record")
+	}
+	if s.BetMode < 0 || s.BetMode >= len(s.BetUnits) || s.BetUnit <= 0 {
+		return nil, errs.NewFatal(fmt.Sprintf("decode spin record err : betMode %d / betunits %v", s.BetMode, s.BetUnits))
+	}
+	n := len(stats.Buckets.WinBucketStr())
+	if len(s.Dist.TotalWinCollect) != n || len(s.Dist.BaseWinCollect) != n || len(s.Dist.FreeWinCollect) != n {
+		return nil, errs.NewFatal("decode spin record err : dist bucket size mismatch")
+	}
+	s.Dist.Bucket = stats.Buckets.GetBucketByBetUnit(s.BetUnit)
+	s.Player.leaveLine = 3 * s.Player.InitBalance
+	return s, nil
+}
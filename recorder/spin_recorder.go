@@ -27,50 +27,53 @@ import (
 //
 // SpinRecorder 負責紀錄遊戲結果，並透過Done輸出統計報表
 type SpinRecorder struct {
-	GameName string
-	GameId   spec.GID
-	BetUnits []int
-	BetUnit  int
-	BetMode  int
-	InitBets int
-	Basic    *BasicRecord
-	Dist     *DistRecord
-	Player   *PlayerRecord
+	GameName string        `json:"game_name"`
+	GameId   spec.GID      `json:"game_id"`
+	BetUnits []int         `json:"bet_units"`
+	BetUnit  int           `json:"bet_unit"`
+	BetMode  int           `json:"bet_mode"`
+	InitBets int           `json:"init_bets"`
+	Basic    *BasicRecord  `json:"basic"`
+	Dist     *DistRecord   `json:"dist"`
+	Player   *PlayerRecord `json:"player"`
+	// Fingerprint 設定檔指紋（由模擬器填入，合併時必須一致，Done 時帶入 SummaryReport）
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // BasicRecord 基本遊戲資料紀錄
 type BasicRecord struct {
-	TotalBet      int
-	TotalWin      int
-	BaseWin       int
-	FreeWin       int
-	TotalWinSqSum int // 平方和
-	BaseWinSqSum  int // 平方和
-	FreeWinSqSum  int // 平方和
-	Trigger       int
-	Rounds        int
+	TotalBet      int `json:"total_bet"`
+	TotalWin      int `json:"total_win"`
+	BaseWin       int `json:"base_win"`
+	FreeWin       int `json:"free_win"`
+	TotalWinSqSum int `json:"total_win_sq_sum"` // 平方和
+	BaseWinSqSum  int `json:"base_win_sq_sum"`  // 平方和
+	FreeWinSqSum  int `json:"free_win_sq_sum"`  // 平方和
+	Trigger       int `json:"trigger"`
+	Rounds        int `json:"rounds"`
+	MaxWin        int `json:"max_win"` // 單局最高贏分
 }
 
 // DistRecord 分數區間落點統計
 //
 // 紀錄時紀錄int資訊
 type DistRecord struct {
-	Bucket          *stats.WinBucket
-	TotalWinCollect []int
-	BaseWinCollect  []int
-	FreeWinCollect  []int
+	Bucket          *stats.WinBucket `json:"-"` // 由 BetUnit 決定，反序列化後重建
+	TotalWinCollect []int            `json:"total_win_collect"`
+	BaseWinCollect  []int            `json:"base_win_collect"`
+	FreeWinCollect  []int            `json:"free_win_collect"`
 }
 
 // PlayerRecord 玩家統計
 type PlayerRecord struct {
 	leaveLine   int
-	InitBalance int
-	Balance     int
-	MaxBalance  int
-	MinBalance  int
-	Bust        bool
-	Cashout     bool
-	Alive       bool
+	InitBalance int  `json:"init_balance"`
+	Balance     int  `json:"balance"`
+	MaxBalance  int  `json:"max_balance"`
+	MinBalance  int  `json:"min_balance"`
+	Bust        bool `json:"bust"`
+	Cashout     bool `json:"cashout"`
+	Alive       bool `json:"alive"`
 }
 
 func NewSpinRecorder(name string, id spec.GID, betUnits []int, initBets int, betMode int) (*SpinRecorder, error) {
@@ -108,15 +111,25 @@ func NewSpinRecorder(name string, id spec.GID, betUnits []int, initBets int, bet
 }
 
 func MergeSpinRecorder(r []*SpinRecorder) (*SpinRecorder, error) {
+	if len(r) == 0 || r[0] == nil {
+		return nil, errs.NewFatal("merge spin record err : empty records")
+	}
 	r0 := r[0]
 	s, err := NewSpinRecorder(r0.GameName, r0.GameId, r0.BetUnits, r0.InitBets, r0.BetMode)
 	if err != nil {
 		return s, err
 	}
+	s.Fingerprint = r0.Fingerprint
 	for _, v := range r {
+		if v == nil || v.Basic == nil || v.Dist == nil {
+			return s, errs.NewFatal("merge spin record err : nil record")
+		}
 		if v.GameName != r0.GameName {
 			return s, errs.NewFatal("merge spin record err : different game name")
 		}
+		if len(v.BetUnits) != len(r0.BetUnits) {
+			return s, errs.NewFatal("merge spin record err : different betunits")
+		}
 		for i, b := range v.BetUnits {
 			if b != r0.BetUnits[i] {
 				return s, errs.NewFatal("merge spin record err : different betunits")
@@ -128,6 +141,9 @@ func MergeSpinRecorder(r []*SpinRecorder) (*SpinRecorder, error) {
 		if v.BetMode != r0.BetMode {
 			return s, errs.NewFatal("merge spin record err : different betmode")
 		}
+		if v.Fingerprint != r0.Fingerprint {
+			return s, errs.NewFatal("merge spin record err : different config fingerprint")
+		}
 		s.Basic.TotalBet += v.Basic.TotalBet
 		s.Basic.TotalWin += v.Basic.TotalWin
 		s.Basic.BaseWin += v.Basic.BaseWin
@@ -137,8 +153,14 @@ func MergeSpinRecorder(r []*SpinRecorder) (*SpinRecorder, error) {
 		s.Basic.FreeWinSqSum += v.Basic.FreeWinSqSum
 		s.Basic.Rounds += v.Basic.Rounds
 		s.Basic.Trigger += v.Basic.Trigger
+		s.Basic.MaxWin = max(s.Basic.MaxWin, v.Basic.MaxWin)
 
 		// 整合Dist
+		if len(v.Dist.TotalWinCollect) != len(s.Dist.TotalWinCollect) ||
+			len(v.Dist.BaseWinCollect) != len(s.Dist.BaseWinCollect) ||
+			len(v.Dist.FreeWinCollect) != len(s.Dist.FreeWinCollect) {
+			return s, errs.NewFatal("merge spin record err : different dist buckets")
+		}
 		for i := range len(v.Dist.TotalWinCollect) {
 			s.Dist.TotalWinCollect[i] += v.Dist.TotalWinCollect[i]
 			s.Dist.BaseWinCollect[i] += v.Dist.BaseWinCollect[i]
@@ -187,6 +209,9 @@ func (s *SpinRecorder) Done() *stats.StatReport {
 			NoWinRounds: s.Dist.TotalWinCollect[0],
 			HitRate:     1.0 - (float64(s.Dist.TotalWinCollect[0]) / float64(s.Basic.Rounds)),
 			Rounds:      s.Basic.Rounds,
+			MaxWin:      s.Basic.MaxWin,
+			MaxWinMult:  float64(s.Basic.MaxWin) / bufloat,
+			Fingerprint: s.Fingerprint,
 		},
 		Mult: &stats.MultReport{
 			TotalWinMult:      float64(s.Basic.TotalWin) / bufloat,
@@ -256,6 +281,10 @@ func (s *SpinRecorder) recordBasic(res *buf.SpinResult) {
 	s.Basic.BaseWinSqSum += bw * bw
 	s.Basic.FreeWinSqSum += fw * fw
 
+	if w > s.Basic.MaxWin {
+		s.Basic.MaxWin = w
+	}
+
 	if res.GameModeCount > 1 {
 		s.Basic.Trigger++
 	}
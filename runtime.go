@@ -29,8 +29,8 @@ type SlotRuntime struct {
 	// build-time 來源（只讀引用）
 	pb *Problab // 方便取 catalog/registry/corefactory 與共用一些 helper
 
-	// data-plane：關鍵主池（每個遊戲一個 pool）
-	pools map[spec.GID]*MachinePool
+	// data-plane：關鍵主池（每個遊戲變體一個 pool；[0] 為預設變體）
+	pools map[spec.GID][]*MachinePool
 	ids   []spec.GID // 固定順序，用於觀測/列舉（來自 cat.IDs()）
 
 	// lifecycle
@@ -60,15 +60,42 @@ func (rt *SlotRuntime) Spin(ctx context.Context, req *dto.SpinRequest) (dto.Spin
 	default:
 	}
 
-	mp, ok := rt.pools[req.GameId]
+	vs, ok := rt.pools[req.GameId]
 	if !ok {
 		return dto.SpinResult{}, errs.NewWarn("game id not found")
 	}
+	mp := rt.pickPool(vs, req)
+	if mp == nil {
+		return dto.SpinResult{}, errs.NewWarn("game variant not found: " + req.Variant)
+	}
 
 	// pool 自己會處理 done / close / rebuild / metrics
 	return mp.Spin(ctx, req)
 }
 
+// pickPool 依序決定變體：請求指定的 variant > 營運商預設變體 > 遊戲預設變體。
+// 請求明確指定但不存在的 variant 回傳 nil（不靜默退回，避免玩到非預期的 RTP 版本）。
+func (rt *SlotRuntime) pickPool(vs []*MachinePool, req *dto.SpinRequest) *MachinePool {
+	if req.Variant != "" {
+		return findPool(vs, req.Variant)
+	}
+	if v, ok := rt.pb.opVariant[req.Operator]; ok && req.Operator != "" {
+		if mp := findPool(vs, v); mp != nil {
+			return mp
+		}
+	}
+	return vs[0]
+}
+
+func findPool(vs []*MachinePool, variant string) *MachinePool {
+	for _, mp := range vs {
+		if mp.gs.Variant == variant {
+			return mp
+		}
+	}
+	return nil
+}
+
 // Close transitions the runtime into a closed state. It is safe to call multiple times.
 func (rt *SlotRuntime) Close() {
 	rt.closeWithReason("closed")
@@ -148,11 +175,12 @@ func (rt *SlotRuntime) buildHealthSnapshot(now int64) RuntimeHealth {
 
 	if runtimeOK {
 		for _, id := range rt.ids {
-			mp := rt.pools[id]
-			closed := mp.Closed()
-			if closed {
-				closedPools = append(closedPools, id)
-				degraded = true
+			for _, mp := range rt.pools[id] {
+				if mp.Closed() {
+					closedPools = append(closedPools, id)
+					degraded = true
+					break
+				}
 			}
 		}
 		if degraded {
@@ -170,9 +198,30 @@ func (rt *SlotRuntime) buildHealthSnapshot(now int64) RuntimeHealth {
 	return snap
 }
 
-func (rt *SlotRuntime) PoolMetrics(gid spec.GID) (MachinePoolMetrics, bool) {
-	if p, ok := rt.pools[gid]; ok {
-		return p.Metrics(), ok
+// PoolMetrics 回傳遊戲全部變體的 pool 觀測快照（預設變體在最前；以 Variant 欄位區分）
+func (rt *SlotRuntime) PoolMetrics(gid spec.GID) ([]MachinePoolMetrics, bool) {
+	vs, ok := rt.pools[gid]
+	if !ok {
+		return nil, false
+	}
+	ms := make([]MachinePoolMetrics, len(vs))
+	for i, mp := range vs {
+		ms[i] = mp.Metrics()
+	}
+	return ms, true
+}
+
+// VariantPoolMetrics 回傳指定變體的 pool 觀測快照（variant 為空字串時為預設變體）
+func (rt *SlotRuntime) VariantPoolMetrics(gid spec.GID, variant string) (MachinePoolMetrics, bool) {
+	vs, ok := rt.pools[gid]
+	if !ok {
+		return MachinePoolMetrics{}, false
+	}
+	if variant == "" {
+		return vs[0].Metrics(), true
+	}
+	if mp := findPool(vs, variant); mp != nil {
+		return mp.Metrics(), true
 	}
 	return MachinePoolMetrics{}, false
 }
@@ -25,6 +25,7 @@ type SpinResult struct {
 	TotalWin      int               // 總贏分
 	GameName      string            // 遊戲名稱
 	GameID        spec.GID          // 遊戲Id
+	Variant       string            // 遊戲變體（空字串為預設版本）
 	Logic         spec.LogicKey     // 對應遊戲邏輯
 	Bet           int               // 當次押注
 	BetUnits      []int             // 押注單位
@@ -34,6 +35,7 @@ type SpinResult struct {
 	GameModeList  []*GameModeResult // 每個遊戲模式的完整結構
 	IsGameEnd     bool              // 遊戲結束旗標
 	State         *SpinState        // 遊戲狀態
+	Fingerprint   string            // 設定檔指紋（由 Machine 建立時填入，Reset 不清除）
 }
 
 // NewSpinResult 建立指定機台的 SpinResult 實體，並預先配置基本容量。
@@ -42,6 +44,7 @@ func NewSpinResult(gs *spec.GameSetting) *SpinResult {
 		TotalWin:      0,
 		GameName:      gs.GameName,
 		GameID:        spec.GID(gs.GameID),
+		Variant:       gs.Variant,
 		Logic:         gs.LogicKey,
 		Bet:           0,
 		BetUnits:      gs.BetUnits,
@@ -112,6 +115,15 @@ type GameModeResult struct {
 	Details    []CalcScreenDetail // 細項紀錄列表
 	HitsFlat   []int16            // 中獎圖稀疏矩陣 (只有中獎的格子idx會紀錄)
 
+	// 可變高度（GenReelByHeight）模式：每個帶盤面的 Act 記錄各軸高度，供前端排版
+	VarLayout bool    // 是否記錄 Layouts
+	Cols      int     // 盤面軸數
+	LayoutTop int     // 直向輪帶起始列（有 TopReel 時為 1）
+	Layouts   []int16 // 各 Act 的各軸高度（每筆 Cols 個，不含 TopReel）
+
+	// 累進乘數：AddAct 時套用到 Act 贏分，跨 Step / Round 保留，Reset 時回到 1
+	Mult int
+
 	TmpAct *TmpAct // 狀態暫存點
 }
 
@@ -128,11 +140,14 @@ type ActResult struct {
 	NowTotalWin int
 	RoundAccWin int
 	StepAccWin  int
-	ActWin      int
+	ActWin      int // 已套用乘數 = BaseWin * Mult
+	BaseWin     int // 套用乘數前的贏分（即細項贏分加總）
+	Mult        int // 套用的累進乘數（無乘數時為 1）
 
 	DetailsStart int
 	DetailsEnd   int
 	ScreenStart  int // -1: 無；否則指到 ScreenBuf 起點
+	LayoutStart  int // -1: 無；否則指到 Layouts 起點
 
 	ExtendResult any // cfg.Turbo模式下存struct指標 正式外發使用情況下轉 map[string]any(Json) 或 []byte
 }
@@ -145,6 +160,7 @@ type CalcScreenDetail struct {
 	Count         int   // 計算數量 直接對應PayTable (Line: 連線長度, Cluster: 集群圖標數量, Collect: 收集數量)
 	Combinations  int   // Way專用 : 組合數量
 	Direction     uint8 // 方向，0: 左到右，1: 右到左 (Way Line用)
+	Multiplier    int   // 套用的合併乘數（0: 未套用；Win 已含乘數；Way 為各 way 乘數總和）
 	HitsFlatStart int   // HitsFlat的起始位置
 	HitsFlatLen   int   // HitsFlat的長度
 }
@@ -185,8 +201,16 @@ func NewGameModeResult(modeId int, ganeModeSetting *spec.GameModeSetting, buffer
 		Details:    make([]CalcScreenDetail, buffer+capModeGrow),
 		HitsFlat:   make([]int16, 0, hitsInitSize+capModeGrow),
 
+		Mult: 1,
+
 		TmpAct: &TmpAct{},
 	}
+	if gms.GenScreenSetting.GenReelType == spec.GenReelByHeight {
+		gmr.VarLayout = true
+		gmr.Cols = gms.ScreenSetting.Columns
+		gmr.LayoutTop = gms.GenScreenSetting.TopRows()
+		gmr.Layouts = make([]int16, 0, gmr.Cols*capModeGrow)
+	}
 	return gmr
 }
 
@@ -204,6 +228,8 @@ func (gmr *GameModeResult) Reset() {
 	gmr.Screens = gmr.Screens[:0]
 	// Details 內容不清空，RecordDetail 時會自動清空並覆蓋
 	gmr.HitsFlat = gmr.HitsFlat[:0]
+	gmr.Layouts = gmr.Layouts[:0]
+	gmr.Mult = 1
 
 	gmr.TmpAct.reset()
 }
@@ -245,6 +271,32 @@ func (gmr *GameModeResult) RecordDetailSegments(win int, symbolID int16, lineID
 	gmr.TmpAct.addwin(win)
 }
 
+// MultiplyDetail 將第 i 筆細項（須為尚未落地的暫存細項）的贏分乘上 mult，記錄乘數並同步暫存贏分。
+func (gmr *GameModeResult) MultiplyDetail(i int, mult int) {
+	gmr.ScaleDetail(i, mult, 1)
+}
+
+// ScaleDetail 將第 i 筆細項（須為尚未落地的暫存細項）的贏分乘上 num/den（向下取整），
+// Multiplier 記為 num 並同步暫存贏分；Way 以各 way 乘數總和 / 組合數套用乘數。
+func (gmr *GameModeResult) ScaleDetail(i int, num int, den int) {
+	if i < gmr.TmpAct.DetailStart || i >= gmr.TmpAct.CurrDetail {
+		panic("scale detail out of current act")
+	}
+	d := &gmr.Details[i]
+	diff := d.Win*num/den - d.Win
+	d.Win += diff
+	d.Multiplier = num
+	gmr.TmpAct.addwin(diff)
+}
+
+// MarkDetailMult 記錄最後一筆暫存細項套用的合併乘數（僅記錄，Win 須已含乘數）。
+func (gmr *GameModeResult) MarkDetailMult(mult int) {
+	if gmr.TmpAct.CurrDetail <= gmr.TmpAct.DetailStart {
+		panic("mark detail mult without current detail")
+	}
+	gmr.Details[gmr.TmpAct.CurrDetail-1].Multiplier = mult
+}
+
 // GetDetails 取得目前累計的盤面細項紀錄。
 func (gmr *GameModeResult) GetDetails() []CalcScreenDetail {
 	return gmr.Details[:gmr.TmpAct.CurrDetail]
@@ -299,6 +351,14 @@ func (gmr *GameModeResult) HitMapTmp() []int16 {
 	return gmr.HitsFlat[gmr.TmpAct.HitsStart:]
 }
 
+// Layout 回傳 Act 的各軸高度；非可變高度模式或該 Act 無盤面時回傳 nil（請勿修改返回值）
+func (gmr *GameModeResult) Layout(a *ActResult) []int16 {
+	if a.LayoutStart < 0 || a.LayoutStart+gmr.Cols > len(gmr.Layouts) {
+		return nil
+	}
+	return gmr.Layouts[a.LayoutStart : a.LayoutStart+gmr.Cols]
+}
+
 // GetTmpWin 取得當下暫存贏分。
 func (gmr *GameModeResult) GetTmpWin() int {
 	return gmr.TmpAct.Win
@@ -314,6 +374,17 @@ func (gmr *GameModeResult) UpdateTmpWin(win int) {
 	t.StepAccWin += diff
 }
 
+// SetMult 設定累進乘數（< 1 視為 1），之後的 AddAct 會以此乘數放大 Act 贏分
+func (gmr *GameModeResult) SetMult(mult int) {
+	gmr.Mult = max(mult, 1)
+}
+
+// AddMult 累加累進乘數（例如每次消除 +1），回傳新的乘數
+func (gmr *GameModeResult) AddMult(delta int) int {
+	gmr.SetMult(gmr.Mult + delta)
+	return gmr.Mult
+}
+
 // Discard 拋棄本次動作得分 資料不落地 環境更美麗
 func (gmr *GameModeResult) Discard() {
 	t := gmr.TmpAct
@@ -331,9 +402,10 @@ func (gmr *GameModeResult) Discard() {
 //   - ext 參數：遊戲的 ExtendResult 介面。AddAct 內部只負責呼叫 Snapshot()，不涉入 isSim 判斷；
 //     是否回傳 nil (Sim 模式) 或深拷貝副本 (Server 模式) 完全由遊戲實作端決定。
 //   - at 參數：指定本次行為是否同時結束 Step / Round（FinishAct 代表僅結束 Act）。
+//   - 累進乘數（Mult > 1）會在此套用：暫存贏分視為 BaseWin，ActWin = BaseWin * Mult，並同步累計。
 //
 // 整體流程：
-//  1. 驗證並 snapshot 盤面（若提供）。
+//  1. 驗證並 snapshot 盤面（若提供）；可變高度模式同時記錄各軸高度；套用累進乘數。
 //  2. 將暫存的分數/細項範圍/盤面位置封裝為 ActResult，並 append 至 ActResults。
 //  3. TotalWin 累加暫存分數，TmpAct 的 Act 索引 +1，並重置暫存邊界(nextAct)。
 //  4. 若 at==FinishStep / FinishRound，立即推進對應層級的索引，方便下一次寫入。
@@ -342,10 +414,19 @@ func (gmr *GameModeResult) AddAct(at FinishType, actType string, screen []int16,
 		panic("screen size not match")
 	}
 	t := gmr.TmpAct
+	baseWin := t.Win
+	if gmr.Mult > 1 && baseWin != 0 {
+		t.addwin(baseWin*gmr.Mult - baseWin)
+	}
 	screenStart := -1
+	layoutStart := -1
 	if len(screen) > 0 {
 		screenStart = t.ScreenStart
 		gmr.snapshot(screen)
+		if gmr.VarLayout {
+			layoutStart = len(gmr.Layouts)
+			gmr.recordLayout(screen)
+		}
 	}
 	// 1. 落地Act
 	a := ActResult{
@@ -356,12 +437,15 @@ func (gmr *GameModeResult) AddAct(at FinishType, actType string, screen []int16,
 		IsRoundEnd:   (at == FinishRound),
 		IsStepEnd:    (at != FinishAct),
 		ActWin:       t.Win,
+		BaseWin:      baseWin,
+		Mult:         max(gmr.Mult, 1),
 		StepAccWin:   t.StepAccWin,
 		RoundAccWin:  t.RoundAccWin,
 		NowTotalWin:  t.Acctotalwin,
 		DetailsStart: t.DetailStart,
 		DetailsEnd:   t.CurrDetail,
 		ScreenStart:  screenStart,
+		LayoutStart:  layoutStart,
 	}
 	if ext != nil {
 		a.ExtendResult = ext.Snapshot()
@@ -489,6 +573,21 @@ func (gmr *GameModeResult) snapshot(screen []int16) {
 	gmr.Screens = append(gmr.Screens, screen...)
 }
 
+// recordLayout 由盤面上的 CellBlocked 推回各軸高度並追加到 Layouts
+func (gmr *GameModeResult) recordLayout(screen []int16) {
+	cols := gmr.Cols
+	rows := gmr.ScreenSize / cols
+	for c := 0; c < cols; c++ {
+		h := int16(0)
+		for r := gmr.LayoutTop; r < rows; r++ {
+			if screen[r*cols+c] != spec.CellBlocked {
+				h++
+			}
+		}
+		gmr.Layouts = append(gmr.Layouts, h)
+	}
+}
+
 func (ta *TmpAct) reset() {
 	ta.CurrRound = 0
 	ta.CurrStep = 0
@@ -414,3 +414,72 @@ func TestSpinResultReset_PreservesCapacity(t *testing.T) {
 		t.Fatalf("expected length 0 after reset, got %d", len(sr.GameModeList))
 	}
 }
+
+func TestGameModeResultLayout(t *testing.T) {
+	gms := &spec.GameModeSetting{
+		ScreenSetting:    spec.ScreenSetting{Columns: 2, Rows: 3},
+		GenScreenSetting: spec.GenScreenSetting{GenReelType: spec.GenReelByHeight},
+	}
+	_ = gms.ScreenSetting.Init()
+	gmr := NewGameModeResult(0, gms, 4, 4)
+	if !gmr.VarLayout || gmr.Cols != 2 {
+		t.Fatalf("expected variable layout recording, got %+v", gmr)
+	}
+	b := spec.CellBlocked
+	gmr.AddAct(FinishAct, "spin", []int16{1, 2, b, 3, b, b}, nil)
+	gmr.AddAct(FinishStep, "noscreen", nil, nil)
+	if got := gmr.Layout(&gmr.ActResults[0]); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("unexpected layout: %v", got)
+	}
+	if got := gmr.Layout(&gmr.ActResults[1]); got != nil {
+		t.Fatalf("act without screen must have no layout, got %v", got)
+	}
+	gmr.Reset()
+	if len(gmr.Layouts) != 0 {
+		t.Fatalf("expected layouts cleared, got %v", gmr.Layouts)
+	}
+}
+
+func TestGameModeResultProgressiveMult(t *testing.T) {
+	gms := testGameModeSetting()
+	if err := gms.ScreenSetting.Init(); err != nil {
+		t.Fatalf("screen init error: %v", err)
+	}
+	gmr := NewGameModeResult(0, gms, 4, 4)
+
+	// 無乘數
+	gmr.RecordDetail(10, 0, 0, 3, 0, 0, []int16{0, 1, 2})
+	gmr.AddAct(FinishStep, "win", nil, nil)
+
+	// 乘數跨 Step / Round 保留
+	gmr.AddMult(1)
+	gmr.RecordDetail(10, 0, 0, 3, 0, 0, []int16{0, 1, 2})
+	gmr.AddAct(FinishRound, "win", nil, nil)
+	if m := gmr.AddMult(1); m != 3 {
+		t.Fatalf("expected mult 3, got %d", m)
+	}
+	gmr.RecordDetail(5, 0, 0, 3, 0, 0, []int16{0, 1, 2})
+	gmr.AddAct(FinishAct, "win", nil, nil)
+	gmr.AddAct(FinishAct, "empty", nil, nil)
+
+	want := []struct{ base, mult, win int }{{10, 1, 10}, {10, 2, 20}, {5, 3, 15}, {0, 3, 0}}
+	for i, w := range want {
+		a := gmr.ActResults[i]
+		if a.BaseWin != w.base || a.Mult != w.mult || a.ActWin != w.win || a.ActWin != a.BaseWin*a.Mult {
+			t.Fatalf("act %d: expected base=%d mult=%d win=%d, got %+v", i, w.base, w.mult, w.win, a)
+		}
+	}
+	if gmr.TotalWin != 45 || gmr.ActResults[2].RoundAccWin != 15 || gmr.ActResults[2].NowTotalWin != 45 {
+		t.Fatalf("expected accumulated wins to include multiplier, got total=%d act=%+v", gmr.TotalWin, gmr.ActResults[2])
+	}
+
+	gmr.SetMult(0)
+	if gmr.Mult != 1 {
+		t.Fatalf("expected mult clamped to 1, got %d", gmr.Mult)
+	}
+	gmr.AddMult(4)
+	gmr.Reset()
+	if gmr.Mult != 1 {
+		t.Fatalf("expected reset mult 1, got %d", gmr.Mult)
+	}
+}
@@ -14,7 +14,10 @@
 
 package calc
 
-import "github.com/zintix-labs/problab/sdk/buf"
+import (
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/spec"
+)
 
 // clusterBuf 優化版：移除了 union/adj 相關的複雜結構，只保留 BFS 必要的緩衝
 type clusterBuf struct {
@@ -34,6 +37,18 @@ type clusterBuf struct {
 	// 配合 wildEpoch 使用，避免每次清零
 	wildMark  []int
 	wildEpoch int
+
+	// wildUsed 記錄 Wild 是否已屬於某個派彩 cluster（wild_exclusive 時使用，每 Spin 清一次）
+	wildUsed []bool
+
+	// 鄰居表（CSR）：格子 i 的鄰居為 nbr[nbrStart[i]:nbrStart[i+1]]，依拓樸預先建好，熱路徑不做邊界判斷
+	nbrStart []int32
+	nbr      []int32
+
+	// 規則（hit_setting.cluster）
+	minSize       int
+	wildExclusive bool
+	cluster       *spec.ClusterSetting
 }
 
 // resetSizes 只調整容量，不清內容
@@ -54,6 +69,12 @@ func (b *clusterBuf) resetSizes(rows int, cols int) {
 		b.wildMark = b.wildMark[:needN]
 	}
 
+	if cap(b.wildUsed) < needN {
+		b.wildUsed = make([]bool, needN)
+	} else {
+		b.wildUsed = b.wildUsed[:needN]
+	}
+
 	if cap(b.q) < needN {
 		b.q = make([]int, 0, needN)
 	}
@@ -63,31 +84,80 @@ func (b *clusterBuf) resetSizes(rows int, cols int) {
 	}
 }
 
-// CalcByClusterOptimized 直接在 Grid 上進行 BFS，移除了 buildUnions 階段。
+// 六角格鄰居偏移 (dc, dr)，依軸或列的奇偶選用
+var (
+	hexOddColOffsets  = [2][6][2]int{{{1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {0, 1}}, {{1, 1}, {1, 0}, {0, -1}, {-1, 0}, {-1, 1}, {0, 1}}}
+	hexEvenColOffsets = [2][6][2]int{{{1, 1}, {1, 0}, {0, -1}, {-1, 0}, {-1, 1}, {0, 1}}, {{1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {0, 1}}}
+	hexOddRowOffsets  = [2][6][2]int{{{1, 0}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}, {0, 1}}, {{1, 0}, {1, -1}, {0, -1}, {-1, 0}, {0, 1}, {1, 1}}}
+	hexEvenRowOffsets = [2][6][2]int{{{1, 0}, {1, -1}, {0, -1}, {-1, 0}, {0, 1}, {1, 1}}, {{1, 0}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}, {0, 1}}}
+	squareOffsets4    = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	squareOffsets8    = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}}
+)
+
+// setRules 依 ClusterSetting 建立鄰居表與規則快取（初始化時呼叫一次）
+func (b *clusterBuf) setRules(cs *spec.ClusterSetting) {
+	b.minSize = cs.MinSize
+	b.wildExclusive = cs.WildExclusive
+	b.cluster = cs
+
+	rows, cols := b.rows, b.cols
+	b.nbrStart = make([]int32, 0, b.n+1)
+	b.nbr = b.nbr[:0]
+	for i := 0; i < b.n; i++ {
+		r, c := i/cols, i%cols
+		b.nbrStart = append(b.nbrStart, int32(len(b.nbr)))
+		var offs [][2]int
+		switch cs.Topology {
+		case spec.ClusterTopologyHexOddCol:
+			offs = hexOddColOffsets[c&1][:]
+		case spec.ClusterTopologyHexEvenCol:
+			offs = hexEvenColOffsets[c&1][:]
+		case spec.ClusterTopologyHexOddRow:
+			offs = hexOddRowOffsets[r&1][:]
+		case spec.ClusterTopologyHexEvenRow:
+			offs = hexEvenRowOffsets[r&1][:]
+		default:
+			offs = squareOffsets4
+			if cs.Adjacency == 8 {
+				offs = squareOffsets8
+			}
+		}
+		for _, o := range offs {
+			nc, nr := c+o[0], r+o[1]
+			if nc < 0 || nc >= cols || nr < 0 || nr >= rows {
+				continue
+			}
+			b.nbr = append(b.nbr, int32(nr*cols+nc))
+		}
+	}
+	b.nbrStart = append(b.nbrStart, int32(len(b.nbr)))
+}
+
+// CalcByCluster 直接在 Grid 上以預建鄰居表進行 BFS，移除了 buildUnions 階段。
 // 效能優勢：減少了 50% 以上的記憶體分配與訪問，Cache Miss 大幅降低。
+//
+// 規則由 hit_setting.cluster 設定：拓樸 / 鄰接數、最小集群大小、wild 是否可共用、分段派彩。
+// 集群依起點格子索引由小到大結算；wild_exclusive 時 wild 歸屬最先結算且派彩的集群。
+// 細項的 Count 為集群實際大小（含 wild），派彩欄位以 ClusterSetting.PayColumn 換算。
 func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *ScreenCalculator) {
-	rows, cols := sc.Rows, sc.Cols
 	b := sc.clusterBuf
 	if b == nil {
-		sc.clusterBuf = &clusterBuf{}
+		sc.initClusterBuf()
 		b = sc.clusterBuf
 	}
-	// 初始化 Buffer 大小
-	b.resetSizes(rows, cols)
 
 	// 重置 Global Visited (普通符號每個 Spin 清一次)
 	// Go 編譯器對 range loop clear 有優化，速度極快 (memclr)
-	for i := range b.visited {
-		b.visited[i] = false
+	clear(b.visited)
+	if b.wildExclusive {
+		clear(b.wildUsed)
 	}
 	// wildMark 不用清，依靠 epoch 區分
 	// 如果 epoch 溢位才清一次 (極少發生)
 	b.wildEpoch++
 	if b.wildEpoch < 0 { // handle overflow
 		b.wildEpoch = 1
-		for i := range b.wildMark {
-			b.wildMark[i] = 0
-		}
+		clear(b.wildMark)
 	}
 
 	n := b.n
@@ -96,20 +166,16 @@ func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scr
 	payFlat := sc.PayTableFlat
 	payIdx := sc.PayTableIndex
 	minPay := sc.minPayCount
-
-	isWild := func(s int16) bool { return ((wildMask >> uint(s)) & 1) != 0 }
-	isPaid := func(s int16) bool { return ((paidMask >> uint(s)) & 1) != 0 }
+	nbr, nbrStart := b.nbr, b.nbrStart
+	multOn := sc.multOn()
 
 	// 遍歷每一個格子作為潛在的 Cluster 起點
 	for i := 0; i < n; i++ {
 		sym := screen[i]
 
 		// 1. 如果是 Wild，跳過 (Wild 不能作為 Cluster 的 "主體" 起始，它只能依附)
-		if isWild(sym) {
-			continue
-		}
-		// 2. 如果該符號不派彩，跳過
-		if !isPaid(sym) {
+		// 2. 如果該符號不派彩（含遮罩格），跳過
+		if (wildMask>>uint(sym))&1 != 0 || (paidMask>>uint(sym))&1 == 0 {
 			continue
 		}
 		// 3. 如果該格已經屬於某個已計算過的 Cluster，跳過
@@ -132,7 +198,6 @@ func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scr
 		b.visited[i] = true
 		b.hits = append(b.hits, int16(i))
 
-		clusterSize := 0 // 實際長度 (普通 + Wild)
 		head := 0
 
 		// BFS Loop
@@ -140,57 +205,41 @@ func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scr
 			// Pop
 			curr := b.q[head]
 			head++
-			clusterSize++
-
-			r := curr / cols
-			c := curr % cols
 
-			// 定義鄰居檢查邏輯
-			checkNeighbor := func(next int) {
+			for _, nx := range nbr[nbrStart[curr]:nbrStart[curr+1]] {
+				next := int(nx)
 				ns := screen[next]
 
-				// 情況 A: 鄰居是 Wild
-				if isWild(ns) {
+				// 情況 A: 鄰居是 Wild（wild_exclusive 時略過已屬於派彩集群者）
+				if (wildMask>>uint(ns))&1 != 0 {
 					// 檢查這個 Wild 在「當前 Cluster」是否已經被訪問過
-					if b.wildMark[next] != currentWildEpoch {
+					if b.wildMark[next] != currentWildEpoch && !b.wildUsed[next] {
 						b.wildMark[next] = currentWildEpoch // 標記為本次已訪問
 						b.q = append(b.q, next)
 						b.hits = append(b.hits, int16(next))
 					}
-					return
+					continue
 				}
 
-				// 情況 B: 鄰居是同種符號
-				if ns == sym {
-					// 檢查這個符號在「全盤」是否已被訪問過
-					if !b.visited[next] {
-						b.visited[next] = true // 標記為全局已訪問，未來不會再作為起點
-						b.q = append(b.q, next)
-						b.hits = append(b.hits, int16(next))
-					}
+				// 情況 B: 鄰居是同種符號，且在「全盤」尚未被訪問過
+				if ns == sym && !b.visited[next] {
+					b.visited[next] = true // 標記為全局已訪問，未來不會再作為起點
+					b.q = append(b.q, next)
+					b.hits = append(b.hits, int16(next))
 				}
 			}
-
-			// 展開四個方向
-			if c > 0 {
-				checkNeighbor(curr - 1)
-			}
-			if c+1 < cols {
-				checkNeighbor(curr + 1)
-			}
-			if r > 0 {
-				checkNeighbor(curr - cols)
-			}
-			if r+1 < rows {
-				checkNeighbor(curr + cols)
-			}
 		}
 
 		// --- 算分 ---
+		clusterSize := len(b.hits) // 實際長度 (普通 + Wild)
 
-		// 1. 檢查最小連線數
+		// 1. 檢查最小集群大小與最低派彩顆數
 		symInt := int(sym)
-		if clusterSize < minPay[symInt] {
+		if clusterSize < b.minSize || (len(b.cluster.PayTiers) == 0 && clusterSize < minPay[symInt]) {
+			continue
+		}
+		col := b.cluster.PayColumn(clusterSize)
+		if col < 0 {
 			continue
 		}
 
@@ -204,7 +253,7 @@ func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scr
 		}
 
 		// 夾斷 (Clamp) 到最大賠率長度
-		idx := base + (clusterSize - 1)
+		idx := base + col
 		if idx >= end {
 			idx = end - 1
 		}
@@ -212,8 +261,23 @@ func CalcByCluster(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scr
 		pay := payFlat[idx]
 		if pay > 0 {
 			win := pay * betMult
+			mult := 1
+			if multOn {
+				mult = sc.hitMult(screen, b.hits)
+				win *= mult
+			}
 			// 3. 寫入結果
 			gmr.RecordDetail(win, sym, 0, clusterSize, 0, 0, b.hits)
+			if mult != 1 {
+				gmr.MarkDetailMult(mult)
+			}
+			if b.wildExclusive {
+				for _, h := range b.hits {
+					if (wildMask>>uint(screen[h]))&1 != 0 {
+						b.wildUsed[h] = true
+					}
+				}
+			}
 		}
 	}
 }
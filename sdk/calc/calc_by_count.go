@@ -16,13 +16,16 @@ package calc
 
 import "github.com/zintix-labs/problab/sdk/buf"
 
-// CalcByCount 依 Collect 下注規則計算盤面分數，支援 wild 代任計數。
+// CalcByCount 依 Collect 下注規則計算盤面分數，支援 wild 代任計數；分裂格（SetCellCount）以 n 顆計入。
 func CalcByCount(betMult int, screen []int16, gmr *buf.GameModeResult, sc *ScreenCalculator) {
 	// 整理圖標位置以及顆數
 	calcSymbolCounts(screen, sc)
+	if sc.cellCountOn {
+		sc.countExtras(screen)
+	}
 
 	// 判斷得分
-	calcByCollect(betMult, sc, gmr)
+	calcByCollect(betMult, screen, sc, gmr)
 
 }
 
@@ -61,7 +64,7 @@ func calcSymbolCounts(screen []int16, sc *ScreenCalculator) {
 	sc.wildCounts = wCnt
 }
 
-func calcByCollect(betMult int, sc *ScreenCalculator, gmr *buf.GameModeResult) *buf.GameModeResult {
+func calcByCollect(betMult int, screen []int16, sc *ScreenCalculator, gmr *buf.GameModeResult) *buf.GameModeResult {
 	cr := gmr
 	cols, rows := sc.Cols, sc.Rows
 	rc := cols * rows
@@ -74,6 +77,7 @@ func calcByCollect(betMult int, sc *ScreenCalculator, gmr *buf.GameModeResult) *
 	whits := sc.wildHitMapFlat
 	isPaid := sc.paidMask
 	isWild := sc.wildMask
+	multOn := sc.multOn()
 
 	// 縮 cap，避免 append/擴容波及底層 array（雖然目前只讀）
 	whitsView := whits[:wCnt:wCnt]
@@ -89,12 +93,24 @@ func calcByCollect(betMult int, sc *ScreenCalculator, gmr *buf.GameModeResult) *
 
 		// A) Wild 自身計分（少見，但若 paytable 有定義就支援）
 		if (isWild & bit) != 0 {
-			p := payFlat[base+(wCnt-1)] // off-by-one 修正：index = base + (count-1)
+			wTotal := wCnt
+			if sc.cellCountOn {
+				wTotal = min(wCnt+sc.wildExtra, sc.payLen)
+			}
+			p := payFlat[base+(wTotal-1)] // off-by-one 修正：index = base + (count-1)
 			if p > 0 {
 				p *= betMult
 				// BCE hint：協助編譯器消去 seg 的邊界檢查
 				_ = whits[wCnt-1]
-				cr.RecordDetail(p, int16(s), 0, wCnt, 0, 0, whitsView)
+				mult := 1
+				if multOn {
+					mult = sc.hitMult(screen, whitsView)
+					p *= mult
+				}
+				cr.RecordDetail(p, int16(s), 0, wTotal, 0, 0, whitsView)
+				if mult != 1 {
+					cr.MarkDetailMult(mult)
+				}
 			}
 
 			continue // wild 自身若可計分，視為獨立一筆；不影響 normal 的加成規則
@@ -106,6 +122,9 @@ func calcByCollect(betMult int, sc *ScreenCalculator, gmr *buf.GameModeResult) *
 		if total <= 0 {
 			continue
 		}
+		if sc.cellCountOn {
+			total = min(total+sc.extraCounts[s]+sc.wildExtra, sc.payLen)
+		}
 
 		p := payFlat[base+(total-1)] // off-by-one 修正
 		p *= betMult
@@ -125,7 +144,15 @@ func calcByCollect(betMult int, sc *ScreenCalculator, gmr *buf.GameModeResult) *
 		seg1 := hits[start : start+c] // 自身命中（可能為空）
 		seg2 := whitsView             // wild 全段（可能為空）
 
+		mult := 1
+		if multOn {
+			mult = sc.hitMult(screen, seg1, seg2)
+			p *= mult
+		}
 		cr.RecordDetailSegments(p, int16(s), 0, total, 0, 0, seg1, seg2)
+		if mult != 1 {
+			cr.MarkDetailMult(mult)
+		}
 	}
 
 	return gmr
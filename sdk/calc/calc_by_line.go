@@ -56,6 +56,7 @@ func calcOneDirection(betMult int, screen []int16, sc *ScreenCalculator, directi
 	paidMask := sc.paidMask
 	payFlat := sc.PayTableFlat
 	payIdx := sc.PayTableIndex
+	multOn := sc.multOn()
 
 	// 逐線
 	for lineIdx := 0; lineIdx < sc.LineCount; lineIdx++ {
@@ -128,19 +129,38 @@ func calcOneDirection(betMult int, screen []int16, sc *ScreenCalculator, directi
 			normWin = betMult * payFlat[payIdx[normSym]+(normRun-1)]
 		}
 
+		// 有乘數時兩種串長各自乘上命中格乘數後再比較
+		wildMult, normMult := 1, 1
+		if multOn {
+			if wildWin > 0 {
+				wildMult = sc.hitMult(screen, line[:wildRun])
+				wildWin *= wildMult
+			}
+			if normWin > 0 {
+				normMult = sc.hitMult(screen, line[:normRun])
+				normWin *= normMult
+			}
+		}
+
+		mult := 1
 		if wildWin > normWin {
 			sym = firstSym
 			hitLen = wildRun
 			win = wildWin
+			mult = wildMult
 		} else {
 			sym = normSym
 			hitLen = normRun
 			win = normWin
+			mult = normMult
 		}
 
 		// 計分
 		if win > 0 {
 			cr.RecordDetail(win, sym, lineIdx, hitLen, 0, direction, line[:hitLen])
+			if mult != 1 {
+				cr.MarkDetailMult(mult)
+			}
 		}
 	}
 }
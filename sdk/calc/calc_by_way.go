@@ -17,6 +17,11 @@ package calc
 import "github.com/zintix-labs/problab/sdk/buf"
 
 // CalcByWay 依 Ways 下注規則計算盤面分數，支援 LTR/RTL 雙向。
+//
+// 遮罩格（spec.CellBlocked）不計入任何軸，因此不規則盤面與可變高度（GenReelByHeight）
+// 的組合數即為各軸實際可見格內的命中數乘積；緩衝依 Rows（最大高度）配置一次即可。
+// 分裂格（SetCellCount）在所屬軸以 n 顆計入組合數；贏分依連線長度查表、不乘組合數，故分裂不改變派彩。
+// 有乘數時每條 way 各自合併所經格子的乘數（見 multiplier.go），不會把同軸多顆乘數疊到每條 way 上。
 func CalcByWay(betMult int, screen []int16, gmr *buf.GameModeResult, sc *ScreenCalculator) {
 	// 計算核心表
 	calcSymbolInCols(screen, sc)
@@ -89,7 +94,9 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 	keepMask := wildMask | paidMask
 	isWildPaid := (wildMask & paidMask) != 0 // 規則1：wild 若要帶頭必須有自己的算分
 	wc := sc.wildInCols                      // [cols]：每欄 wild 聚合總數（已預算）
+	aw, wcw := sc.wayCounts(screen)          // 組合數用計數（無分裂格時即 arr / wc）
 	symN := len(sc.symbolCounts)
+	multOn := sc.multOn()
 
 	// 首欄候選去重（≤64 符號 → bitset）
 	var seen uint64 = 0
@@ -118,13 +125,13 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 
 			// 起手即計入第 0 欄：run=1，comb=wc[0]
 			bestLen = 1
-			bestComb = wc[0]
-			selfCnt = bestComb
+			bestComb = wcw[0]
+			selfCnt = wc[0]
 			for c := 1; c < cols; c++ {
 				if wc[c] == 0 {
 					break
 				}
-				bestComb *= wc[c]
+				bestComb *= wcw[c]
 				bestLen++
 
 				wildCnt += wc[c] // 後續只計入wild當中 都會算到
@@ -140,6 +147,9 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 				seg1 := hits[start : start+selfCnt]
 				seg2 := wHits[wc[0] : wc[0]+wildCnt]
 				gmr.RecordDetailSegments(bestWin, int16(s), 0, bestLen, bestComb, 0, seg1, seg2)
+				if multOn {
+					sc.recordWayMult(screen, gmr, bestComb, seg1, seg2)
+				}
 			}
 			continue
 		}
@@ -150,8 +160,8 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 
 			// 起手：第 0 欄必定有目標（能進來代表首欄看到此符號），bestLen=1，bestComb=arr[s*cols+0]
 			bestLen = 1
-			bestComb = arr[base]
-			selfCnt = bestComb
+			bestComb = aw[base]
+			selfCnt = arr[base]
 
 			for c := 1; c < cols; c++ { // 從第 2 欄開始可借 wild
 				sum := arr[base+c] + wc[c]
@@ -161,7 +171,7 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 				selfCnt += arr[base+c] // 全計入
 				wildCnt += wc[c]       // 全計入
 				bestLen++
-				bestComb *= sum
+				bestComb *= aw[base+c] + wcw[c]
 			}
 
 			pbase := payIdx[s]
@@ -172,6 +182,9 @@ func calcByWayLTR(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 				seg1 := hits[start : start+selfCnt]
 				seg2 := wHits[wc[0] : wc[0]+wildCnt]
 				gmr.RecordDetailSegments(bestWin, int16(s), 0, bestLen, bestComb, 0, seg1, seg2)
+				if multOn {
+					sc.recordWayMult(screen, gmr, bestComb, seg1, seg2)
+				}
 			}
 		}
 	}
@@ -192,7 +205,9 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 	keepMask := wildMask | paidMask
 	isWildPaid := (wildMask & paidMask) != 0 // 規則1：wild 若要帶頭必須有自己的算分
 	wc := sc.wildInCols                      // [cols]：每欄 wild 聚合總數
+	aw, wcw := sc.wayCounts(screen)          // 組合數用計數（無分裂格時即 arr / wc）
 	symN := len(sc.symbolCounts)
+	multOn := sc.multOn()
 
 	// wild 總顆數（不建 off，僅作總量，供 RTL 右端切片回推）
 	wildTotal := 0
@@ -224,14 +239,14 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 		if ((wildMask & bit) != 0) && isWildPaid {
 			// 起手即包含最後一欄
 			bestLen = 1
-			bestComb = wc[cols-1]
+			bestComb = wcw[cols-1]
 			wildCntR = wc[cols-1] // wild-only：累計含最後一欄
 
 			for c := cols - 2; c >= 0; c-- {
 				if wc[c] == 0 {
 					break
 				}
-				bestComb *= wc[c]
+				bestComb *= wcw[c]
 				bestLen++
 				wildCntR += wc[c]
 			}
@@ -243,6 +258,9 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 				// 取最右 bestLen 欄的 wild 命中（含最後一欄）
 				seg := wHits[wildTotal-wildCntR : wildTotal]
 				gmr.RecordDetail(bestWin, int16(s), 0, bestLen, bestComb, 1, seg)
+				if multOn {
+					sc.recordWayMult(screen, gmr, bestComb, seg, nil)
+				}
 			}
 			continue
 		}
@@ -253,8 +271,8 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 
 			// 起手：最後一欄必定有目標（能進來代表該欄看到此符號）
 			bestLen = 1
-			bestComb = arr[base+(cols-1)]
-			selfCntR = bestComb
+			bestComb = aw[base+(cols-1)]
+			selfCntR = arr[base+(cols-1)]
 			wildCntR = 0 // 最後一欄不可借 wild
 
 			for c := cols - 2; c >= 0; c-- { // 從倒數第 2 欄開始可借 wild
@@ -263,7 +281,7 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 					break
 				}
 				bestLen++
-				bestComb *= sum
+				bestComb *= aw[base+c] + wcw[c]
 				selfCntR += arr[base+c]
 				wildCntR += wc[c]
 			}
@@ -281,6 +299,9 @@ func calcByWayRTL(betMult int, screen []int16, gmr *buf.GameModeResult, sc *Scre
 				segWild := wHits[wildExLast-wildCntR : wildExLast]
 
 				gmr.RecordDetailSegments(bestWin, int16(s), 0, bestLen, bestComb, 1, segSelf, segWild)
+				if multOn {
+					sc.recordWayMult(screen, gmr, bestComb, segSelf, segWild)
+				}
 			}
 		}
 	}
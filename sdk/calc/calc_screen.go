@@ -15,8 +15,7 @@
 package calc
 
 import (
-	"log"
-
+	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/buf"
 	"github.com/zintix-labs/problab/spec"
 )
@@ -83,24 +82,84 @@ type ScreenCalculator struct {
 	// ----------  Cluster 熱路徑暫存資料 ----------
 	clusterBuf *clusterBuf
 
+	// ----------  自訂算分器（RegisterBetType）暫存資料 ----------
+	Custom any
+
+	// ----------  乘數（見 multiplier.go） ----------
+	CellMults   []int      // 格子乘數（<= 1 表示無），以 SetCellMult 設定
+	cellMultOn  bool       // 是否有任何格子乘數
+	symMults    []int      // 符號乘數
+	symMultMask SymbolMask // 具有乘數的符號遮罩
+	multAdd     bool       // 乘數相加（否則相乘）
+	customMults bool       // 自訂下注型態：算分後以命中格套用乘數
+	wayN        []int      // Way 每軸命中格數
+	wayM        []int      // Way 每軸格子乘數和（相乘用）
+	wayA        []int      // Way 每軸格子乘數和（相加用）
+	wayZ        []int      // Way 每軸無乘數格數（相加用）
+
+	// ----------  分裂計數（見 split.go） ----------
+	CellCounts    []int // 格子計數（<= 1 表示一般），以 SetCellCount 設定
+	cellCountOn   bool  // 是否有任何分裂格
+	payLen        int   // 每個符號的派彩表長度
+	symbolInColsW []int // Way：加權後的每符號每軸計數
+	wildInColsW   []int // Way：加權後的每軸 wild 計數
+	extraCounts   []int // Count：每符號額外顆數
+	wildExtra     int   // Count：wild 額外顆數
+
 	// calcScreen預封装
 	calcScreenFn CalcScreenFn
 }
 
 // NewScreenCalculator 建立算分器。
+//
+// 設定無法初始化時 panic；需要取得錯誤時請改用 NewScreenCalculatorE。
 func NewScreenCalculator(gameModeSetting *spec.GameModeSetting) *ScreenCalculator {
+	sc, err := NewScreenCalculatorE(gameModeSetting)
+	if err != nil {
+		panic(err)
+	}
+	return sc
+}
+
+// NewScreenCalculatorE 與 NewScreenCalculator 相同，但以錯誤回報初始化失敗：
+// bet_type 沒有對應的算分函式（例如只以 spec.RegisterBetType 註冊、未經 calc.RegisterBetType）。
+func NewScreenCalculatorE(gameModeSetting *spec.GameModeSetting) (*ScreenCalculator, error) {
 	sc := &ScreenCalculator{
 		ScreenSetting: &gameModeSetting.ScreenSetting,
 		SymbolSetting: &gameModeSetting.SymbolSetting,
 		HitSetting:    &gameModeSetting.HitSetting,
 	}
-	sc.init()
-	return sc
+	if err := sc.init(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// WildMask 回傳 Wild 符號遮罩（供自訂算分器使用）
+func (sc *ScreenCalculator) WildMask() SymbolMask {
+	return sc.wildMask
+}
+
+// PaidMask 回傳具有派彩的符號遮罩（供自訂算分器使用）
+func (sc *ScreenCalculator) PaidMask() SymbolMask {
+	return sc.paidMask
+}
+
+// MinPayCount 回傳符號 s 的最低中獎顆數（無派彩時為 0）
+func (sc *ScreenCalculator) MinPayCount(s int) int {
+	return sc.minPayCount[s]
 }
 
 // CalcScreen 以預先決定的熱路徑計算盤面並回傳結果。
+// 內建算分於評估時套用乘數；自訂下注型態有符號乘數或格子乘數時，會對本次新增的細項套用合併乘數。
 func (sc *ScreenCalculator) CalcScreen(betMult int, screen []int16, gmr *buf.GameModeResult) {
+	if !sc.customMults || !sc.multOn() {
+		sc.calcScreenFn(betMult, screen, gmr, sc)
+		return
+	}
+	d0 := gmr.TmpAct.CurrDetail
 	sc.calcScreenFn(betMult, screen, gmr, sc)
+	sc.applyMults(screen, gmr, d0)
 }
 
 // ============================================================
@@ -108,12 +167,14 @@ func (sc *ScreenCalculator) CalcScreen(betMult int, screen []int16, gmr *buf.Gam
 // ============================================================
 
 // init 初始化算分器的快取資料與熱路徑依賴
-func (sc *ScreenCalculator) init() {
-	sc.initSettings()   // 錯誤防禦
-	sc.initScreen()     // Screen 設定
-	sc.initSymbols()    // Symbol設定 wildMask 與 payMask
-	sc.initHitSetting() // 中獎設定
-	sc.initCalcFn()     // 預封装盤面算分函數
+func (sc *ScreenCalculator) init() error {
+	sc.initSettings()      // 錯誤防禦
+	sc.initScreen()        // Screen 設定
+	sc.initSymbols()       // Symbol設定 wildMask 與 payMask
+	sc.initHitSetting()    // 中獎設定
+	sc.initMults()         // 符號 / 格子乘數
+	sc.initSplits()        // 分裂計數
+	return sc.initCalcFn() // 預封装盤面算分函數
 }
 
 func (sc *ScreenCalculator) initSettings() {
@@ -129,12 +190,10 @@ func (sc *ScreenCalculator) initScreen() {
 }
 
 func (sc *ScreenCalculator) initSymbols() {
-	// wildMask : Wild符號遮罩
+	// wildMask : Wild符號遮罩（依符號角色，多角色符號如 scatter+wild 同樣視為 wild）
 	// payMask : 具有派彩的符號遮罩
-	for i, st := range sc.SymbolSetting.SymbolTypes {
-		if st == spec.SymbolTypeWild {
-			sc.wildMask |= (1 << uint(i))
-		}
+	sc.wildMask = sc.SymbolSetting.RoleMask(spec.SymbolRoleWild)
+	for i := range sc.SymbolSetting.SymbolCount {
 		arr := sc.SymbolSetting.PayTable[i]
 		for _, v := range arr {
 			if v > 0 {
@@ -178,6 +237,9 @@ func (sc *ScreenCalculator) initHitSetting() {
 		sc.initClusterBuf()
 		// sc.initClusterBufOld() // Cluster 暫存緩衝
 	}
+	if ev, ok := evaluator(betType); ok && ev.Init != nil {
+		ev.Init(sc)
+	}
 
 }
 
@@ -232,14 +294,19 @@ func (sc *ScreenCalculator) initClusterBuf() {
 	if sc.clusterBuf == nil {
 		sc.clusterBuf = &clusterBuf{}
 	}
-	// 將目前的 Rows/Cols 與遮罩快取到 buffer
+	// 將目前的 Rows/Cols 與遮罩快取到 buffer，並依 hit_setting.cluster 建立鄰居表
 	sc.clusterBuf.resetSizes(sc.Rows, sc.Cols)
+	sc.clusterBuf.setRules(&sc.HitSetting.Cluster)
 }
 
-func (sc *ScreenCalculator) initCalcFn() {
+func (sc *ScreenCalculator) initCalcFn() error {
 	if fn, ok := fromBetTypeGetCalcScreenFn[sc.HitSetting.BetType]; ok {
 		sc.calcScreenFn = fn
+	} else if ev, ok := evaluator(sc.HitSetting.BetType); ok {
+		sc.calcScreenFn = ev.Calc
+		sc.customMults = true
 	} else {
-		log.Fatalf("miss match bettype %v to calc screen function", sc.HitSetting.BetType)
+		return errs.Fatalf("bet type %q has no calc screen function (register it with calc.RegisterBetType)", sc.HitSetting.BetTypeStr)
 	}
+	return nil
 }
@@ -0,0 +1,179 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import "github.com/zintix-labs/problab/sdk/buf"
+
+// 乘數來源：
+//   - 符號乘數：symbols[].multiplier（例如 2 倍 wild），盤面上出現即生效
+//   - 格子乘數：由遊戲邏輯以 SetCellMult 設定（例如倍數格），ClearCellMults 清除
+//
+// 同一格同時有符號乘數與格子乘數時一併計入；多個乘數依 hit_setting.multiplier_mode 相乘或相加。
+// 內建算分在評估當下套用乘數，Win 已含乘數並記錄在 CalcScreenDetail.Multiplier：
+//   - Line：wild 起手與 normal 起手兩種串長各自乘上命中格乘數後再取較大者
+//   - Way：每條 way 的乘數由該 way 經過的格子合併（每軸一格），細項贏分依「各 way 乘數總和 / 組合數」縮放
+//     （Way 贏分不含組合數，無法整除時向下取整），Multiplier 記錄各 way 乘數總和
+//   - Cluster / Count：命中格乘數合併後乘上贏分
+//
+// 自訂下注型態（RegisterBetType）則於算分後對新增細項以命中格合併乘數。未設定任何乘數時完全不進入此流程。
+
+// SetCellMult 設定盤面 idx 格子的乘數（<= 1 視為無乘數），對之後的 CalcScreen 生效
+func (sc *ScreenCalculator) SetCellMult(idx int, mult int) {
+	sc.CellMults[idx] = mult
+	if mult > 1 {
+		sc.cellMultOn = true
+	}
+}
+
+// ClearCellMults 清除所有格子乘數
+func (sc *ScreenCalculator) ClearCellMults() {
+	if sc.cellMultOn {
+		clear(sc.CellMults)
+		sc.cellMultOn = false
+	}
+}
+
+// initMults 建立符號乘數遮罩與格子乘數緩衝
+func (sc *ScreenCalculator) initMults() {
+	sc.CellMults = make([]int, sc.ScreenSize)
+	sc.multAdd = sc.HitSetting.MultAdd
+	sc.symMults = sc.SymbolSetting.SymbolMults
+	for s, m := range sc.symMults {
+		if m > 1 && s < 64 {
+			sc.symMultMask |= 1 << uint(s)
+		}
+	}
+	// Way 每軸累計緩衝
+	sc.wayN = make([]int, sc.Cols)
+	sc.wayM = make([]int, sc.Cols)
+	sc.wayA = make([]int, sc.Cols)
+	sc.wayZ = make([]int, sc.Cols)
+}
+
+// multOn 回傳盤面上是否可能有乘數
+func (sc *ScreenCalculator) multOn() bool {
+	return sc.symMultMask != 0 || sc.cellMultOn
+}
+
+// applyMults 對本次 CalcScreen 新增的細項（d0 之後）套用乘數（僅自訂下注型態使用）
+func (sc *ScreenCalculator) applyMults(screen []int16, gmr *buf.GameModeResult, d0 int) {
+	details := gmr.GetDetails()
+	for i := d0; i < len(details); i++ {
+		d := &details[i]
+		if d.Win == 0 {
+			continue
+		}
+		hits := gmr.HitsFlat[d.HitsFlatStart : d.HitsFlatStart+d.HitsFlatLen]
+		if m := sc.hitMult(screen, hits); m != 1 {
+			gmr.MultiplyDetail(i, m)
+		}
+	}
+}
+
+// cellMult 回傳單一格子的乘數：mul 為相乘結果（無乘數為 1），add 為相加結果（無乘數為 0）
+func (sc *ScreenCalculator) cellMult(s int16, idx int16) (mul int, add int) {
+	mul = 1
+	if uint(s) < 64 && sc.symMultMask&(1<<uint(s)) != 0 {
+		m := sc.symMults[s]
+		mul *= m
+		add += m
+	}
+	if sc.cellMultOn {
+		if m := sc.CellMults[idx]; m > 1 {
+			mul *= m
+			add += m
+		}
+	}
+	return mul, add
+}
+
+// hitMult 合併命中格子上的乘數；沒有任何乘數時回傳 1
+func (sc *ScreenCalculator) hitMult(screen []int16, segs ...[]int16) int {
+	add, mul := 0, 1
+	for _, hits := range segs {
+		for _, idx := range hits {
+			m, a := sc.cellMult(screen[idx], idx)
+			mul *= m
+			add += a
+		}
+	}
+	if sc.multAdd && add > 0 {
+		return add
+	}
+	return mul
+}
+
+// wayMult 回傳一筆 Way 細項各 way 乘數的總和（沒有任何乘數時等於組合數）
+//
+// 命中格即各 way 可經過的格子（分裂格以 n 顆計）。相乘時總和為各軸格子乘數和的乘積；
+// 相加時每條 way 為所經格子乘數之和（完全沒有乘數的 way 為 1），
+// 總和為 Σ_c（第 c 軸乘數和 × 其他軸格數乘積）加上無乘數 way 的數量。
+func (sc *ScreenCalculator) wayMult(screen []int16, seg1 []int16, seg2 []int16) int {
+	n, m, a, z := sc.wayN, sc.wayM, sc.wayA, sc.wayZ
+	clear(n)
+	clear(m)
+	clear(a)
+	clear(z)
+	for _, seg := range [2][]int16{seg1, seg2} {
+		for _, idx := range seg {
+			w := 1
+			if sc.cellCountOn && sc.CellCounts[idx] > 1 {
+				w = sc.CellCounts[idx]
+			}
+			cm, ca := sc.cellMult(screen[idx], idx)
+			c := int(idx) % sc.Cols
+			n[c] += w
+			m[c] += w * cm
+			a[c] += w * ca
+			if ca == 0 {
+				z[c] += w
+			}
+		}
+	}
+	if !sc.multAdd {
+		total := 1
+		for c := range m {
+			if n[c] > 0 {
+				total *= m[c]
+			}
+		}
+		return total
+	}
+	total, plain := 0, 1
+	for c := range a {
+		if n[c] == 0 {
+			continue
+		}
+		plain *= z[c]
+		if a[c] == 0 {
+			continue
+		}
+		part := a[c]
+		for d := range n {
+			if d != c && n[d] > 0 {
+				part *= n[d]
+			}
+		}
+		total += part
+	}
+	return total + plain
+}
+
+// recordWayMult 依各 way 乘數縮放最後一筆 Way 細項（在 RecordDetail 之後立即呼叫）
+func (sc *ScreenCalculator) recordWayMult(screen []int16, gmr *buf.GameModeResult, comb int, seg1 []int16, seg2 []int16) {
+	if w := sc.wayMult(screen, seg1, seg2); w != comb {
+		gmr.ScaleDetail(gmr.TmpAct.CurrDetail-1, w, comb)
+	}
+}
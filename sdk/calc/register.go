@@ -0,0 +1,79 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+import (
+	"sync"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// Evaluator 自訂算分器（BetType 外掛），與內建算分器共用 RecordDetail / HitsFlat 紀錄流程。
+//
+//   - Calc: 必填。熱路徑算分函式，請以 gmr.RecordDetail / RecordDetailSegments 記錄細項（請避免配置記憶體）。
+//   - Init: 選填。建立 ScreenCalculator 時呼叫一次，可將暫存緩衝放在 sc.Custom。
+//   - Cap: 選填。回傳 (細項數, HitsFlat 格數) 的初始容量估計，供 GameMode 預先配置；未提供時使用保守預設值。
+type Evaluator struct {
+	Calc CalcScreenFn
+	Init func(sc *ScreenCalculator)
+	Cap  func(gms *spec.GameModeSetting) (details int, hits int)
+}
+
+var (
+	evalMu     sync.RWMutex
+	evaluators = map[spec.BetType]Evaluator{}
+)
+
+// RegisterBetType 以名稱註冊自訂 bet_type 與其算分器，設定檔以 hit_setting.bet_type: <name> 使用。
+//
+// 應於程式初始化階段（init）呼叫；同名重複註冊會覆寫算分器。內建名稱不可覆寫。
+//
+// Example:
+//
+//	calc.RegisterBetType("pay_anywhere_8", calc.Evaluator{
+//		Calc: calcPayAnywhere,
+//		Cap:  func(gms *spec.GameModeSetting) (int, int) { return gms.SymbolSetting.SymbolCount, gms.ScreenSetting.ScreenSize * 2 },
+//	})
+func RegisterBetType(name string, ev Evaluator) (spec.BetType, error) {
+	if ev.Calc == nil {
+		return 0, errs.NewFatal("calc.RegisterBetType: Calc is required")
+	}
+	bt, err := spec.RegisterBetType(name)
+	if err != nil {
+		return 0, err
+	}
+	evalMu.Lock()
+	defer evalMu.Unlock()
+	evaluators[bt] = ev
+	return bt, nil
+}
+
+// CapHint 回傳自訂 BetType 的初始容量估計；非自訂或未提供 Cap 時 ok 為 false
+func CapHint(gms *spec.GameModeSetting) (details int, hits int, ok bool) {
+	ev, found := evaluator(gms.HitSetting.BetType)
+	if !found || ev.Cap == nil {
+		return 0, 0, false
+	}
+	details, hits = ev.Cap(gms)
+	return details, hits, true
+}
+
+func evaluator(bt spec.BetType) (Evaluator, bool) {
+	evalMu.RLock()
+	defer evalMu.RUnlock()
+	ev, ok := evaluators[bt]
+	return ev, ok
+}
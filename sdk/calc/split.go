@@ -0,0 +1,94 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calc
+
+// 分裂（split / double）符號：
+//
+// 由遊戲邏輯以 SetCellCount 設定某格算作 n 顆（例如 ops.SplitSymbols），ClearCellCounts 清除。
+//   - Way：該格在所屬軸的計數為 n，組合數（Combinations）隨之放大；Way 贏分為 pay_table × 押注倍數、
+//     不乘組合數，因此分裂不改變 Way 派彩，只影響組合數紀錄與每條 way 乘數的加權（見 multiplier.go）
+//   - Count：該格計入 n 顆（超過派彩表長度時以最後一格計）
+//
+// 命中位置（HitsFlat）仍只記錄一次；Line / Cluster 不受影響。未設定時算分熱路徑不變。
+
+// SetCellCount 設定盤面 idx 格子的計數（<= 1 視為一般格子），對之後的 CalcScreen 生效
+func (sc *ScreenCalculator) SetCellCount(idx int, n int) {
+	sc.CellCounts[idx] = n
+	if n > 1 {
+		sc.cellCountOn = true
+	}
+}
+
+// ClearCellCounts 清除所有格子計數
+func (sc *ScreenCalculator) ClearCellCounts() {
+	if sc.cellCountOn {
+		clear(sc.CellCounts)
+		sc.cellCountOn = false
+	}
+}
+
+// initSplits 配置分裂計數緩衝
+func (sc *ScreenCalculator) initSplits() {
+	sc.CellCounts = make([]int, sc.ScreenSize)
+	if n := sc.SymbolSetting.SymbolCount; n > 0 {
+		sc.payLen = len(sc.PayTableFlat) / n
+	}
+	switch {
+	case sc.symbolInCols != nil: // Way
+		sc.symbolInColsW = make([]int, len(sc.symbolInCols))
+		sc.wildInColsW = make([]int, len(sc.wildInCols))
+	case sc.symbolCounts != nil: // Count
+		sc.extraCounts = make([]int, len(sc.symbolCounts))
+	}
+}
+
+// wayCounts 回傳 Way 組合數使用的每軸計數（有分裂格時為加權版本）
+func (sc *ScreenCalculator) wayCounts(screen []int16) (arr []int, wc []int) {
+	if !sc.cellCountOn {
+		return sc.symbolInCols, sc.wildInCols
+	}
+	copy(sc.symbolInColsW, sc.symbolInCols)
+	copy(sc.wildInColsW, sc.wildInCols)
+	symN := len(sc.symbolCounts)
+	for idx, n := range sc.CellCounts {
+		s := int(screen[idx])
+		if n <= 1 || uint(s) >= uint(symN) {
+			continue
+		}
+		c := idx % sc.Cols
+		sc.symbolInColsW[s*sc.Cols+c] += n - 1
+		if sc.wildMask&(1<<uint(s)) != 0 {
+			sc.wildInColsW[c] += n - 1
+		}
+	}
+	return sc.symbolInColsW, sc.wildInColsW
+}
+
+// countExtras 統計 Count 分裂格帶來的額外顆數（每符號與 wild 總和）
+func (sc *ScreenCalculator) countExtras(screen []int16) {
+	clear(sc.extraCounts)
+	sc.wildExtra = 0
+	symN := len(sc.symbolCounts)
+	for idx, n := range sc.CellCounts {
+		s := int(screen[idx])
+		if n <= 1 || uint(s) >= uint(symN) {
+			continue
+		}
+		sc.extraCounts[s] += n - 1
+		if sc.wildMask&(1<<uint(s)) != 0 {
+			sc.wildExtra += n - 1
+		}
+	}
+}
@@ -15,6 +15,8 @@
 package calc
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/zintix-labs/problab/sdk/buf"
@@ -120,3 +122,346 @@ func TestCalcByCluster(t *testing.T) {
 		t.Fatalf("expected hitmap length 4, got %v", hit)
 	}
 }
+
+func TestCalcByWayIrregular(t *testing.T) {
+	// 1-3-1 菱形：遮罩格為 CellBlocked，每軸 ways 數依該軸實際高度計算
+	gms := buildGameModeSetting(3, 3, "way_ltr", nil, []string{"H1", "W1"}, [][]int{{0, 0, 6}, {0, 0, 0}})
+	gms.ScreenSetting.Mask = []uint8{
+		0, 1, 0,
+		1, 1, 1,
+		0, 1, 0,
+	}
+	sc := NewScreenCalculator(&gms)
+	gmr := buf.NewGameModeResult(0, &gms, 4, 4)
+	b := spec.CellBlocked
+	screen := []int16{
+		b, 0, b,
+		0, 0, 0,
+		b, 1, b,
+	}
+
+	sc.CalcScreen(1, screen, gmr)
+
+	details := gmr.GetDetails()
+	if len(details) != 1 || details[0].Count != 3 || details[0].Combinations != 3 || details[0].HitsFlatLen != 5 {
+		t.Fatalf("unexpected details: %+v", details)
+	}
+	for _, idx := range gmr.HitsFlat {
+		if screen[idx] == b {
+			t.Fatalf("blocked cell %d must not be hit: %v", idx, gmr.HitsFlat)
+		}
+	}
+}
+
+// payAnywhere 測試用自訂 bet_type：同一圖標出現次數達門檻即派彩（Count 直接對應 PayTable），命中格子記入 HitsFlat
+type payAnywhere struct {
+	counts []int
+	hits   [][]int16
+}
+
+func calcPayAnywhere(betMult int, screen []int16, gmr *buf.GameModeResult, sc *ScreenCalculator) {
+	pa := sc.Custom.(*payAnywhere)
+	for s := range pa.counts {
+		pa.counts[s] = 0
+		pa.hits[s] = pa.hits[s][:0]
+	}
+	for i, s := range screen {
+		if uint(s) < uint(len(pa.counts)) {
+			pa.counts[s]++
+			pa.hits[s] = append(pa.hits[s], int16(i))
+		}
+	}
+	for s, n := range pa.counts {
+		if n == 0 || sc.MinPayCount(s) == 0 || n < sc.MinPayCount(s) {
+			continue
+		}
+		base := sc.PayTableIndex[s]
+		win := sc.PayTableFlat[base+min(n, sc.ScreenSize)-1] * betMult
+		gmr.RecordDetail(win, int16(s), 0, n, 0, 0, pa.hits[s])
+	}
+}
+
+var payAnywhereEvaluator = Evaluator{
+	Calc: calcPayAnywhere,
+	Init: func(sc *ScreenCalculator) {
+		n := sc.SymbolSetting.SymbolCount
+		pa := &payAnywhere{counts: make([]int, n), hits: make([][]int16, n)}
+		for s := range pa.hits {
+			pa.hits[s] = make([]int16, 0, sc.ScreenSize)
+		}
+		sc.Custom = pa
+	},
+	Cap: func(gms *spec.GameModeSetting) (int, int) {
+		return gms.SymbolSetting.SymbolCount, gms.ScreenSetting.Columns * gms.ScreenSetting.Rows
+	},
+}
+
+func TestRegisterBetType(t *testing.T) {
+	bt, err := RegisterBetType("pay_anywhere_test", payAnywhereEvaluator)
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if !spec.IsBetTypeCustom(bt) {
+		t.Fatalf("expected custom bet type")
+	}
+	if _, err := RegisterBetType("way_ltr", Evaluator{Calc: calcPayAnywhere}); err == nil {
+		t.Fatalf("expected builtin name conflict error")
+	}
+
+	gms := buildGameModeSetting(3, 1, "pay_anywhere_test", nil, []string{"H1", "H2"}, [][]int{{0, 0, 4}, {0, 2, 3}})
+	sc := NewScreenCalculator(&gms)
+	if d, h, ok := CapHint(&gms); !ok || d != 2 || h != 3 {
+		t.Fatalf("unexpected cap hint: %d %d %v", d, h, ok)
+	}
+	gmr := buf.NewGameModeResult(0, &gms, 4, 4)
+	sc.CalcScreen(2, []int16{1, 0, 1}, gmr)
+	details := gmr.GetDetails()
+	if gmr.GetTmpWin() != 4 || len(details) != 1 || details[0].SymbolID != 1 || details[0].Count != 2 {
+		t.Fatalf("unexpected result: win=%d details=%+v", gmr.GetTmpWin(), details)
+	}
+	if hits := gmr.HitsFlat; len(hits) != 2 || hits[0] != 0 || hits[1] != 2 || details[0].HitsFlatLen != 2 {
+		t.Fatalf("unexpected hits: %v", hits)
+	}
+
+	// 只在 spec 註冊（沒有算分函式）：建立算分器時回傳錯誤而非中止程式
+	if _, err := spec.RegisterBetType("spec_only_test"); err != nil {
+		t.Fatalf("register spec bet type failed: %v", err)
+	}
+	orphan := buildGameModeSetting(3, 1, "spec_only_test", nil, []string{"H1", "H2"}, [][]int{{0, 0, 4}, {0, 2, 3}})
+	if _, err := NewScreenCalculatorE(&orphan); err == nil {
+		t.Fatalf("expected missing calc function error")
+	}
+}
+
+// multMode 以 YAML 建立乘數測試用的遊戲模式：W1 為 2 倍 wild，hit 為 hit_setting 內容（含 multiplier_mode）
+func multMode(t *testing.T, cols, rows int, hit string, pay string) *spec.GameModeSetting {
+	t.Helper()
+	reels := strings.Repeat("\n            - symbols: [0]", cols)
+	y := fmt.Sprintf(`game_name: mult
+game_id: 1
+logic_key: k
+bet_units: [1]
+max_win_limit: 100000
+game_mode_settings:
+  - screen_setting: {columns: %d, rows: %d}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:%s
+    symbol_setting:
+      symbols: [{name: H1}, {name: W1, multiplier: 2}]
+      pay_table: %s
+    hit_setting: %s
+`, cols, rows, reels, pay, hit)
+	gs, err := spec.GetGameSettingByYAML([]byte(y))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	return &gs.GameModeSettings[0]
+}
+
+func TestCalcMultipliers(t *testing.T) {
+	calc := func(gms *spec.GameModeSetting, screen []int16, cells map[int]int) (int, []buf.CalcScreenDetail) {
+		t.Helper()
+		sc := NewScreenCalculator(gms)
+		for idx, m := range cells {
+			sc.SetCellMult(idx, m)
+		}
+		gmr := buf.NewGameModeResult(0, gms, 4, 4)
+		sc.CalcScreen(1, screen, gmr)
+		return gmr.GetTmpWin(), gmr.GetDetails()
+	}
+	line := "{bet_type: line_ltr, line_table: [[0, 0, 0]]}"
+	lineAdd := "{bet_type: line_ltr, line_table: [[0, 0, 0]], multiplier_mode: add}"
+	pay := "[[0, 0, 5], [0, 0, 0]]"
+
+	// 一顆 2 倍 wild：5 * 2
+	if win, d := calc(multMode(t, 3, 1, line, pay), []int16{0, 1, 0}, nil); win != 10 || len(d) != 1 || d[0].Multiplier != 2 {
+		t.Fatalf("unexpected wild multiplier result: win=%d details=%+v", win, d)
+	}
+	// 相乘：2 倍 wild x 3 倍格子 = 6
+	if win, _ := calc(multMode(t, 3, 1, line, pay), []int16{0, 1, 0}, map[int]int{2: 3}); win != 30 {
+		t.Fatalf("expected 5*2*3=30, got %d", win)
+	}
+	// 相加：2 + 3 = 5
+	if win, _ := calc(multMode(t, 3, 1, lineAdd, pay), []int16{0, 1, 0}, map[int]int{2: 3}); win != 25 {
+		t.Fatalf("expected 5*(2+3)=25, got %d", win)
+	}
+	// 只有格子乘數
+	if win, d := calc(multMode(t, 3, 1, line, pay), []int16{0, 0, 0}, map[int]int{1: 4}); win != 20 || d[0].Multiplier != 4 {
+		t.Fatalf("expected cell multiplier on hit cell, got win=%d", win)
+	}
+
+	// Line：以乘上乘數後的贏分選擇串長（W1 W1 H1：wild 2 連 4*2*2=16，normal 3 連 5*2*2=20）
+	wildPay := "[[0, 0, 5], [0, 4, 0]]"
+	if win, d := calc(multMode(t, 3, 1, line, wildPay), []int16{1, 1, 0}, map[int]int{2: 3}); win != 60 || d[0].SymbolID != 0 || d[0].Multiplier != 12 {
+		t.Fatalf("expected normal run 5*2*2*3=60, got win=%d details=%+v", win, d)
+	}
+	// 沒有乘數時 wild 串較高（8 > 5）；第 3 格的 3 倍格子只在 normal 串上，比較後應改選 normal
+	if win, d := calc(multMode(t, 3, 1, "{bet_type: line_ltr, line_table: [[0, 0, 0]], multiplier_mode: add}", "[[0, 0, 5], [0, 8, 0]]"), []int16{1, 1, 0}, map[int]int{2: 3}); win != 35 || d[0].SymbolID != 0 {
+		t.Fatalf("expected normal run 5*(2+2+3)=35 over wild run 8*(2+2)=32, got win=%d details=%+v", win, d)
+	}
+
+	// Count / Cluster：命中格乘數合併後乘上贏分
+	for _, hit := range []string{"{bet_type: count}", "{bet_type: cluster}"} {
+		if win, d := calc(multMode(t, 3, 1, hit, pay), []int16{0, 1, 0}, map[int]int{0: 3}); win != 30 || d[0].Multiplier != 6 {
+			t.Fatalf("%s: expected 5*2*3=30, got win=%d details=%+v", hit, win, d)
+		}
+	}
+}
+
+func TestCalcWayMultipliers(t *testing.T) {
+	calc := func(hit string, screen []int16, cells map[int]int) (int, []buf.CalcScreenDetail) {
+		t.Helper()
+		gms := multMode(t, 3, 2, hit, "[[0, 0, 6], [0, 0, 0]]")
+		sc := NewScreenCalculator(gms)
+		for idx, m := range cells {
+			sc.SetCellMult(idx, m)
+		}
+		gmr := buf.NewGameModeResult(0, gms, 4, 4)
+		sc.CalcScreen(1, screen, gmr)
+		return gmr.GetTmpWin(), gmr.GetDetails()
+	}
+	b := spec.CellBlocked
+
+	// 第 2 軸兩顆 2 倍 wild：每條 way 只經過其中一顆，乘數為 2 而非 4（8 條 way 總和 16）
+	for _, hit := range []string{"{bet_type: way_ltr}", "{bet_type: way_ltr, multiplier_mode: add}", "{bet_type: way_rtl}"} {
+		win, d := calc(hit, []int16{
+			0, 1, 0,
+			0, 1, 0,
+		}, nil)
+		if win != 12 || len(d) != 1 || d[0].Combinations != 8 || d[0].Multiplier != 16 {
+			t.Fatalf("%s: expected 6*16/8=12, got win=%d details=%+v", hit, win, d)
+		}
+	}
+	// 兩條 way 只有一條經過 wild：6 * (1+2) / 2 = 9
+	if win, d := calc("{bet_type: way_ltr}", []int16{
+		0, 0, 0,
+		b, 1, b,
+	}, nil); win != 9 || d[0].Combinations != 2 || d[0].Multiplier != 3 {
+		t.Fatalf("expected 6*3/2=9, got win=%d details=%+v", win, d)
+	}
+	// 相乘與相加逐條 way 合併：第 2 軸 wild 另有 3 倍格子
+	screen := []int16{
+		0, 0, 0,
+		b, 1, 1,
+	}
+	// 相乘：軸乘數和 1 x (1+6) x (1+2) = 21，6 * 21 / 4 = 31（向下取整）
+	if win, d := calc("{bet_type: way_ltr}", screen, map[int]int{4: 3}); win != 31 || d[0].Combinations != 4 || d[0].Multiplier != 21 {
+		t.Fatalf("expected 6*21/4=31, got win=%d details=%+v", win, d)
+	}
+	// 相加：四條 way 為 1、2、5、5+2，總和 15，6 * 15 / 4 = 22
+	if win, d := calc("{bet_type: way_ltr, multiplier_mode: add}", screen, map[int]int{4: 3}); win != 22 || d[0].Multiplier != 15 {
+		t.Fatalf("expected 6*15/4=22, got win=%d details=%+v", win, d)
+	}
+	// 沒有乘數時不記錄 Multiplier
+	if win, d := calc("{bet_type: way_ltr}", []int16{0, 0, 0, 0, 0, 0}, nil); win != 6 || d[0].Multiplier != 0 {
+		t.Fatalf("expected plain way win, got win=%d details=%+v", win, d)
+	}
+}
+
+func TestCalcByClusterRules(t *testing.T) {
+	run := func(cols, rows int, cs spec.ClusterSetting, payTable [][]int, screen []int16) *buf.GameModeResult {
+		t.Helper()
+		gms := buildGameModeSetting(cols, rows, "cluster", nil, []string{"H1", "H2", "W1"}, payTable)
+		gms.HitSetting.Cluster = cs
+		if err := gms.HitSetting.Init(); err != nil {
+			t.Fatalf("hit setting init failed: %v", err)
+		}
+		sc := NewScreenCalculator(&gms)
+		gmr := buf.NewGameModeResult(0, &gms, 8, 8)
+		sc.CalcScreen(1, screen, gmr)
+		if allocs := testing.AllocsPerRun(10, func() {
+			gmr.Discard()
+			sc.CalcScreen(1, screen, gmr)
+		}); allocs != 0 {
+			t.Fatalf("expected zero allocs, got %v", allocs)
+		}
+		return gmr
+	}
+	pay := [][]int{{0, 0, 5, 10}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	diag := []int16{
+		0, 1, 1,
+		1, 0, 1,
+		1, 1, 0,
+	}
+	// 4 鄰接：對角不相連
+	if gmr := run(3, 3, spec.ClusterSetting{}, pay, diag); gmr.GetTmpWin() != 0 {
+		t.Fatalf("expected no 4-neighbour cluster, got %d", gmr.GetTmpWin())
+	}
+	// 8 鄰接：對角 3 顆相連
+	if gmr := run(3, 3, spec.ClusterSetting{Adjacency: 8}, pay, diag); gmr.GetTmpWin() != 5 {
+		t.Fatalf("expected 8-neighbour cluster of 3, got %d", gmr.GetTmpWin())
+	}
+	// 最小集群大小
+	if gmr := run(3, 3, spec.ClusterSetting{Adjacency: 8, MinSize: 4}, pay, diag); gmr.GetTmpWin() != 0 {
+		t.Fatalf("expected min_size to reject cluster, got %d", gmr.GetTmpWin())
+	}
+
+	// wild 位於兩個集群之間：預設共用（兩個集群都派彩），exclusive 只給第一個
+	shared := []int16{
+		0, 0, 2, 1, 1,
+	}
+	if gmr := run(5, 1, spec.ClusterSetting{}, [][]int{{0, 0, 5, 0, 0}, {0, 0, 7, 0, 0}, {0, 0, 0, 0, 0}}, shared); gmr.GetTmpWin() != 12 {
+		t.Fatalf("expected shared wild to pay both clusters, got %d", gmr.GetTmpWin())
+	}
+	if gmr := run(5, 1, spec.ClusterSetting{WildExclusive: true}, [][]int{{0, 0, 5, 0, 0}, {0, 0, 7, 0, 0}, {0, 0, 0, 0, 0}}, shared); gmr.GetTmpWin() != 5 {
+		t.Fatalf("expected exclusive wild to pay first cluster only, got %d", gmr.GetTmpWin())
+	}
+
+	// 分段派彩：[2, 4] → 2-3 顆第 0 欄，4+ 第 1 欄
+	tiers := spec.ClusterSetting{PayTiers: []int{2, 4}}
+	tierPay := [][]int{{3, 9}, {0, 0}, {0, 0}}
+	if gmr := run(5, 1, tiers, tierPay, []int16{0, 0, 0, 1, 1}); gmr.GetTmpWin() != 3 {
+		t.Fatalf("expected tier 0 pay, got %d", gmr.GetTmpWin())
+	}
+	if gmr := run(5, 1, tiers, tierPay, []int16{0, 0, 0, 0, 0}); gmr.GetTmpWin() != 9 {
+		t.Fatalf("expected tier 1 pay, got %d", gmr.GetTmpWin())
+	}
+
+	// 六角格（偶數軸下移）：(0,0) 與 (1,1) 在方格不相鄰，六角格相鄰
+	hex := []int16{
+		0, 1,
+		1, 0,
+	}
+	if gmr := run(2, 2, spec.ClusterSetting{Topology: spec.ClusterTopologyHexEvenCol}, [][]int{{0, 5, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}, hex); gmr.GetTmpWin() != 5 {
+		t.Fatalf("expected hex neighbours to connect, got %d", gmr.GetTmpWin())
+	}
+}
+
+func TestCalcSplitCounts(t *testing.T) {
+	// Way：分裂格放大組合數，贏分不乘組合數（派彩不變）
+	gms := buildGameModeSetting(3, 2, "way_ltr", nil, []string{"H1", "L1"}, [][]int{{0, 3, 6}, {0, 0, 0}})
+	sc := NewScreenCalculator(&gms)
+	gmr := buf.NewGameModeResult(0, &gms, 4, 4)
+	screen := []int16{
+		0, 0, 0,
+		1, 0, 1,
+	}
+	sc.SetCellCount(4, 2)
+	sc.CalcScreen(2, screen, gmr)
+	if d := gmr.GetDetails(); len(d) != 1 || d[0].Combinations != 3 || d[0].HitsFlatLen != 4 || d[0].Win != 12 {
+		t.Fatalf("unexpected split way details: %+v", d)
+	}
+	gmr.AddAct(buf.FinishAct, "spin", nil, nil)
+	sc.ClearCellCounts()
+	sc.CalcScreen(2, screen, gmr)
+	if d := gmr.GetDetails(); d[len(d)-1].Combinations != 2 || d[len(d)-1].Win != 12 {
+		t.Fatalf("expected combinations reset after clear, got %+v", d)
+	}
+
+	// Count：分裂格以 n 顆計入，超過派彩表長度以最後一格計
+	gms = buildGameModeSetting(3, 1, "count", nil, []string{"H1", "L1"}, [][]int{{0, 2, 4}, {0, 0, 0}})
+	sc = NewScreenCalculator(&gms)
+	gmr = buf.NewGameModeResult(0, &gms, 4, 4)
+	screen = []int16{0, 1, 1}
+	for _, tc := range []struct{ n, win int }{{1, 0}, {2, 2}, {3, 4}, {5, 4}} {
+		gmr.Reset()
+		sc.SetCellCount(0, tc.n)
+		sc.CalcScreen(1, screen, gmr)
+		if gmr.GetTmpWin() != tc.win {
+			t.Fatalf("split x%d: expected win %d, got %d", tc.n, tc.win, gmr.GetTmpWin())
+		}
+	}
+}
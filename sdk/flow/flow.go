@@ -0,0 +1,264 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flow 提供宣告式的通用遊戲邏輯（LogicKey: flow），簡單流程的遊戲不需撰寫 Go 邏輯檔。
+//
+// 流程寫在 fixed 區塊（見 Setting），例如「base 消除 → 3 顆 scatter 觸發 10 局 free（可 retrigger）」：
+//
+//	logic_key: flow
+//	fixed:
+//	  steps:
+//	    - mode: 0
+//	      cascade: {max_step: 30, refill: refill}
+//	      scatter_pay: {symbol: C1, pays: [0, 0, 2, 5, 20]}
+//	      triggers:
+//	        - {symbol: C1, count: 3, goto: 1}
+//	    - mode: 1
+//	      rounds: 10
+//	      max_rounds: 50
+//	      cascade: {max_step: 30, refill: refill}
+//	      triggers:
+//	        - {symbol: C1, count: 3, goto: 1, rounds: 5}
+//
+// 每局的 Act 與 demo 遊戲相同（gen_screen / win / clear / gravity / fillscreen / scatter_pay / trigger），
+// 每個步驟產出一個 GameModeResult，依序附加到 SpinResult。
+//
+// 使用時把 flow.Logics 加入 problab.Logics(...)。
+package flow
+
+import (
+	"log"
+
+	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/sdk/ops"
+	"github.com/zintix-labs/problab/sdk/slot"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// LogicKey 通用流程邏輯的 logic_key
+const LogicKey spec.LogicKey = "flow"
+
+// Logics 只含通用流程邏輯的註冊表
+var Logics = slot.NewLogicRegistry()
+
+func init() {
+	if err := slot.GameRegister(LogicKey, build, Logics); err != nil {
+		log.Fatalf("%s register failed: %v", LogicKey, err)
+	}
+	if err := dto.RegisterExtendRender[Extend](LogicKey); err != nil {
+		log.Fatalf("%s register failed: %v", LogicKey, err)
+	}
+}
+
+// Extend 觸發 Act 的 ExtendResult
+type Extend struct {
+	Symbol int16 `json:"symbol"` // 觸發符號
+	Count  int   `json:"count"`  // 符號顆數
+	Hits   []int `json:"hits"`   // 符號位置
+	Goto   int   `json:"goto"`   // 目標步驟
+	Rounds int   `json:"rounds"` // 給予局數
+	isSim  bool
+}
+
+// Reset 清空到初始狀態（保留容量）
+func (e *Extend) Reset() {
+	e.Symbol = 0
+	e.Count = 0
+	e.Hits = e.Hits[:0]
+	e.Goto = 0
+	e.Rounds = 0
+}
+
+// Snapshot 建立深拷貝；模擬模式回傳 nil
+func (e *Extend) Snapshot() any {
+	if e.isSim {
+		return nil
+	}
+	return &Extend{
+		Symbol: e.Symbol,
+		Count:  e.Count,
+		Hits:   append([]int(nil), e.Hits...),
+		Goto:   e.Goto,
+		Rounds: e.Rounds,
+	}
+}
+
+type game struct {
+	set     *Setting
+	ext     *Extend
+	fillIdx []int // 消除補盤：每軸補盤位置
+	fillPos []int // 消除補盤：每軸輪帶位置
+	refill  []*spec.ReelSet
+	hits    []int16 // scatter 派彩命中位置
+}
+
+func build(g *slot.Game) (slot.GameLogic, error) {
+	set := new(Setting)
+	if err := spec.DecodeFixed(g.GameSetting, set); err != nil {
+		return nil, err
+	}
+	if err := set.init(g.GameSetting); err != nil {
+		return nil, err
+	}
+	maxCols, maxSize := 0, 0
+	for _, gms := range g.GameSetting.GameModeSettings {
+		maxCols = max(maxCols, gms.ScreenSetting.Columns)
+		maxSize = max(maxSize, gms.ScreenSetting.ScreenSize)
+	}
+	gm := &game{
+		set: set,
+		ext: &Extend{
+			Hits:  make([]int, 0, maxSize),
+			isSim: g.IsSim,
+		},
+		fillIdx: make([]int, maxCols),
+		fillPos: make([]int, maxCols),
+		refill:  make([]*spec.ReelSet, len(set.Steps)),
+		hits:    make([]int16, 0, maxSize),
+	}
+	for i, st := range set.Steps {
+		if st.Cascade != nil {
+			gm.refill[i], _ = g.GameModeHandlerList[st.Mode].GameModeSetting.GenScreenSetting.ReelSetByName(st.Cascade.Refill)
+		}
+	}
+	return gm, nil
+}
+
+// GetResult 依流程設定跑完一次 spin
+func (g *game) GetResult(r *buf.SpinRequest, gh *slot.Game) *buf.SpinResult {
+	sr := gh.StartNewSpin(r)
+	step, rounds := 0, 1
+	for step >= 0 {
+		var gmr *buf.GameModeResult
+		gmr, step, rounds = g.runStep(step, rounds, r.BetMult, gh)
+		sr.AppendModeResult(gmr)
+	}
+	sr.End()
+	return sr
+}
+
+// runStep 跑完一個步驟的所有局數，回傳結果、下一個步驟（-1 表示結束）與其局數
+func (g *game) runStep(step int, rounds int, betMult int, gh *slot.Game) (*buf.GameModeResult, int, int) {
+	st := &g.set.Steps[step]
+	mode := gh.GameModeHandlerList[st.Mode]
+	gmr := mode.GameModeResult
+	next := -1
+	nextRounds := 0
+	for i := 0; i < rounds; i++ {
+		screen := mode.ScreenGenerator.GenScreen()
+		gmr.AddAct(buf.FinishAct, "gen_screen", screen, nil)
+		if st.Cascade != nil {
+			g.cascade(step, betMult, screen, mode)
+		} else {
+			mode.ScreenCalculator.CalcScreen(betMult, screen, gmr)
+			if gmr.GetTmpWin() > 0 {
+				gmr.AddAct(buf.FinishAct, "win", nil, nil)
+			}
+		}
+		if st.ScatterPay != nil {
+			g.scatterPay(st.ScatterPay, betMult, screen, gmr)
+		}
+		if tr := g.trigger(st, screen); tr != nil {
+			gmr.Trigger = 1
+			gmr.AddAct(buf.FinishAct, "trigger", nil, g.ext)
+			switch {
+			case tr.Goto == step: // retrigger
+				rounds = min(rounds+g.ext.Rounds, st.MaxRounds) // init 保證有 retrigger 時 MaxRounds > 0
+			case next < 0: // 只接受第一個轉移
+				next, nextRounds = tr.Goto, g.ext.Rounds
+			}
+		}
+		gmr.FinishRound()
+	}
+	return mode.YieldResult(), next, nextRounds
+}
+
+// cascade 消除補盤直到沒有中獎或達 max_step（流程同 demo_cascade）
+func (g *game) cascade(step int, betMult int, screen []int16, mode *slot.GameMode) {
+	sg, sc, gmr := mode.ScreenGenerator, mode.ScreenCalculator, mode.GameModeResult
+	refill := g.refill[step]
+	fillIdx, fillPos := g.fillIdx[:sg.Cols], g.fillPos[:sg.Cols]
+	clear(fillIdx)
+	for c := range fillPos {
+		fillPos[c] = refill.Reels[c].ReelLUT.Pick(sg.Core())
+	}
+	for range g.set.Steps[step].Cascade.MaxStep {
+		sc.CalcScreen(betMult, screen, gmr)
+		if gmr.GetTmpWin() == 0 {
+			gmr.FinishStep()
+			return
+		}
+		hit := gmr.HitMapTmp()
+		gmr.AddAct(buf.FinishAct, "win", nil, nil)
+
+		ops.Clear(screen, hit)
+		gmr.AddAct(buf.FinishStep, "clear", screen, nil)
+
+		ops.Gravity(screen, sg.Cols, sg.Rows, fillIdx)
+		gmr.AddAct(buf.FinishStep, "gravity", screen, nil)
+
+		ops.FillScreen(screen, refill, fillIdx, fillPos, sg.Cols)
+		gmr.AddAct(buf.FinishStep, "fillscreen", screen, nil)
+	}
+}
+
+// scatterPay 依全盤符號顆數派彩
+func (g *game) scatterPay(sp *ScatterPay, betMult int, screen []int16, gmr *buf.GameModeResult) {
+	hits := g.hits[:0]
+	for i, s := range screen {
+		if s == sp.symbol {
+			hits = append(hits, int16(i))
+		}
+	}
+	g.hits = hits
+	if len(hits) == 0 {
+		return
+	}
+	win := sp.Pays[min(len(hits), len(sp.Pays))-1] * betMult
+	if win <= 0 {
+		return
+	}
+	gmr.RecordDetail(win, sp.symbol, 0, len(hits), 0, 0, hits)
+	gmr.AddAct(buf.FinishAct, "scatter_pay", nil, nil)
+}
+
+// trigger 依序檢查觸發條件，成立時填好 ext 並回傳該條件
+func (g *game) trigger(st *Step, screen []int16) *Trigger {
+	ext := g.ext
+	for k := range st.Triggers {
+		tr := &st.Triggers[k]
+		ext.Reset()
+		for i, s := range screen {
+			if s == tr.symbol {
+				ext.Hits = append(ext.Hits, i)
+			}
+		}
+		if len(ext.Hits) < tr.Count {
+			continue
+		}
+		ext.Symbol = tr.symbol
+		ext.Count = len(ext.Hits)
+		ext.Goto = tr.Goto
+		ext.Rounds = tr.Rounds
+		if ext.Rounds == 0 {
+			ext.Rounds = g.set.Steps[tr.Goto].Rounds
+		}
+		return tr
+	}
+	ext.Reset()
+	return nil
+}
+
+var _ slot.GameLogic = (*game)(nil)
@@ -0,0 +1,133 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// Setting 流程設定（即 GameSetting.fixed 的內容）
+//
+// Steps 第 0 個為起始步驟（通常是 base game，固定只跑 1 局）。
+type Setting struct {
+	Steps []Step `yaml:"steps" json:"steps"`
+}
+
+// Step 流程中的一個步驟：在指定模式上跑 Rounds 局
+//
+// Fields:
+//   - Mode: game_mode_settings 索引（多個步驟可共用同一個模式）
+//   - Rounds: 局數（第 0 步固定為 1；由觸發進入時可被 Trigger.Rounds 覆寫）
+//   - MaxRounds: retrigger 累計局數上限；有 retrigger（goto 本步驟）的觸發時必填（> 0），保證流程必定結束
+//   - Cascade: 消除補盤設定，留空表示不消除
+//   - ScatterPay: scatter 派彩，留空表示無
+//   - Triggers: 每局結束時依序檢查，第一個成立者生效
+type Step struct {
+	Mode       int         `yaml:"mode"                  json:"mode"`
+	Rounds     int         `yaml:"rounds,omitempty"      json:"rounds,omitempty"`
+	MaxRounds  int         `yaml:"max_rounds,omitempty"  json:"max_rounds,omitempty"`
+	Cascade    *Cascade    `yaml:"cascade,omitempty"     json:"cascade,omitempty"`
+	ScatterPay *ScatterPay `yaml:"scatter_pay,omitempty" json:"scatter_pay,omitempty"`
+	Triggers   []Trigger   `yaml:"triggers,omitempty"    json:"triggers,omitempty"`
+}
+
+// Cascade 消除補盤：中獎格消除、掉落後以 Refill 輪帶組補滿，直到沒有中獎或達 MaxStep
+type Cascade struct {
+	MaxStep int    `yaml:"max_step" json:"max_step"`
+	Refill  string `yaml:"refill"   json:"refill"` // 補盤輪帶組名稱（reel_set_group[].name）
+}
+
+// ScatterPay 依全盤符號顆數派彩（押注倍數），Pays[n-1] 為 n 顆的派彩，超過長度以最後一格計
+type ScatterPay struct {
+	Symbol string `yaml:"symbol" json:"symbol"`
+	Pays   []int  `yaml:"pays"   json:"pays"`
+	symbol int16
+}
+
+// Trigger 觸發條件：局末盤面上 Symbol 至少 Count 顆
+//
+// Goto 為目標步驟索引：等於目前步驟時為 retrigger（加局），否則必須是之後的步驟，
+// 目前步驟跑完後轉入。Rounds 為給予的局數，0 表示使用目標步驟的 rounds。
+type Trigger struct {
+	Symbol string `yaml:"symbol"           json:"symbol"`
+	Count  int    `yaml:"count"            json:"count"`
+	Goto   int    `yaml:"goto"             json:"goto"`
+	Rounds int    `yaml:"rounds,omitempty" json:"rounds,omitempty"`
+	symbol int16
+}
+
+// init 依遊戲設定檢查流程並解析符號
+func (s *Setting) init(gs *spec.GameSetting) error {
+	if len(s.Steps) == 0 {
+		return errs.NewFatal("flow: steps is empty")
+	}
+	for i := range s.Steps {
+		st := &s.Steps[i]
+		if st.Mode < 0 || st.Mode >= len(gs.GameModeSettings) {
+			return errs.Fatalf("flow: steps[%d].mode %d out of range", i, st.Mode)
+		}
+		gms := &gs.GameModeSettings[st.Mode]
+		if i == 0 {
+			st.Rounds = 1
+		}
+		if st.Rounds < 1 {
+			return errs.Fatalf("flow: steps[%d].rounds must be >= 1, got %d", i, st.Rounds)
+		}
+		if st.MaxRounds < 0 {
+			return errs.Fatalf("flow: steps[%d].max_rounds must be >= 0, got %d", i, st.MaxRounds)
+		}
+		if c := st.Cascade; c != nil {
+			if c.MaxStep < 1 {
+				return errs.Fatalf("flow: steps[%d].cascade.max_step must be >= 1, got %d", i, c.MaxStep)
+			}
+			if _, ok := gms.GenScreenSetting.ReelSetByName(c.Refill); !ok {
+				return errs.Fatalf("flow: steps[%d].cascade.refill: reel set %q not found", i, c.Refill)
+			}
+		}
+		if sp := st.ScatterPay; sp != nil {
+			id, ok := gms.SymbolSetting.Lookup(sp.Symbol)
+			if !ok {
+				return errs.Fatalf("flow: steps[%d].scatter_pay: unknown symbol %s", i, sp.Symbol)
+			}
+			if len(sp.Pays) == 0 {
+				return errs.Fatalf("flow: steps[%d].scatter_pay.pays is empty", i)
+			}
+			sp.symbol = int16(id)
+		}
+		for k := range st.Triggers {
+			tr := &st.Triggers[k]
+			id, ok := gms.SymbolSetting.Lookup(tr.Symbol)
+			if !ok {
+				return errs.Fatalf("flow: steps[%d].triggers[%d]: unknown symbol %s", i, k, tr.Symbol)
+			}
+			if tr.Count < 1 {
+				return errs.Fatalf("flow: steps[%d].triggers[%d].count must be >= 1, got %d", i, k, tr.Count)
+			}
+			// 只能 retrigger 或往後轉移，保證流程必定結束
+			if tr.Goto < i || tr.Goto >= len(s.Steps) || (tr.Goto == i && i == 0) {
+				return errs.Fatalf("flow: steps[%d].triggers[%d].goto %d must be this step (retrigger) or a later step", i, k, tr.Goto)
+			}
+			if tr.Rounds < 0 {
+				return errs.Fatalf("flow: steps[%d].triggers[%d].rounds must be >= 0, got %d", i, k, tr.Rounds)
+			}
+			if tr.Goto == i && st.MaxRounds == 0 {
+				return errs.Fatalf("flow: steps[%d].max_rounds must be > 0 when triggers[%d] retriggers the step", i, k)
+			}
+			tr.symbol = int16(id)
+		}
+	}
+	return nil
+}
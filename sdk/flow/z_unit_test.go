@@ -0,0 +1,161 @@
+package flow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zintix-labs/problab/demo/demo_configs"
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/slot"
+	"github.com/zintix-labs/problab/spec"
+)
+
+const flowYAML = `game_name: t
+game_id: 1
+logic_key: flow
+bet_units: [10]
+max_win_limit: 100000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [1]
+            - symbols: [1]
+            - symbols: [1]
+    symbol_setting:
+      symbol_used: [H1, C1]
+      pay_table: [[0, 0, 0], [0, 0, 0]]
+    hit_setting:
+      bet_type: count
+  - screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [FREE]
+            - symbols: [1]
+            - symbols: [1]
+    symbol_setting:
+      symbol_used: [H1, C1]
+      pay_table: [[0, 0, 0], [0, 0, 0]]
+    hit_setting:
+      bet_type: count
+fixed:
+  steps:
+    - mode: 0
+      scatter_pay: {symbol: C1, pays: [0, 0, 2]}
+      triggers:
+        - {symbol: C1, count: 3, goto: 1}
+    - mode: 1
+      rounds: 2
+      max_rounds: 4
+      triggers:
+        - {symbol: C1, count: 3, goto: 1, rounds: 1}
+`
+
+func newFlowGame(t *testing.T, yml string) (*slot.Game, error) {
+	t.Helper()
+	gs, err := spec.GetGameSettingByYAML([]byte(yml))
+	if err != nil {
+		t.Fatalf("parse setting: %v", err)
+	}
+	return slot.NewGame(gs, Logics, core.New(core.Default().New(1)), false)
+}
+
+func TestFlowTriggerAndRetrigger(t *testing.T) {
+	// free 盤面全為 C1：每局 retrigger，直到 max_rounds
+	g, err := newFlowGame(t, strings.ReplaceAll(flowYAML, "FREE", "1"))
+	if err != nil {
+		t.Fatalf("build flow game: %v", err)
+	}
+	sr := g.GetResult(&buf.SpinRequest{BetMult: 3})
+	if sr.GameModeCount != 2 || !sr.IsGameEnd {
+		t.Fatalf("expected base + free, got %d modes", sr.GameModeCount)
+	}
+	base, free := sr.GameModeList[0], sr.GameModeList[1]
+	if base.Trigger != 1 || base.TotalWin != 6 {
+		t.Fatalf("expected base trigger with scatter pay 2*3, got trigger=%d win=%d", base.Trigger, base.TotalWin)
+	}
+	last := base.ActResults[len(base.ActResults)-1]
+	if ext := last.ExtendResult.(*Extend); last.ActType != "trigger" || ext.Count != 3 || ext.Rounds != 2 {
+		t.Fatalf("unexpected trigger act: %+v", last)
+	}
+	if rounds := free.TmpAct.CurrRound; rounds != 4 {
+		t.Fatalf("expected free rounds capped at 4, got %d", rounds)
+	}
+	if sr.TotalWin != 6 {
+		t.Fatalf("expected total win 6, got %d", sr.TotalWin)
+	}
+
+	// 沒有 retrigger：跑滿 rounds
+	g, err = newFlowGame(t, strings.ReplaceAll(flowYAML, "FREE", "0"))
+	if err != nil {
+		t.Fatalf("build flow game: %v", err)
+	}
+	sr = g.GetResult(&buf.SpinRequest{BetMult: 1})
+	if free := sr.GameModeList[1]; free.TmpAct.CurrRound != 2 || free.Trigger != 0 {
+		t.Fatalf("expected 2 free rounds without retrigger, got %d", free.TmpAct.CurrRound)
+	}
+}
+
+func TestFlowSettingErrors(t *testing.T) {
+	for _, bad := range []string{
+		strings.Replace(flowYAML, "mode: 1", "mode: 2", 1),
+		strings.Replace(flowYAML, "rounds: 2", "rounds: 0", 1),
+		strings.Replace(flowYAML, "{symbol: C1, count: 3, goto: 1}", "{symbol: C9, count: 3, goto: 1}", 1),
+		strings.Replace(flowYAML, "{symbol: C1, count: 3, goto: 1}", "{symbol: C1, count: 3, goto: 0}", 1),
+		strings.Replace(flowYAML, "scatter_pay: {symbol: C1, pays: [0, 0, 2]}", "cascade: {max_step: 3, refill: nope}", 1),
+		strings.Replace(flowYAML, "max_rounds: 4", "max_round: 4", 1),
+		strings.Replace(flowYAML, "max_rounds: 4", "max_rounds: 0", 1),
+	} {
+		if _, err := newFlowGame(t, strings.ReplaceAll(bad, "FREE", "1")); err == nil {
+			t.Fatalf("expected build error for:\n%s", bad)
+		}
+	}
+}
+
+func TestFlowCascadeDemoConfig(t *testing.T) {
+	// demo_cascade 的流程改以設定描述
+	raw, err := demo_configs.FS.ReadFile("game_1_democascade.yaml")
+	if err != nil {
+		t.Fatalf("read demo config: %v", err)
+	}
+	yml := string(raw)
+	yml = strings.Replace(yml, "logic_key: demo_cascade", "logic_key: flow", 1)
+	yml = yml[:strings.Index(yml, "\nfixed:")] + `
+fixed:
+  steps:
+    - mode: 0
+      cascade: {max_step: 1000, refill: refill}
+      triggers:
+        - {symbol: C1, count: 3, goto: 1}
+    - mode: 1
+      rounds: 10
+      cascade: {max_step: 1000, refill: refill}
+`
+	g, err := newFlowGame(t, yml)
+	if err != nil {
+		t.Fatalf("build flow game: %v", err)
+	}
+	cascades := 0
+	for range 200 {
+		sr := g.GetResult(&buf.SpinRequest{BetMult: 1})
+		base := sr.GameModeList[0]
+		if (base.Trigger != 0) != (sr.GameModeCount == 2) {
+			t.Fatalf("trigger and mode count mismatch: trigger=%d modes=%d", base.Trigger, sr.GameModeCount)
+		}
+		for _, a := range base.ActResults {
+			if a.ActType == "fillscreen" {
+				cascades++
+			}
+		}
+	}
+	if cascades == 0 {
+		t.Fatalf("expected cascades in 200 spins")
+	}
+}
@@ -15,8 +15,7 @@
 package gen
 
 import (
-	"log"
-
+	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/core"
 	"github.com/zintix-labs/problab/spec"
 )
@@ -28,6 +27,13 @@ type GenScreenFn func(*ScreenGenerator, []spec.Reel) []int16
 var genScreenMap = map[spec.GenReelType]GenScreenFn{
 	spec.GenReelByReelIdx:      genScreenByReelIdx,
 	spec.GenReelBySymbolWeight: genScreenBySymbolWeight,
+	spec.GenReelByHeight:       genScreenByHeight,
+}
+
+// genMaskedScreenMap 不規則盤面（Mask 含 0）使用的生成函式：只寫入可用格子
+var genMaskedScreenMap = map[spec.GenReelType]GenScreenFn{
+	spec.GenReelByReelIdx:      genMaskedScreenByReelIdx,
+	spec.GenReelBySymbolWeight: genMaskedScreenBySymbolWeight,
 }
 
 // ScreenGenerator 保存生成盤面所需的所有狀態。
@@ -39,7 +45,9 @@ type ScreenGenerator struct {
 	// ScreenSetting 內容建立
 	Cols         int
 	Rows         int
-	WriteableIdx []int // 可寫入格子idx（從Mask轉化）
+	WriteableIdx []int   // 可寫入格子idx（從Mask轉化）
+	ColCells     [][]int // 每軸可寫入格子idx（由上到下；不規則盤面使用）
+	Heights      []int   // 最近一次生成的每軸可見高度（GenReelByHeight 使用，不含 TopReel）
 	// GenScreenSetting 內容建立
 	ReelSetGroup []spec.ReelSet
 	// 生成函數以及盤面Buffer(避免重複判斷以及重複new盤面)
@@ -49,14 +57,29 @@ type ScreenGenerator struct {
 
 // NewScreenGenerator 根據設定與核心亂數器建立生成器，並立即完成初始化，
 // 讓之後的生成流程可以免配置快速執行。
+//
+// 設定無法初始化時 panic；需要取得錯誤時請改用 NewScreenGeneratorE。
 func NewScreenGenerator(core *core.Core, screenSetting *spec.ScreenSetting, genScreenSetting *spec.GenScreenSetting) *ScreenGenerator {
+	sg, err := NewScreenGeneratorE(core, screenSetting, genScreenSetting)
+	if err != nil {
+		panic(err)
+	}
+	return sg
+}
+
+// NewScreenGeneratorE 與 NewScreenGenerator 相同，但以錯誤回報初始化失敗：
+// 設定無法初始化、或 gen_reel_type 沒有對應的生成函式（例如只以 spec.RegisterGenReelType 註冊、
+// 未經 gen.Register）。
+func NewScreenGeneratorE(core *core.Core, screenSetting *spec.ScreenSetting, genScreenSetting *spec.GenScreenSetting) (*ScreenGenerator, error) {
 	result := &ScreenGenerator{
 		core:             core,
 		ScreenSetting:    screenSetting,
 		GenScreenSetting: genScreenSetting,
 	}
-	_ = result.init()
-	return result
+	if err := result.init(); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // init 對於已經資料賦值的 ScreenGenerator 作初始化
@@ -79,19 +102,41 @@ func (sg *ScreenGenerator) init() error {
 		}
 	}
 
+	sg.ColCells = sg.ScreenSetting.ColCells
+	sg.Heights = make([]int, sg.Cols)
+
 	// GenScreenSetting 內容建立
 	sg.ReelSetGroup = sg.GenScreenSetting.ReelSetGroup
 
 	// 生成函數以及盤面Buffer(避免重複判斷以及重複new盤面)
-	if val, ok := genScreenMap[sg.GenScreenSetting.GenReelType]; ok {
+	fnMap := genScreenMap
+	if sg.ScreenSetting.Irregular {
+		fnMap = genMaskedScreenMap
+	}
+	if val, ok := fnMap[sg.GenScreenSetting.GenReelType]; ok {
 		sg.genScreenFn = val
+	} else if bind, ok := customGen(sg.GenScreenSetting.GenReelType); ok {
+		sg.genScreenFn = bind(sg.GenScreenSetting.Custom)
+	} else if sg.ScreenSetting.Irregular {
+		return errs.Fatalf("gen reel type %q has no generator for masked screens", sg.GenScreenSetting.GenReelTypeStr)
 	} else {
-		log.Fatal("GenReelType wrong")
+		return errs.Fatalf("gen reel type %q has no generator (register it with gen.Register)", sg.GenScreenSetting.GenReelTypeStr)
 	}
 	sg.Screen = make([]int16, sg.Cols*sg.Rows)
+	// 遮罩格子固定為 CellBlocked，生成時不會覆寫
+	for i := range sg.Screen {
+		if !sg.ScreenSetting.Open(i) {
+			sg.Screen[i] = spec.CellBlocked
+		}
+	}
 	return nil
 }
 
+// Core 回傳生成器使用的亂數核心（供自訂生成函式抽樣）
+func (sg *ScreenGenerator) Core() *core.Core {
+	return sg.core
+}
+
 // GenScreen 生成盤面熱路徑函數
 func (sg *ScreenGenerator) GenScreen() []int16 {
 	idx := sg.GenScreenSetting.ReelSetLUT.Pick(sg.core)
@@ -104,7 +149,7 @@ func (sg *ScreenGenerator) GenScreenByReelSetIdx(i int) []int16 {
 	return sg.genScreenFn(sg, sg.ReelSetGroup[i].Reels)
 }
 
-// 依照輪軸權重生成盤面(無視mask)
+// 依照輪軸權重生成盤面(完整矩陣)
 func genScreenByReelIdx(sg *ScreenGenerator, reels []spec.Reel) []int16 {
 	cols := sg.Cols
 	rows := sg.Rows
@@ -140,3 +185,63 @@ func genScreenBySymbolWeight(sg *ScreenGenerator, reels []spec.Reel) []int16 {
 	}
 	return sg.Screen
 }
+
+// 可變高度：每軸依高度權重表抽出本局高度，自 TopRows 列起連續取輪帶，其餘格子為 CellBlocked；
+// 有 TopReel 時再由左到右填入第 0 列的指定軸
+func genScreenByHeight(sg *ScreenGenerator, reels []spec.Reel) []int16 {
+	cols := sg.Cols
+	rows := sg.Rows
+	s := sg.Screen
+	gs := sg.GenScreenSetting
+	top := gs.TopRows()
+	for col := 0; col < cols; col++ {
+		ht := &gs.ReelHeights[col]
+		h := ht.Heights[ht.LUT.Pick(sg.core)]
+		sg.Heights[col] = h
+		reel := &reels[col]
+		id := reel.ReelLUT.Pick(sg.core)
+		length := reel.ReelLength
+		for r := 0; r < rows; r++ {
+			k := r - top
+			if k < 0 || k >= h {
+				s[r*cols+col] = spec.CellBlocked
+				continue
+			}
+			s[r*cols+col] = reel.ReelSymbols[(id+k)%length]
+		}
+	}
+	if tr := gs.TopReel; tr != nil {
+		id := tr.Reel.ReelLUT.Pick(sg.core)
+		for k, col := range tr.Columns {
+			s[col] = tr.Reel.ReelSymbols[(id+k)%tr.Reel.ReelLength]
+		}
+	}
+	return sg.Screen
+}
+
+// 不規則盤面：依輪軸權重生成，每軸只在可用格子上由上到下連續取輪帶
+func genMaskedScreenByReelIdx(sg *ScreenGenerator, reels []spec.Reel) []int16 {
+	s := sg.Screen
+	for col, cells := range sg.ColCells {
+		reel := &reels[col]
+		id := reel.ReelLUT.Pick(sg.core)
+		length := reel.ReelLength
+		for k, idx := range cells {
+			s[idx] = reel.ReelSymbols[(id+k)%length]
+		}
+	}
+	return sg.Screen
+}
+
+// 不規則盤面：依圖標權重生成，只寫入可用格子
+func genMaskedScreenBySymbolWeight(sg *ScreenGenerator, reels []spec.Reel) []int16 {
+	s := sg.Screen
+	for col, cells := range sg.ColCells {
+		reel := &reels[col]
+		for _, idx := range cells {
+			id := reel.ReelLUT.Pick(sg.core)
+			s[idx] = reel.ReelSymbols[id]
+		}
+	}
+	return sg.Screen
+}
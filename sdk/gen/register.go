@@ -0,0 +1,79 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"sync"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// CustomGenFn 自訂盤面生成函式。
+//
+//   - sg: 生成器（盤面緩衝 sg.Screen、亂數核心 sg.Core()、盤面尺寸等）
+//   - cfg: gen_screen_setting.params 解碼後的設定（每個生成器固定，勿修改）
+//   - reels: 本次選中的輪帶組（GenScreen 依權重抽選；GenScreenByReelSetIdx 由邏輯指定，可依遊戲狀態選擇）
+//
+// 應寫入並回傳 sg.Screen（熱路徑，請避免配置記憶體）；遮罩格請保留 spec.CellBlocked。
+type CustomGenFn[T any] func(sg *ScreenGenerator, cfg *T, reels []spec.Reel) []int16
+
+var (
+	customMu  sync.RWMutex
+	customMap = map[spec.GenReelType]func(cfg any) GenScreenFn{}
+)
+
+// Register 以名稱註冊自訂盤面生成器，設定檔以 gen_reel_type: <name> 使用。
+//
+// T 為 gen_screen_setting.params 的設定型別（非指標 struct），於設定解析時嚴格解碼並檢查
+// （*T 可實作 spec.GenParamsChecker 以檢查與盤面的相容性），錯誤會在註冊設定檔時回報，而非建立機台時才中止。
+// 應於程式初始化階段（init）呼叫；同名重複註冊僅允許相同的 T。
+//
+// Example:
+//
+//	type StackedParams struct {
+//		StackSize int `yaml:"stack_size"`
+//	}
+//
+//	gen.Register("GenStacked", func(sg *gen.ScreenGenerator, cfg *StackedParams, reels []spec.Reel) []int16 { ... })
+func Register[T any](name string, fn CustomGenFn[T]) error {
+	if fn == nil {
+		return errs.NewFatal("gen.Register: fn is required")
+	}
+	t, err := spec.RegisterGenReelType[T](name)
+	if err != nil {
+		return err
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	customMap[t] = func(cfg any) GenScreenFn {
+		c, _ := cfg.(*T)
+		if c == nil {
+			c = new(T)
+		}
+		// 型別斷言只在建立生成器時做一次，熱路徑直接使用 c
+		return func(sg *ScreenGenerator, reels []spec.Reel) []int16 {
+			return fn(sg, c, reels)
+		}
+	}
+	return nil
+}
+
+func customGen(t spec.GenReelType) (func(cfg any) GenScreenFn, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	bind, ok := customMap[t]
+	return bind, ok
+}
@@ -17,6 +17,7 @@ package gen
 import (
 	"testing"
 
+	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/core"
 	"github.com/zintix-labs/problab/spec"
 )
@@ -52,3 +53,177 @@ func TestGenScreenByReelSetIdx(t *testing.T) {
 		}
 	}
 }
+
+func TestGenMaskedScreen(t *testing.T) {
+	// 3-4-3 菱形（3 軸 x 4 列）
+	ss := &spec.ScreenSetting{
+		Columns: 3,
+		Rows:    4,
+		Mask: []uint8{
+			0, 1, 0,
+			1, 1, 1,
+			1, 1, 1,
+			1, 1, 1,
+		},
+	}
+	gs := &spec.GenScreenSetting{
+		GenReelTypeStr: "GenReelByReelIdx",
+		ReelSetGroup: []spec.ReelSet{
+			{Weight: 1, Reels: []spec.Reel{
+				{ReelSymbols: []int16{1, 2, 3, 4}},
+				{ReelSymbols: []int16{1, 2, 3, 4}},
+				{ReelSymbols: []int16{1, 2, 3, 4}},
+			}},
+		},
+	}
+	c := core.New(core.Default().New(1))
+	g := NewScreenGenerator(c, ss, gs)
+	for range 20 {
+		screen := g.GenScreen()
+		for i, v := range screen {
+			if (ss.Mask[i] == 0) != (v == spec.CellBlocked) {
+				t.Fatalf("blocked cells mismatch at %d: %v", i, screen)
+			}
+		}
+		// 每軸可用格子為輪帶上的連續位置
+		for _, cells := range g.ColCells {
+			for k := 1; k < len(cells); k++ {
+				if screen[cells[k]] != screen[cells[k-1]]%4+1 {
+					t.Fatalf("expected consecutive strip symbols, got %v", screen)
+				}
+			}
+		}
+	}
+}
+
+func TestGenScreenByHeight(t *testing.T) {
+	ss := testScreenSetting(3, 4)
+	gs := &spec.GenScreenSetting{
+		GenReelTypeStr: "GenReelByHeight",
+		ReelSetGroup: []spec.ReelSet{
+			{Weight: 1, Reels: []spec.Reel{
+				{ReelSymbols: []int16{1, 2, 3}},
+				{ReelSymbols: []int16{1, 2, 3}},
+				{ReelSymbols: []int16{1, 2, 3}},
+			}},
+		},
+		ReelHeights: []spec.HeightTable{
+			{Heights: []int{1, 2, 3}},
+			{Heights: []int{2, 3}},
+			{Heights: []int{3}},
+		},
+		TopReel: &spec.TopReel{Columns: []int{1, 2}, Reel: spec.Reel{ReelSymbols: []int16{7}}},
+	}
+	c := core.New(core.Default().New(1))
+	g := NewScreenGenerator(c, ss, gs)
+	seen := map[int]bool{}
+	for range 50 {
+		screen := g.GenScreen()
+		if screen[0] != spec.CellBlocked || screen[1] != 7 || screen[2] != 7 {
+			t.Fatalf("unexpected top row: %v", screen)
+		}
+		for col, h := range g.Heights {
+			seen[col*10+h] = true
+			for r := 1; r < ss.Rows; r++ {
+				if open := screen[r*ss.Columns+col] != spec.CellBlocked; open != (r <= h) {
+					t.Fatalf("col %d height %d mismatch at row %d: %v", col, h, r, screen)
+				}
+			}
+		}
+	}
+	for _, k := range []int{1, 2, 3, 12, 13, 23} {
+		if !seen[k] {
+			t.Fatalf("height %d of col %d never drawn", k%10, k/10)
+		}
+	}
+}
+
+type testFillParams struct {
+	Symbol int16 `yaml:"symbol"`
+}
+
+func (p *testFillParams) CheckScreen(ss *spec.ScreenSetting) error {
+	if p.Symbol < 0 {
+		return errs.NewFatal("symbol must be >= 0")
+	}
+	return nil
+}
+
+func TestRegisterCustomGenerator(t *testing.T) {
+	fill := func(sg *ScreenGenerator, cfg *testFillParams, reels []spec.Reel) []int16 {
+		for i := range sg.Screen {
+			if sg.Screen[i] != spec.CellBlocked {
+				sg.Screen[i] = cfg.Symbol + int16(len(reels))
+			}
+		}
+		return sg.Screen
+	}
+	if err := Register("GenTestFill", fill); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if err := Register("GenTestFill", fill); err != nil {
+		t.Fatalf("re-register with same params type must succeed: %v", err)
+	}
+	if err := Register("GenTestFill", func(*ScreenGenerator, *struct{ X int }, []spec.Reel) []int16 { return nil }); err == nil {
+		t.Fatalf("expected params type mismatch error")
+	}
+	if err := Register("GenReelByReelIdx", fill); err == nil {
+		t.Fatalf("expected builtin name conflict error")
+	}
+
+	if _, err := parseGenType("GenTestFill", "{symbl: 1}"); err == nil {
+		t.Fatalf("expected unknown params field error at parse time")
+	}
+	if _, err := parseGenType("GenTestFill", "{symbol: -1}"); err == nil {
+		t.Fatalf("expected CheckScreen error at parse time")
+	}
+	gs, err := parseGenType("GenTestFill", "{symbol: 1}")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m := &gs.GameModeSettings[0]
+	g := NewScreenGenerator(core.New(core.Default().New(1)), &m.ScreenSetting, &m.GenScreenSetting)
+	for _, v := range g.GenScreen() {
+		if v != 3 {
+			t.Fatalf("expected custom generator output, got %v", g.Screen)
+		}
+	}
+
+	// 只在 spec 註冊（沒有生成函式）：解析可過，建立生成器時回傳錯誤而非中止程式
+	if _, err := spec.RegisterGenReelType[testFillParams]("GenTestSpecOnly"); err != nil {
+		t.Fatalf("register type failed: %v", err)
+	}
+	gs, err = parseGenType("GenTestSpecOnly", "{symbol: 1}")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	m = &gs.GameModeSettings[0]
+	if _, err := NewScreenGeneratorE(core.New(core.Default().New(1)), &m.ScreenSetting, &m.GenScreenSetting); err == nil {
+		t.Fatalf("expected missing generator error")
+	}
+}
+
+func parseGenType(name, params string) (*spec.GameSetting, error) {
+	y := `game_name: custom
+game_id: 1
+logic_key: normal
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - screen_setting: {columns: 2, rows: 2}
+    gen_screen_setting:
+      gen_reel_type: ` + name + `
+      params: ` + params + `
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0]
+            - symbols: [0]
+    symbol_setting:
+      symbol_used: [H1, H2, H3, H4]
+      pay_table: [[0, 1], [0, 1], [0, 1], [0, 1]]
+    hit_setting:
+      bet_type: way_ltr
+`
+	return spec.GetGameSettingByYAML([]byte(y))
+}
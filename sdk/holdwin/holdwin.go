@@ -0,0 +1,259 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package holdwin 提供 hold-and-win / cash-collect respin 特色的狀態機。
+//
+// 規則（設定見 spec.HoldAndWinSetting）：
+//   - 觸發盤面上的 coin / collector 全部鎖定，coin 依 coin_values 抽出數值
+//   - 每次 respin 只對未鎖定的格子逐格抽樣（使用該模式 ScreenGenerator 的輪帶，視為符號權重）
+//   - 有新的 coin / collector 落下時 respin 次數重置，否則遞減；次數歸零或全盤鎖定時結束
+//   - collector 落下時收集盤面上所有 coin 的數值作為自身數值
+//   - 結束時贏分 = 所有鎖定格數值總和（全盤鎖定再加 full_screen_prize），皆為押注倍數
+//
+// 使用方式（例如 free 模式設定了 hold_and_win）：
+//
+//	// build 階段
+//	hw, err := holdwin.New(mode.ScreenGenerator, mode.GameModeSetting, gh.IsSim)
+//	dto.RegisterExtendRender[holdwin.Extend](logicKey)
+//
+//	// 觸發後
+//	hw.Run(betMult, triggerScreen, mode.GameModeResult)
+//	return mode.YieldResult()
+//
+// 每次 respin 記錄為一個 Round（Act: "respin"，帶盤面與 Extend），最後的派彩記錄為 Act "collect"。
+package holdwin
+
+import (
+	"math/bits"
+
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/gen"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// Act 類型
+const (
+	ActStart   = "hold_and_win" // 觸發盤面鎖定
+	ActRespin  = "respin"       // 每次 respin
+	ActCollect = "collect"      // 結束派彩
+)
+
+// Extend hold-and-win 的 ExtendResult，隨每個 Act 輸出
+type Extend struct {
+	Respins    int     `json:"respins"`               // 剩餘 respin 次數
+	Values     []int   `json:"values"`                // 每格數值（押注倍數，0 表示未鎖定）
+	Landed     []int16 `json:"landed,omitempty"`      // 本次新鎖定的格子
+	FullScreen bool    `json:"full_screen,omitempty"` // 是否全盤鎖定
+	isSim      bool
+}
+
+// Reset 清空到初始狀態（保留容量）
+func (e *Extend) Reset() {
+	e.Respins = 0
+	clear(e.Values)
+	e.Landed = e.Landed[:0]
+	e.FullScreen = false
+}
+
+// Snapshot 建立深拷貝；模擬模式回傳 nil
+func (e *Extend) Snapshot() any {
+	if e.isSim {
+		return nil
+	}
+	return &Extend{
+		Respins:    e.Respins,
+		Values:     append([]int(nil), e.Values...),
+		Landed:     append([]int16(nil), e.Landed...),
+		FullScreen: e.FullScreen,
+	}
+}
+
+// HoldAndWin hold-and-win 狀態機（非並行安全，與所屬 GameMode 綁定）
+type HoldAndWin struct {
+	Setting *spec.HoldAndWinSetting
+	Ext     *Extend
+
+	sg     *gen.ScreenGenerator
+	core   *core.Core
+	screen []int16 // respin 盤面（鎖定格保留符號）
+	locked []bool
+	open   []int   // 可用格子（排除遮罩格）
+	hits   []int16 // 派彩時的鎖定格
+	nLock  int
+}
+
+// New 依模式設定建立 HoldAndWin；模式未設定 hold_and_win 時回傳錯誤
+func New(sg *gen.ScreenGenerator, gms *spec.GameModeSetting, isSim bool) (*HoldAndWin, error) {
+	if gms.HoldAndWin == nil {
+		return nil, errs.NewFatal("holdwin: game mode has no hold_and_win setting")
+	}
+	size := gms.ScreenSetting.ScreenSize
+	hw := &HoldAndWin{
+		Setting: gms.HoldAndWin,
+		Ext: &Extend{
+			Values: make([]int, size),
+			Landed: make([]int16, 0, size),
+			isSim:  isSim,
+		},
+		sg:     sg,
+		core:   sg.Core(),
+		screen: make([]int16, size),
+		locked: make([]bool, size),
+		open:   make([]int, 0, size),
+		hits:   make([]int16, 0, size),
+	}
+	for i := 0; i < size; i++ {
+		if gms.ScreenSetting.Open(i) {
+			hw.open = append(hw.open, i)
+		}
+	}
+	return hw, nil
+}
+
+// Run 從觸發盤面開始執行完整 hold-and-win，逐次記錄到 gmr 並回傳贏分
+//
+//   - betMult: 押注倍數
+//   - screen: 觸發盤面（大小須與本模式相同，不會被修改）
+//   - gmr: 本模式的 GameModeResult
+func (hw *HoldAndWin) Run(betMult int, screen []int16, gmr *buf.GameModeResult) int {
+	hw.reset(screen)
+
+	// 1. 鎖定觸發盤面
+	hw.lockLanded()
+	hw.Ext.Respins = hw.Setting.Respins
+	gmr.AddAct(buf.FinishRound, ActStart, hw.screen, hw.Ext)
+
+	// 2. respin 直到次數歸零或全盤鎖定
+	for hw.Ext.Respins > 0 && !hw.Ext.FullScreen {
+		hw.respin()
+		if hw.lockLanded() > 0 {
+			hw.Ext.Respins = hw.Setting.Respins
+		} else {
+			hw.Ext.Respins--
+		}
+		gmr.AddAct(buf.FinishRound, ActRespin, hw.screen, hw.Ext)
+	}
+
+	// 3. 派彩
+	return hw.collect(betMult, gmr)
+}
+
+// Locked 回傳目前鎖定格數
+func (hw *HoldAndWin) Locked() int {
+	return hw.nLock
+}
+
+// ============================================================
+// ** 以下內部方法 **
+// ============================================================
+
+func (hw *HoldAndWin) reset(screen []int16) {
+	if len(screen) != len(hw.screen) {
+		panic("holdwin: screen size not match")
+	}
+	hw.Ext.Reset()
+	copy(hw.screen, screen)
+	clear(hw.locked)
+	hw.nLock = 0
+}
+
+// respin 對未鎖定的可用格子逐格抽樣
+func (hw *HoldAndWin) respin() {
+	sg := hw.sg
+	reels := sg.ReelSetGroup[sg.GenScreenSetting.ReelSetLUT.Pick(hw.core)].Reels
+	for _, idx := range hw.open {
+		if hw.locked[idx] {
+			continue
+		}
+		reel := &reels[idx%sg.Cols]
+		hw.screen[idx] = reel.ReelSymbols[reel.ReelLUT.Pick(hw.core)]
+	}
+}
+
+// lockLanded 鎖定新落下的 coin / collector，回傳新鎖定數
+func (hw *HoldAndWin) lockLanded() int {
+	cfg := hw.Setting
+	ext := hw.Ext
+	ext.Landed = ext.Landed[:0]
+	collectors := 0
+	for _, idx := range hw.open {
+		s := hw.screen[idx]
+		if hw.locked[idx] || uint(s) >= 64 {
+			continue
+		}
+		bit := uint64(1) << uint(s)
+		switch {
+		case cfg.CoinMask&bit != 0:
+			ext.Values[idx] = cfg.CoinValues[cfg.CoinValueLUT.Pick(hw.core)]
+		case cfg.CollectorMask&bit != 0:
+			collectors++
+		default:
+			continue
+		}
+		hw.locked[idx] = true
+		hw.nLock++
+		ext.Landed = append(ext.Landed, int16(idx))
+	}
+	// collector 在同批 coin 鎖定後才收集
+	if collectors > 0 {
+		sum := 0
+		for _, idx := range hw.open {
+			if hw.locked[idx] && cfg.CoinMask&(1<<uint(hw.screen[idx])) != 0 {
+				sum += ext.Values[idx]
+			}
+		}
+		for _, idx := range ext.Landed {
+			if cfg.CollectorMask&(1<<uint(hw.screen[idx])) != 0 {
+				ext.Values[idx] = sum
+			}
+		}
+	}
+	ext.FullScreen = hw.nLock == len(hw.open)
+	return len(ext.Landed)
+}
+
+// coinSym 派彩細項使用的符號 ID（第一個 coin 符號）
+func (hw *HoldAndWin) coinSym() int16 {
+	return int16(bits.TrailingZeros64(hw.Setting.CoinMask))
+}
+
+// collect 記錄派彩細項並提交最後一個 Act
+func (hw *HoldAndWin) collect(betMult int, gmr *buf.GameModeResult) int {
+	cfg := hw.Setting
+	ext := hw.Ext
+	ext.Landed = ext.Landed[:0]
+	hw.hits = hw.hits[:0]
+	sum := 0
+	for _, idx := range hw.open {
+		if hw.locked[idx] {
+			sum += ext.Values[idx]
+			hw.hits = append(hw.hits, int16(idx))
+		}
+	}
+	win := 0
+	if sum > 0 {
+		w := sum * betMult
+		gmr.RecordDetail(w, hw.coinSym(), 0, len(hw.hits), 0, 0, hw.hits)
+		win += w
+	}
+	if ext.FullScreen && cfg.FullScreenPrize > 0 {
+		w := cfg.FullScreenPrize * betMult
+		gmr.RecordDetail(w, hw.coinSym(), 0, len(hw.hits), 0, 0, hw.hits)
+		win += w
+	}
+	gmr.AddAct(buf.FinishRound, ActCollect, nil, ext)
+	return win
+}
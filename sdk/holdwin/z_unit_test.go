@@ -0,0 +1,99 @@
+package holdwin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/gen"
+	"github.com/zintix-labs/problab/spec"
+)
+
+const holdYAML = `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 100000
+game_mode_settings:
+  - screen_setting: {columns: 2, rows: 2}
+    gen_screen_setting:
+      gen_reel_type: GenReelBySymbolWeight
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [REEL]
+            - symbols: [REEL]
+    symbol_setting:
+      symbol_used: [L1, C1, C2]
+      pay_table: [[0, 0, 0, 0], [0, 0, 0, 0], [0, 0, 0, 0]]
+    hit_setting:
+      bet_type: count
+    hold_and_win:
+      respins: 3
+      coins: [C1]
+      collectors: [C2]
+      coin_values: [5]
+      full_screen_prize: 100
+`
+
+func newHoldAndWin(t *testing.T, reel string) (*HoldAndWin, *buf.GameModeResult) {
+	t.Helper()
+	gs, err := spec.GetGameSettingByYAML([]byte(strings.ReplaceAll(holdYAML, "REEL", reel)))
+	if err != nil {
+		t.Fatalf("parse setting: %v", err)
+	}
+	gms := &gs.GameModeSettings[0]
+	sg := gen.NewScreenGenerator(core.New(core.Default().New(1)), &gms.ScreenSetting, &gms.GenScreenSetting)
+	hw, err := New(sg, gms, false)
+	if err != nil {
+		t.Fatalf("new hold and win: %v", err)
+	}
+	return hw, buf.NewGameModeResult(0, gms, 16, 16)
+}
+
+func TestHoldAndWinRespinsExpire(t *testing.T) {
+	hw, gmr := newHoldAndWin(t, "0")
+	if win := hw.Run(2, []int16{1, 0, 0, 0}, gmr); win != 10 {
+		t.Fatalf("expected win 5*2=10, got %d", win)
+	}
+	// start + 3 次 respin + collect
+	acts := gmr.ActResults
+	if len(acts) != 5 || acts[0].ActType != ActStart || acts[3].ActType != ActRespin || acts[4].ActType != ActCollect {
+		t.Fatalf("unexpected acts: %+v", acts)
+	}
+	if ext := acts[3].ExtendResult.(*Extend); ext.Respins != 0 || ext.Values[0] != 5 {
+		t.Fatalf("unexpected last respin extend: %+v", ext)
+	}
+	if gmr.TotalWin != 10 || !acts[4].IsRoundEnd {
+		t.Fatalf("expected collect act to carry the win, got total %d", gmr.TotalWin)
+	}
+}
+
+func TestHoldAndWinFullScreenAndCollector(t *testing.T) {
+	// 輪帶全為 coin：一次 respin 即全盤鎖定
+	hw, gmr := newHoldAndWin(t, "1")
+	if win := hw.Run(1, []int16{2, 0, 1, 0}, gmr); win != 3*5+5+100 {
+		t.Fatalf("expected coins + collector + full screen prize, got %d", win)
+	}
+	acts := gmr.ActResults
+	if len(acts) != 3 {
+		t.Fatalf("expected start, one respin and collect, got %d acts", len(acts))
+	}
+	start := acts[0].ExtendResult.(*Extend)
+	if start.Values[0] != 5 || start.Values[2] != 5 || len(start.Landed) != 2 {
+		t.Fatalf("collector should collect the coins landed with it: %+v", start)
+	}
+	if ext := acts[1].ExtendResult.(*Extend); !ext.FullScreen || ext.Respins != 3 || len(ext.Landed) != 2 {
+		t.Fatalf("unexpected respin extend: %+v", ext)
+	}
+	if hw.Locked() != 4 {
+		t.Fatalf("expected 4 locked cells, got %d", hw.Locked())
+	}
+
+	// 重跑不殘留狀態
+	gmr.Reset()
+	if win := hw.Run(1, []int16{1, 0, 0, 0}, gmr); win != 4*5+100 {
+		t.Fatalf("unexpected win after rerun: %d", win)
+	}
+}
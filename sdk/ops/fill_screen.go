@@ -34,8 +34,11 @@ func FillScreen(screen []int16, reels *spec.ReelSet, fillIdxBuf []int, reelPosId
 		strip := reels.Reels[c].ReelSymbols
 		stripLen := len(strip)
 
-		// 從起始點往上補到頂 (0)
+		// 從起始點往上補到頂 (0)，略過遮罩格子
 		for w := startRowPtr; w >= 0; w -= cols {
+			if screen[w] == spec.CellBlocked {
+				continue
+			}
 			currentReelPos-- // 先--
 			// 處理輪帶回捲
 			if currentReelPos < 0 {
@@ -49,7 +52,7 @@ func FillScreen(screen []int16, reels *spec.ReelSet, fillIdxBuf []int, reelPosId
 	}
 }
 
-// FillScreenByHole 穿透補盤：掃描全盤，見縫插針
+// FillScreenByHole 穿透補盤：掃描全盤，見縫插針（只補 0，遮罩格子不受影響）
 //
 // 相較FillScreen 少了fillStartIdx，直接掃描全盤補足，性能差一點，但更為萬用
 //   - screen: 盤面 (原地修改)
@@ -14,23 +14,35 @@
 
 package ops
 
+import "github.com/zintix-labs/problab/spec"
+
 // Gravity 執行標準的單格圖標下落邏輯 (Column-wise compact)
 //
 //   - screen: 盤面數據 (將被原地修改)
 //   - cols, rows: 盤面維度
 //   - fillIdxBuf: (選用) 用於回傳每列需要補圖的位置，若為 nil 則內部不紀錄
+//
+// 遮罩格子（spec.CellBlocked）視為不存在：圖標穿過它們往下掉，補 0 時也不會覆寫。
 func Gravity(screen []int16, cols int, rows int, fillIdxBuf []int) {
 	// 掉落 (原地壓縮演算法)
 	for c := 0; c < cols; c++ {
 		wp := (rows-1)*cols + c // Write Pointer (寫入位置，從底開始)
+		for wp >= 0 && screen[wp] == spec.CellBlocked {
+			wp -= cols
+		}
 
 		// 自底向上掃描
 		for r := rows - 1; r >= 0; r-- {
 			rp := r*cols + c // Read Pointer
-			if screen[rp] != 0 {
-				if rp != wp {
-					screen[wp] = screen[rp]
-				}
+			s := screen[rp]
+			if s == 0 || s == spec.CellBlocked {
+				continue
+			}
+			if rp != wp {
+				screen[wp] = s
+			}
+			wp -= cols
+			for wp >= 0 && screen[wp] == spec.CellBlocked {
 				wp -= cols
 			}
 		}
@@ -42,7 +54,9 @@ func Gravity(screen []int16, cols int, rows int, fillIdxBuf []int) {
 
 		// 4. 上方剩餘空間補 0
 		for w := wp; w >= 0; w -= cols {
-			screen[w] = 0
+			if screen[w] != spec.CellBlocked {
+				screen[w] = 0
+			}
 		}
 	}
 }
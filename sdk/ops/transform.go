@@ -0,0 +1,149 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ops
+
+import (
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/sampler"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// 符號轉換特色操作（mystery / upgrade / split）
+//
+// 與 wild 操作相同：原地修改盤面，改動的格子 append 到 changed 後回傳，可直接記錄為一個 Act。
+// 隨機部分由 WeightedTable 驅動，通常宣告在遊戲的 Fixed 結構中，DecodeFixed 後呼叫 Init：
+//
+//	type fixed struct {
+//		Mystery ops.WeightedTable[int16] `yaml:"mystery"` // values: 揭示符號, weights: 權重
+//		Split   ops.WeightedTable[int]   `yaml:"split"`   // values: 分裂格數
+//	}
+//	if err := f.Mystery.Init(); err != nil { ... }
+
+// WeightedTable 權重表：依 Weights 抽出 Values 之一
+type WeightedTable[T sampler.Integers] struct {
+	Values  []T         `yaml:"values"  json:"values"`
+	Weights []int       `yaml:"weights" json:"weights"`
+	LUT     sampler.LUT `yaml:"-"       json:"-"`
+}
+
+// Init 檢查權重表並建立 LUT（權重須非負且不可全為 0）
+func (wt *WeightedTable[T]) Init() error {
+	if len(wt.Values) == 0 {
+		return errs.NewFatal("weighted table: values is empty")
+	}
+	if len(wt.Values) != len(wt.Weights) {
+		return errs.NewFatal("weighted table: len(values) != len(weights)")
+	}
+	sum := 0
+	for _, w := range wt.Weights {
+		if w < 0 {
+			return errs.NewFatal("weighted table: negative weight")
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return errs.NewFatal("weighted table: all weights are zero")
+	}
+	wt.LUT = sampler.BuildLUT(wt.Weights)
+	return nil
+}
+
+// Pick 依權重抽出一個值
+func (wt *WeightedTable[T]) Pick(c *core.Core) T {
+	return wt.Values[wt.LUT.Pick(c)]
+}
+
+// RevealMystery 盤面上所有 mystery 符號揭示為同一個隨機符號
+//
+//   - c: 亂數核心
+//   - screen: 盤面 (原地修改)
+//   - mystery: mystery 符號
+//   - table: 揭示符號權重表
+//   - changed: 改動格子輸出緩衝 (append 後回傳)
+//
+// 回傳揭示的符號；盤面沒有 mystery 時不抽亂數，回傳 -1。
+func RevealMystery(c *core.Core, screen []int16, mystery int16, table *WeightedTable[int16], changed []int16) (int16, []int16) {
+	sym := int16(-1)
+	for i, s := range screen {
+		if s != mystery {
+			continue
+		}
+		if sym < 0 {
+			sym = table.Pick(c)
+		}
+		screen[i] = sym
+		changed = append(changed, int16(i))
+	}
+	return sym, changed
+}
+
+// Upgrade 將 fromMask 內的符號沿 ladder 升一階（ladder[i] → ladder[i+1]）
+//
+//   - screen: 盤面 (原地修改)
+//   - ladder: 升級階梯，例如 [L4, L3, L2, L1, H4]
+//   - fromMask: 要升級的符號遮罩（可用 WeightedTable 抽出後組成）；ladder 最後一階不會再升級
+//   - changed: 改動格子輸出緩衝 (append 後回傳)
+func Upgrade(screen []int16, ladder []int16, fromMask uint64, changed []int16) []int16 {
+	last := len(ladder) - 1
+	for i, s := range screen {
+		if !inMask(fromMask, s) {
+			continue
+		}
+		for k := 0; k < last; k++ {
+			if ladder[k] == s {
+				screen[i] = ladder[k+1]
+				changed = append(changed, int16(i))
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// CellCounter 記錄格子計數倍數（calc.ScreenCalculator 實作）
+type CellCounter interface {
+	SetCellCount(idx int, n int)
+}
+
+// SplitSymbols 在符合條件的格子中隨機挑選 n 格分裂為 count 顆（例如 2 表示計數加倍）
+//
+// 盤面符號不變，分裂結果寫入 cc（算分器）：Count 算分時該格以 count 顆計算，
+// Way 則只放大組合數與乘數加權（Way 贏分不乘組合數，見 calc.SetCellCount）。
+//
+//   - c: 亂數核心
+//   - screen: 盤面 (只讀)
+//   - n: 分裂格數（可由 WeightedTable 抽出）
+//   - count: 分裂後顆數
+//   - eligibleMask: 可分裂的符號遮罩
+//   - scratch: 候選格緩衝，長度至少為盤面大小（避免配置）
+//   - cc: 接收分裂結果的算分器
+//   - changed: 分裂格子輸出緩衝 (append 後回傳，依抽中順序)
+func SplitSymbols(c *core.Core, screen []int16, n int, count int, eligibleMask uint64, scratch []int, cc CellCounter, changed []int16) []int16 {
+	cand := scratch[:0]
+	for i, s := range screen {
+		if s != spec.CellBlocked && inMask(eligibleMask, s) {
+			cand = append(cand, i)
+		}
+	}
+	n = min(n, len(cand))
+	for i := 0; i < n; i++ {
+		j := i + c.IntN(len(cand)-i)
+		cand[i], cand[j] = cand[j], cand[i]
+		cc.SetCellCount(cand[i], count)
+		changed = append(changed, int16(cand[i]))
+	}
+	return changed
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ops
+
+import (
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// Wild 特色操作
+//
+// 所有操作皆原地修改盤面，並把「被改動的格子 idx」append 到呼叫端提供的 changed 後回傳
+// （容量足夠時零配置）。符號集合以位元遮罩表示（bit i 對應符號 i，同 calc.SymbolMask），
+// 遮罩格（spec.CellBlocked）一律不受影響。
+//
+// 改動的格子可直接記錄為一個 Act，例如：
+//
+//	changed = ops.ExpandWilds(screen, cols, rows, wildMask, changed[:0])
+//	gmr.RecordDetail(0, wild, 0, len(changed), 0, 0, changed)
+//	gmr.AddAct(buf.FinishAct, "expand_wild", screen, nil)
+
+func inMask(mask uint64, s int16) bool {
+	return uint(s) < 64 && mask&(1<<uint(s)) != 0
+}
+
+// ExpandWilds 擴展 wild：任一軸出現 wild 時，整軸換成該軸最上方的 wild
+//
+//   - screen: 盤面 (原地修改)
+//   - cols, rows: 盤面維度
+//   - wildMask: 會擴展的 wild 符號遮罩
+//   - changed: 改動格子輸出緩衝 (append 後回傳)
+func ExpandWilds(screen []int16, cols int, rows int, wildMask uint64, changed []int16) []int16 {
+	for c := 0; c < cols; c++ {
+		w := int16(-1)
+		for r := 0; r < rows; r++ {
+			if s := screen[r*cols+c]; inMask(wildMask, s) {
+				w = s
+				break
+			}
+		}
+		if w < 0 {
+			continue
+		}
+		for r := 0; r < rows; r++ {
+			idx := r*cols + c
+			if s := screen[idx]; s != spec.CellBlocked && s != w {
+				screen[idx] = w
+				changed = append(changed, int16(idx))
+			}
+		}
+	}
+	return changed
+}
+
+// RandomWilds 在符合條件的格子中隨機放置 n 個 wild（不足 n 格時全部放置）
+//
+//   - c: 亂數核心
+//   - screen: 盤面 (原地修改)
+//   - n: 放置數量
+//   - wild: 放置的 wild 符號
+//   - skipMask: 不可被覆蓋的符號遮罩（通常為 wild 與 scatter）
+//   - scratch: 候選格緩衝，長度至少為盤面大小（避免配置）
+//   - changed: 改動格子輸出緩衝 (append 後回傳，依抽中順序)
+func RandomWilds(c *core.Core, screen []int16, n int, wild int16, skipMask uint64, scratch []int, changed []int16) []int16 {
+	cand := scratch[:0]
+	for i, s := range screen {
+		if s == spec.CellBlocked || inMask(skipMask, s) {
+			continue
+		}
+		cand = append(cand, i)
+	}
+	// 部分 Fisher-Yates：只洗前 n 個
+	n = min(n, len(cand))
+	for i := 0; i < n; i++ {
+		j := i + c.IntN(len(cand)-i)
+		cand[i], cand[j] = cand[j], cand[i]
+		screen[cand[i]] = wild
+		changed = append(changed, int16(cand[i]))
+	}
+	return changed
+}
+
+// HeldWilds 跨局保留的 wild（sticky / walking wild）
+//
+// 典型流程（每個 free round）：
+//
+//	screen := gen.GenScreen()
+//	changed = held.Apply(screen, changed[:0]) // 放回保留的 wild
+//	held.Collect(screen, wildMask)            // 記住本局新出現的 wild
+//	...算分...
+//	held.Shift(cols, rows, -1, 0)             // walking wild：下一局往左走一軸（sticky 則不呼叫）
+type HeldWilds struct {
+	syms  []int16 // 每格保留的 wild 符號，spec.CellBlocked 表示無
+	cells []int16 // 有保留 wild 的格子（保持加入順序）
+	tmp   []int16 // Shift 用暫存（idx, wild 成對）
+}
+
+// NewHeldWilds 依盤面大小建立 HeldWilds（唯一的配置點）
+func NewHeldWilds(screenSize int) *HeldWilds {
+	h := &HeldWilds{
+		syms:  make([]int16, screenSize),
+		cells: make([]int16, 0, screenSize),
+		tmp:   make([]int16, 0, 2*screenSize),
+	}
+	h.Reset()
+	return h
+}
+
+// Reset 清除所有保留的 wild（例如 free game 結束時）
+func (h *HeldWilds) Reset() {
+	for i := range h.syms {
+		h.syms[i] = spec.CellBlocked
+	}
+	h.cells = h.cells[:0]
+}
+
+// Len 回傳目前保留的 wild 數量
+func (h *HeldWilds) Len() int {
+	return len(h.cells)
+}
+
+// Cells 回傳目前保留 wild 的格子（請勿修改返回值）
+func (h *HeldWilds) Cells() []int16 {
+	return h.cells
+}
+
+// Hold 保留指定格子的 wild
+func (h *HeldWilds) Hold(idx int, wild int16) {
+	if h.syms[idx] == spec.CellBlocked {
+		h.cells = append(h.cells, int16(idx))
+	}
+	h.syms[idx] = wild
+}
+
+// Collect 將盤面上所有 wild 加入保留
+func (h *HeldWilds) Collect(screen []int16, wildMask uint64) {
+	for i, s := range screen {
+		if inMask(wildMask, s) {
+			h.Hold(i, s)
+		}
+	}
+}
+
+// Apply 將保留的 wild 放回盤面（覆蓋原符號，遮罩格除外）
+//
+//   - changed: 改動格子輸出緩衝 (append 後回傳；原本就是同一 wild 的格子不列入)
+func (h *HeldWilds) Apply(screen []int16, changed []int16) []int16 {
+	for _, idx := range h.cells {
+		s := screen[idx]
+		if s == spec.CellBlocked || s == h.syms[idx] {
+			continue
+		}
+		screen[idx] = h.syms[idx]
+		changed = append(changed, idx)
+	}
+	return changed
+}
+
+// Shift 將保留的 wild 平移 (dc, dr)，移出盤面者捨棄（walking wild）
+func (h *HeldWilds) Shift(cols int, rows int, dc int, dr int) {
+	h.tmp = h.tmp[:0]
+	for _, idx := range h.cells {
+		h.tmp = append(h.tmp, idx, h.syms[idx])
+		h.syms[idx] = spec.CellBlocked
+	}
+	h.cells = h.cells[:0]
+	for k := 0; k < len(h.tmp); k += 2 {
+		idx, w := int(h.tmp[k]), h.tmp[k+1]
+		c, r := idx%cols+dc, idx/cols+dr
+		if c < 0 || c >= cols || r < 0 || r >= rows {
+			continue
+		}
+		h.Hold(r*cols+c, w)
+	}
+}
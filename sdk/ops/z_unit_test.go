@@ -15,8 +15,10 @@
 package ops
 
 import (
+	"slices"
 	"testing"
 
+	"github.com/zintix-labs/problab/sdk/core"
 	"github.com/zintix-labs/problab/spec"
 )
 
@@ -75,3 +77,165 @@ func TestFillScreenByHole(t *testing.T) {
 		}
 	}
 }
+
+func TestGravityBlockedCells(t *testing.T) {
+	b := spec.CellBlocked
+	cols, rows := 2, 4
+	screen := []int16{
+		b, 1,
+		2, 0,
+		b, 3,
+		0, b,
+	}
+	fillIdx := make([]int, cols)
+	Gravity(screen, cols, rows, fillIdx)
+	want := []int16{
+		b, 0,
+		0, 1,
+		b, 3,
+		2, b,
+	}
+	if !slices.Equal(screen, want) {
+		t.Fatalf("unexpected gravity result: %v", screen)
+	}
+	if fillIdx[0] != 2 || fillIdx[1] != 1 {
+		t.Fatalf("unexpected fill idx: %v", fillIdx)
+	}
+
+	reels := &spec.ReelSet{Reels: []spec.Reel{
+		{ReelSymbols: []int16{7, 8, 9}},
+		{ReelSymbols: []int16{7, 8, 9}},
+	}}
+	FillScreen(screen, reels, fillIdx, []int{0, 0}, cols)
+	for i, v := range screen {
+		if v == 0 || (want[i] == b) != (v == b) {
+			t.Fatalf("blocked cells must stay and holes must be filled, got %v", screen)
+		}
+	}
+}
+
+func TestWildOps(t *testing.T) {
+	const w = 9
+	wildMask := uint64(1) << w
+	b := spec.CellBlocked
+	cols, rows := 3, 3
+	changed := make([]int16, 0, cols*rows)
+
+	// 擴展：第 1 軸有 wild，整軸（遮罩格除外）換成 wild
+	screen := []int16{
+		1, 2, 3,
+		1, w, 3,
+		1, b, 3,
+	}
+	changed = ExpandWilds(screen, cols, rows, wildMask, changed[:0])
+	if !slices.Equal(changed, []int16{1}) || screen[1] != w || screen[7] != b {
+		t.Fatalf("unexpected expand result: screen=%v changed=%v", screen, changed)
+	}
+
+	// 隨機：只放在非遮罩且非 skip 的格子
+	c := core.New(core.Default().New(1))
+	scratch := make([]int, cols*rows)
+	screen = []int16{1, 1, 1, b, w, 1, 1, 1, 1}
+	changed = RandomWilds(c, screen, 3, w, wildMask, scratch, changed[:0])
+	if len(changed) != 3 {
+		t.Fatalf("expected 3 wilds placed, got %v", changed)
+	}
+	for _, idx := range changed {
+		if idx == 3 || idx == 4 || screen[idx] != w {
+			t.Fatalf("wild placed on ineligible cell %d: %v", idx, screen)
+		}
+	}
+	screen = []int16{1, b, w}
+	if changed = RandomWilds(c, screen, 5, w, wildMask, scratch, changed[:0]); len(changed) != 1 || changed[0] != 0 {
+		t.Fatalf("expected only one eligible cell, got %v", changed)
+	}
+
+	// sticky：保留的 wild 下一局放回
+	held := NewHeldWilds(cols * rows)
+	held.Collect([]int16{1, w, 1, 1, 1, 1, 1, 1, w}, wildMask)
+	screen = []int16{2, 2, 2, 2, 2, 2, 2, 2, w}
+	if changed = held.Apply(screen, changed[:0]); !slices.Equal(changed, []int16{1}) || screen[1] != w {
+		t.Fatalf("unexpected sticky apply: screen=%v changed=%v", screen, changed)
+	}
+
+	// walking：往左一軸，移出盤面者捨棄
+	held.Shift(cols, rows, -1, 0)
+	if !slices.Equal(held.Cells(), []int16{0, 7}) {
+		t.Fatalf("unexpected walking cells: %v", held.Cells())
+	}
+	held.Shift(cols, rows, -1, 0)
+	if !slices.Equal(held.Cells(), []int16{6}) {
+		t.Fatalf("unexpected walking cells after second step: %v", held.Cells())
+	}
+	held.Reset()
+	if held.Len() != 0 {
+		t.Fatalf("expected reset")
+	}
+
+	allocs := testing.AllocsPerRun(20, func() {
+		screen := []int16{1, w, 1, 1, 1, 1, 1, 1, 1}
+		changed = ExpandWilds(screen, cols, rows, wildMask, changed[:0])
+		changed = RandomWilds(c, screen, 2, w, wildMask, scratch, changed[:0])
+		held.Collect(screen, wildMask)
+		held.Shift(cols, rows, 1, 0)
+		changed = held.Apply(screen, changed[:0])
+		held.Reset()
+	})
+	if allocs != 0 {
+		t.Fatalf("expected zero allocs, got %v", allocs)
+	}
+}
+
+type cellCounts []int
+
+func (cc cellCounts) SetCellCount(idx int, n int) { cc[idx] = n }
+
+func TestSymbolTransforms(t *testing.T) {
+	c := core.New(core.Default().New(1))
+	const mystery = 7
+
+	table := WeightedTable[int16]{Values: []int16{2, 3}, Weights: []int{0, 1}}
+	if err := table.Init(); err != nil {
+		t.Fatalf("init weighted table: %v", err)
+	}
+	for _, bad := range []WeightedTable[int16]{
+		{},
+		{Values: []int16{1}, Weights: []int{1, 2}},
+		{Values: []int16{1}, Weights: []int{-1}},
+		{Values: []int16{1, 2}, Weights: []int{0, 0}},
+	} {
+		if err := bad.Init(); err == nil {
+			t.Fatalf("expected init error for %+v", bad)
+		}
+	}
+
+	// mystery：全部揭示為同一符號
+	screen := []int16{mystery, 1, mystery, spec.CellBlocked}
+	sym, changed := RevealMystery(c, screen, mystery, &table, nil)
+	if sym != 3 || !slices.Equal(changed, []int16{0, 2}) || !slices.Equal(screen, []int16{3, 1, 3, spec.CellBlocked}) {
+		t.Fatalf("unexpected reveal: sym=%d screen=%v changed=%v", sym, screen, changed)
+	}
+	if sym, changed = RevealMystery(c, screen, mystery, &table, changed[:0]); sym != -1 || len(changed) != 0 {
+		t.Fatalf("expected no reveal without mystery, got %d %v", sym, changed)
+	}
+
+	// upgrade：L 符號升一階，最後一階不變
+	ladder := []int16{4, 5, 6}
+	screen = []int16{4, 5, 6, 1}
+	changed = Upgrade(screen, ladder, 1<<4|1<<5|1<<6|1<<1, changed[:0])
+	if !slices.Equal(screen, []int16{5, 6, 6, 1}) || !slices.Equal(changed, []int16{0, 1}) {
+		t.Fatalf("unexpected upgrade: screen=%v changed=%v", screen, changed)
+	}
+
+	// split：只分裂符合條件的格子，盤面不變
+	screen = []int16{1, 2, 1, spec.CellBlocked}
+	cc := make(cellCounts, len(screen))
+	scratch := make([]int, len(screen))
+	changed = SplitSymbols(c, screen, 5, 2, 1<<1, scratch, cc, changed[:0])
+	if len(changed) != 2 || cc[0] != 2 || cc[2] != 2 || cc[1] != 0 || cc[3] != 0 {
+		t.Fatalf("unexpected split: counts=%v changed=%v", cc, changed)
+	}
+	if !slices.Equal(screen, []int16{1, 2, 1, spec.CellBlocked}) {
+		t.Fatalf("split must not modify screen: %v", screen)
+	}
+}
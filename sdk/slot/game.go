@@ -103,7 +103,11 @@ func (g *Game) init(reg *LogicRegistry) error {
 	modecount := len(g.GameSetting.GameModeSettings)
 	g.GameModeHandlerList = make([]*GameMode, modecount)
 	for i := 0; i < modecount; i++ {
-		g.GameModeHandlerList[i] = newGameMode(g.Core, &g.GameSetting.GameModeSettings[i], i)
+		gm, err := newGameMode(g.Core, &g.GameSetting.GameModeSettings[i], i)
+		if err != nil {
+			return errs.Wrap(err, fmt.Sprintf("build game mode failed: game=%q mode=%d", g.GameName, i))
+		}
+		g.GameModeHandlerList[i] = gm
 	}
 
 	// 建立介面
@@ -94,14 +94,16 @@ type GameMode struct {
 //
 // 注意：不需要由遊戲邏輯實作者直接呼叫，因為上層 GameHandler 會負責建立並持有。
 // 你會在遊戲邏輯裡拿到已經初始化完成的 *GameModeHandler 來使用。
-func newGameMode(core *core.Core, gameModeSetting *spec.GameModeSetting, modeId int) *GameMode {
+func newGameMode(core *core.Core, gameModeSetting *spec.GameModeSetting, modeId int) (*GameMode, error) {
 	gm := &GameMode{
 		core:            core,
 		GameModeSetting: gameModeSetting,
 		modeId:          modeId,
 	}
-	gm.init()
-	return gm
+	if err := gm.init(); err != nil {
+		return nil, err
+	}
+	return gm, nil
 }
 
 // YieldResult 提交（Yield）當前 GameModeResult，並切換到下一個可寫 buffer。
@@ -175,11 +177,19 @@ func (gm *GameMode) ResetGameModeResult() {
 // 設計重點：
 // - generator / calculator 以 mode setting 建立一次後重用
 // - pool 預先配置 poolSize 個 *GameModeResult，並根據 BetType 估計初始容量以避免熱路徑擴容
-func (gm *GameMode) init() {
+func (gm *GameMode) init() error {
 	// 建立盤面生成器
-	gm.ScreenGenerator = gen.NewScreenGenerator(gm.core, &gm.GameModeSetting.ScreenSetting, &gm.GameModeSetting.GenScreenSetting)
+	sg, err := gen.NewScreenGeneratorE(gm.core, &gm.GameModeSetting.ScreenSetting, &gm.GameModeSetting.GenScreenSetting)
+	if err != nil {
+		return err
+	}
+	gm.ScreenGenerator = sg
 	// 建立盤面計算器
-	gm.ScreenCalculator = calc.NewScreenCalculator(gm.GameModeSetting)
+	sc, err := calc.NewScreenCalculatorE(gm.GameModeSetting)
+	if err != nil {
+		return err
+	}
+	gm.ScreenCalculator = sc
 
 	// 建立結果池
 	gm.pid = 0
@@ -189,6 +199,7 @@ func (gm *GameMode) init() {
 		gm.pool = append(gm.pool, buf.NewGameModeResult(gm.modeId, gm.GameModeSetting, buffer, hitsInitSize))
 	}
 	gm.GameModeResult = gm.pool[gm.pid]
+	return nil
 }
 
 // getInitCap 回傳 (bufferCap, hitsFlatInitSize)，用於初始化 GameModeResult 的內部切片容量。
@@ -231,6 +242,11 @@ func (gm *GameMode) getInitCap() (int, int) {
 		buffer = (gms.ScreenSetting.Columns * gms.ScreenSetting.Rows) + gms.SymbolSetting.SymbolCount // 盤面大小(每個格子都算一次得分)+圖標數量
 		hitsFlatInitSize = gms.ScreenSetting.Columns * gms.ScreenSetting.Rows * 2                     // 兩倍盤面大小
 	default:
+		// 自訂 BetType：優先使用註冊時提供的容量估計
+		if b, h, ok := calc.CapHint(gms); ok {
+			buffer, hitsFlatInitSize = max(b, 0), max(h, 0)
+			break
+		}
 		buffer = ((gms.ScreenSetting.Columns * gms.ScreenSetting.Rows) + gms.SymbolSetting.SymbolCount) * 4
 		hitsFlatInitSize = gms.ScreenSetting.Columns * gms.ScreenSetting.Rows * 2
 	}
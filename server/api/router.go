@@ -17,6 +17,7 @@ package api
 import (
 	"log/slog"
 
+	"github.com/zintix-labs/problab"
 	"github.com/zintix-labs/problab/server/api/dev"
 	"github.com/zintix-labs/problab/server/api/index"
 	v1 "github.com/zintix-labs/problab/server/api/v1"
@@ -39,21 +40,23 @@ import (
 // The problab repo's built-in cmd/svr is designed as a lab server (ModeDev).
 // Production services should typically be assembled in a separate project
 // (scaffold) and run with ModeProd by default.
-func RegisterRoutes(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
+//
+// rt 為共用的 SlotRuntime（由組裝端建立，生命週期由 app 管理）。
+func RegisterRoutes(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime) error {
 	registerMiddleware(svr, sCfg.Log) // 1. middleware
 	registerIndex(svr)                // 2. landing page
 
 	switch sCfg.Mode {
 	case svrcfg.ModeDev:
-		if err := registerDev(svr, sCfg); err != nil {
+		if err := registerDev(svr, sCfg, rt); err != nil {
 			return err
 		}
 	case svrcfg.ModeProd:
-		if err := registerProd(svr, sCfg); err != nil {
+		if err := registerProd(svr, sCfg, rt); err != nil {
 			return err
 		}
 	default:
-		if err := registerProd(svr, sCfg); err != nil {
+		if err := registerProd(svr, sCfg, rt); err != nil {
 			return err
 		}
 	}
@@ -74,10 +77,10 @@ func registerIndex(svr netsvr.NetSvr) {
 }
 
 // registerDev mounts dev APIs
-func registerDev(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
+func registerDev(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime) error {
 	// dev panel
 	dev.Register(svr, sCfg)
-	r, err := v1.NewSpinHandler(sCfg)
+	r, err := v1.NewSpinHandler(sCfg, rt)
 	if err != nil {
 		return err
 	}
@@ -90,6 +93,7 @@ func registerDev(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
 	svr.Group("/v1", func(rt netsvr.NetRouter) {
 		rt.Get("/spin", r.Spin)
 		rt.Post("/spin", r.Spin)
+		rt.Get("/spin/schema", r.SpinSchema)
 		rt.Get("/health", r.Health)
 		rt.Get("/poolmetrics", r.PoolMetrics)
 		rt.Get("/sim", s.Sim)
@@ -97,14 +101,15 @@ func registerDev(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
 		rt.Post("/simbycfg", s.SimByJson)
 		rt.Post("/sim", s.Sim)
 		rt.Post("/simplayer", s.SimPlayers)
+		rt.Post("/simshard", s.SimShard)
 		rt.Post("/stat", v1.Stat)
 	})
 	return nil
 }
 
 // registerProd mounts prod APIs
-func registerProd(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
-	r, err := v1.NewSpinHandler(sCfg)
+func registerProd(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime) error {
+	r, err := v1.NewSpinHandler(sCfg, rt)
 	if err != nil {
 		return err
 	}
@@ -112,6 +117,7 @@ func registerProd(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) error {
 	svr.Group("/v1", func(rt netsvr.NetRouter) {
 		rt.Get("/spin", r.Spin)
 		rt.Post("/spin", r.Spin)
+		rt.Get("/spin/schema", r.SpinSchema)
 		rt.Get("/health", r.Health)
 		rt.Get("/poolmetrics", r.PoolMetrics)
 	})
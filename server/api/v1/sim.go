@@ -25,7 +25,9 @@ import (
 	"strconv"
 
 	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/distsim"
 	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/recorder"
 	"github.com/zintix-labs/problab/server/httperr"
 	"github.com/zintix-labs/problab/server/svrcfg"
 	"github.com/zintix-labs/problab/spec"
@@ -324,3 +326,37 @@ func (sh *SimHandler) SimPlayers(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+// SimShard 執行分散式模擬的單一分片，回傳序列化後的 recorder.SpinRecorder
+//
+// 供 distsim.HTTPWorker 呼叫，僅於 dev 模式開放。
+func (sh *SimHandler) SimShard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	req := distsim.Shard{}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		httperr.Errs(w, errs.NewWarn("invalid json:"+err.Error()))
+		return
+	}
+	if _, ok := sh.Problab.EntryById(req.GameId); !ok {
+		httperr.Errs(w, errs.NewWarn("gid not found"))
+		return
+	}
+	rec, err := distsim.RunShard(sh.Problab, req)
+	if err != nil {
+		httperr.Log(sh.log, "simulate shard err", err)
+		httperr.Errs(w, errs.Wrap(err, "simulate shard err"))
+		return
+	}
+	b, err := recorder.EncodeSpinRecorder(rec)
+	if err != nil {
+		httperr.Errs(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
@@ -54,6 +54,19 @@ func (s *SpinHandler) Spin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Add("Vary", "Accept")
+	if dto.AcceptsCBOR(r) {
+		// 先完整編碼再寫出，避免寫到一半才出錯
+		b, err := dto.EncodeCBOR(result)
+		if err != nil {
+			httperr.Errs(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", dto.ContentTypeCBOR)
+		_, _ = w.Write(b)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		httperr.Errs(w, err)
@@ -73,6 +86,16 @@ func (s *SpinHandler) Spin(w http.ResponseWriter, r *http.Request) {
 	// _, _ = w.Write(b.Bytes())
 }
 
+// SpinSchema 回傳 spin API CBOR 格式的結構定義（CDDL）
+func (s *SpinHandler) SpinSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(dto.SpinSchemaCDDL))
+}
+
 func (s *SpinHandler) Health(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -108,9 +131,10 @@ func (s *SpinHandler) PoolMetrics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, ok := s.rt.PoolMetrics(spec.GID(gidint))
+	variant := r.URL.Query().Get("variant")
+	metrics, ok := s.rt.VariantPoolMetrics(spec.GID(gidint), variant)
 	if !ok {
-		httperr.Errs(w, errs.NewWarn("gid is not exist: "+game))
+		httperr.Errs(w, errs.NewWarn("gid/variant is not exist: "+game+" "+variant))
 		return
 	}
 
@@ -130,10 +154,10 @@ type SpinHandler struct {
 	log *slog.Logger
 }
 
-func NewSpinHandler(sCfg *svrcfg.SvrCfg) (*SpinHandler, error) {
-	rt, err := sCfg.Problab.BuildRuntime(sCfg.SlotBufSize)
-	if err != nil {
-		return nil, errs.Wrap(err, "build spin handler error")
+// NewSpinHandler 建立 SpinHandler；rt 由組裝端建立並與其他服務（例如 gRPC）共用
+func NewSpinHandler(sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime) (*SpinHandler, error) {
+	if rt == nil {
+		return nil, errs.NewFatal("build spin handler error: slot runtime is required")
 	}
 	return &SpinHandler{rt: rt, log: sCfg.Log}, nil
 }
@@ -0,0 +1,133 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client 是 problab gRPC 服務的 Go 客戶端，以 dto / runtime 結構收發。
+//
+//	c, err := client.Dial("localhost:5809")
+//	defer c.Close()
+//	res, err := c.Spin(ctx, &dto.SpinRequest{GameId: 0, Bet: 1, BetMult: 1})
+//
+// 回傳的錯誤：
+//   - deadline / 取消：errors.Is(err, context.DeadlineExceeded / context.Canceled) 成立
+//   - 請求錯誤（InvalidArgument / NotFound）：errs.Warn
+//   - 其他：errs.Fatal
+//
+// errs.E 的 Cause 為原始 gRPC status error。
+// SpinResult 的 ext 與 cp 以 json.RawMessage 回傳，由業務端依遊戲自行解析。
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/catalog"
+	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/server/rpc/problabv1"
+	"github.com/zintix-labs/problab/spec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Client problab gRPC 客戶端（並行安全）
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  problabv1.SlotRuntimeClient
+}
+
+// Dial 建立連線；未提供 opts 時使用不加密連線（內網服務間呼叫）
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, errs.Wrap(err, "rpc dial failed")
+	}
+	return &Client{conn: conn, rpc: problabv1.NewSlotRuntimeClient(conn)}, nil
+}
+
+// New 以既有連線建立客戶端（連線由呼叫端管理，Close 不會關閉它）
+func New(conn grpc.ClientConnInterface) *Client {
+	return &Client{rpc: problabv1.NewSlotRuntimeClient(conn)}
+}
+
+// Close 關閉 Dial 建立的連線
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Spin 執行一次 spin；ctx 的 deadline 會傳到伺服器端的 SlotRuntime.Spin
+func (c *Client) Spin(ctx context.Context, req *dto.SpinRequest) (dto.SpinResult, error) {
+	res, err := c.rpc.Spin(ctx, problabv1.SpinRequestFromDTO(req))
+	if err != nil {
+		return dto.SpinResult{}, fromStatus(err)
+	}
+	return problabv1.SpinResultToDTO(res), nil
+}
+
+// Health 取得 runtime 健康快照
+func (c *Client) Health(ctx context.Context) (problab.RuntimeHealth, error) {
+	res, err := c.rpc.Health(ctx, &problabv1.HealthRequest{})
+	if err != nil {
+		return problab.RuntimeHealth{}, fromStatus(err)
+	}
+	return problabv1.HealthToRuntime(res), nil
+}
+
+// PoolMetrics 取得指定遊戲（變體）的 pool 觀測快照（variant 為空字串時為預設變體）
+func (c *Client) PoolMetrics(ctx context.Context, gid spec.GID, variant string) (problab.MachinePoolMetrics, error) {
+	res, err := c.rpc.PoolMetrics(ctx, &problabv1.PoolMetricsRequest{Gid: uint64(gid), Variant: variant})
+	if err != nil {
+		return problab.MachinePoolMetrics{}, fromStatus(err)
+	}
+	return problabv1.PoolMetricsToRuntime(res), nil
+}
+
+// ListGames 列出所有遊戲與變體
+func (c *Client) ListGames(ctx context.Context) ([]catalog.Summary, error) {
+	res, err := c.rpc.ListGames(ctx, &problabv1.ListGamesRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return problabv1.GamesToCatalog(res), nil
+}
+
+// fromStatus 把 gRPC status 轉回 errs 分級（與 server 端 rpc.Code 相反方向）
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return errs.Wrap(err, "rpc call failed")
+	}
+	switch s.Code() {
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, s.Message())
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, s.Message())
+	case codes.InvalidArgument, codes.NotFound:
+		e := errs.NewWarn(s.Message())
+		e.Cause = err
+		return e
+	default:
+		e := errs.NewFatal(s.Message())
+		e.Cause = err
+		return e
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package problabv1
+
+// dto / runtime 結構與 protobuf 訊息的雙向轉換（server 與 client 共用）。
+//
+// ext / cp 為遊戲自定義結構，以 JSON bytes 傳輸：
+//   - server 端：ExtendResult 以 json.Marshal 編碼；cp 直接沿用 dto 的 json.RawMessage
+//   - client 端：兩者都還原成 json.RawMessage，由業務端依遊戲自行解析
+
+import (
+	"encoding/json"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/catalog"
+	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/spec"
+)
+
+// ============================================================
+// ** Spin **
+// ============================================================
+
+// SpinRequestFromDTO dto.SpinRequest → SpinRequest
+func SpinRequestFromDTO(r *dto.SpinRequest) *SpinRequest {
+	if r == nil {
+		return nil
+	}
+	pb := &SpinRequest{
+		Uid:         r.UID,
+		Game:        r.GameName,
+		Gid:         uint64(r.GameId),
+		Variant:     r.Variant,
+		Operator:    r.Operator,
+		Bet:         int64(r.Bet),
+		BetMode:     int64(r.BetMode),
+		BetMult:     int64(r.BetMult),
+		Cycle:       int64(r.Cycle),
+		Choice:      int64(r.Choice),
+		HasChoice:   r.HasChoice,
+		Fingerprint: r.Fingerprint,
+		Verbosity:   string(r.Verbosity),
+	}
+	if r.StartState != nil {
+		pb.StartState = &StartState{
+			StartB64U: r.StartState.StartCoreSnapB64U,
+			Cp:        r.StartState.Checkpoint,
+		}
+	}
+	return pb
+}
+
+// SpinRequestToDTO SpinRequest → dto.SpinRequest
+//
+// 與 HTTP 相同的 choice contract：has_choice 為 false 時 choice 必須為 0。
+func SpinRequestToDTO(pb *SpinRequest) (*dto.SpinRequest, error) {
+	if pb == nil {
+		return nil, errs.NewWarn("nil spin request")
+	}
+	if !pb.HasChoice && pb.Choice != 0 {
+		return nil, errs.NewWarn("choice must be omitted when has_choice is false")
+	}
+	r := &dto.SpinRequest{
+		UID:         pb.Uid,
+		GameName:    pb.Game,
+		GameId:      spec.GID(pb.Gid),
+		Variant:     pb.Variant,
+		Operator:    pb.Operator,
+		Bet:         int(pb.Bet),
+		BetMode:     int(pb.BetMode),
+		BetMult:     int(pb.BetMult),
+		Cycle:       int(pb.Cycle),
+		Choice:      int(pb.Choice),
+		HasChoice:   pb.HasChoice,
+		Fingerprint: pb.Fingerprint,
+		Verbosity:   dto.Verbosity(pb.Verbosity),
+	}
+	if ss := pb.StartState; ss != nil && (ss.StartB64U != "" || len(ss.Cp) > 0) {
+		r.StartState = &dto.StartState{StartCoreSnapB64U: ss.StartB64U}
+		if len(ss.Cp) > 0 {
+			if !json.Valid(ss.Cp) {
+				return nil, errs.NewWarn("start_state.cp is not valid json")
+			}
+			r.StartState.Checkpoint = json.RawMessage(ss.Cp)
+		}
+	}
+	return r, nil
+}
+
+// SpinResultFromDTO dto.SpinResult → SpinResult
+func SpinResultFromDTO(r *dto.SpinResult) (*SpinResult, error) {
+	pb := &SpinResult{
+		Game:        r.GameName,
+		Gameid:      uint64(r.GameID),
+		Variant:     r.Variant,
+		Win:         int64(r.TotalWin),
+		Bet:         int64(r.Bet),
+		Betmode:     int64(r.BetMode),
+		Betmult:     int64(r.BetMult),
+		Isend:       r.IsGameEnd,
+		Fingerprint: r.Fingerprint,
+		SpinState: &SpinState{
+			StartB64U: r.State.StartCoreSnapB64U,
+			AfterB64U: r.State.AfterCoreSnapB64U,
+			Cp:        r.State.Checkpoint,
+		},
+	}
+	if len(r.GameModes) > 0 {
+		pb.Gamemodes = make([]*GameModeResult, len(r.GameModes))
+		for i := range r.GameModes {
+			gm, err := gameModeFromDTO(&r.GameModes[i])
+			if err != nil {
+				return nil, err
+			}
+			pb.Gamemodes[i] = gm
+		}
+	}
+	return pb, nil
+}
+
+// SpinResultToDTO SpinResult → dto.SpinResult（ext 還原為 json.RawMessage）
+func SpinResultToDTO(pb *SpinResult) dto.SpinResult {
+	r := dto.SpinResult{
+		GameName:    pb.GetGame(),
+		GameID:      spec.GID(pb.GetGameid()),
+		Variant:     pb.GetVariant(),
+		TotalWin:    int(pb.GetWin()),
+		Bet:         int(pb.GetBet()),
+		BetMode:     int(pb.GetBetmode()),
+		BetMult:     int(pb.GetBetmult()),
+		IsGameEnd:   pb.GetIsend(),
+		Fingerprint: pb.GetFingerprint(),
+	}
+	if st := pb.GetSpinState(); st != nil {
+		r.State = dto.SpinState{
+			StartCoreSnapB64U: st.StartB64U,
+			AfterCoreSnapB64U: st.AfterB64U,
+		}
+		if len(st.Cp) > 0 {
+			r.State.Checkpoint = json.RawMessage(st.Cp)
+		}
+	}
+	if len(pb.GetGamemodes()) > 0 {
+		r.GameModes = make([]dto.GameModeResultDTO, len(pb.Gamemodes))
+		for i, gm := range pb.Gamemodes {
+			r.GameModes[i] = gameModeToDTO(gm)
+		}
+	}
+	return r
+}
+
+func gameModeFromDTO(g *dto.GameModeResultDTO) (*GameModeResult, error) {
+	pb := &GameModeResult{
+		Win:     int64(g.TotalWin),
+		Modeid:  int64(g.GameModeId),
+		Isend:   g.IsModeEnd,
+		Trigger: int64(g.Trigger),
+	}
+	if len(g.ActResults) > 0 {
+		pb.Acts = make([]*ActResult, len(g.ActResults))
+		for i := range g.ActResults {
+			a := &g.ActResults[i]
+			act := &ActResult{
+				Acttype:     a.ActType,
+				Id:          int64(a.Id),
+				Round:       int64(a.RoundId),
+				Step:        int64(a.StepId),
+				Act:         int64(a.ActId),
+				IsRoundEnd:  a.IsRoundEnd,
+				IsStepEnd:   a.IsStepEnd,
+				Nowtotalwin: int64(a.NowTotalWin),
+				Roundaccwin: int64(a.RoundAccWin),
+				Stepaccwin:  int64(a.StepAccWin),
+				Actwin:      int64(a.ActWin),
+				Basewin:     int64(a.BaseWin),
+				Mult:        int64(a.Mult),
+				Screen:      toInt32s(a.Screen),
+				Layout:      toInt32s(a.Layout),
+			}
+			if len(a.Details) > 0 {
+				act.Details = make([]*CalcScreenDetail, len(a.Details))
+				for k := range a.Details {
+					d := &a.Details[k]
+					act.Details[k] = &CalcScreenDetail{
+						Win:       int64(d.Win),
+						Symbol:    int32(d.SymbolID),
+						Line:      int64(d.LineID),
+						Count:     int64(d.Count),
+						Comb:      int64(d.Combinations),
+						Direction: uint32(d.Direction),
+						Mult:      int64(d.Multiplier),
+						Hits:      toInt32s(d.HitMap),
+					}
+				}
+			}
+			if a.ExtendResult != nil {
+				ext, err := json.Marshal(a.ExtendResult)
+				if err != nil {
+					return nil, errs.Wrap(err, "encode extend result failed")
+				}
+				act.Ext = ext
+			}
+			pb.Acts[i] = act
+		}
+	}
+	return pb, nil
+}
+
+func gameModeToDTO(pb *GameModeResult) dto.GameModeResultDTO {
+	g := dto.GameModeResultDTO{
+		TotalWin:   int(pb.GetWin()),
+		GameModeId: int(pb.GetModeid()),
+		IsModeEnd:  pb.GetIsend(),
+		Trigger:    int(pb.GetTrigger()),
+	}
+	if len(pb.GetActs()) > 0 {
+		g.ActResults = make([]dto.ActResultDTO, len(pb.Acts))
+		for i, act := range pb.Acts {
+			a := dto.ActResultDTO{
+				ActType:     act.Acttype,
+				Id:          int(act.Id),
+				RoundId:     int(act.Round),
+				StepId:      int(act.Step),
+				ActId:       int(act.Act),
+				IsRoundEnd:  act.IsRoundEnd,
+				IsStepEnd:   act.IsStepEnd,
+				NowTotalWin: int(act.Nowtotalwin),
+				RoundAccWin: int(act.Roundaccwin),
+				StepAccWin:  int(act.Stepaccwin),
+				ActWin:      int(act.Actwin),
+				BaseWin:     int(act.Basewin),
+				Mult:        int(act.Mult),
+				Screen:      toInt16s(act.Screen),
+				Layout:      toInt16s(act.Layout),
+			}
+			if len(act.Details) > 0 {
+				a.Details = make([]dto.CalcScreenDetailDTO, len(act.Details))
+				for k, d := range act.Details {
+					a.Details[k] = dto.CalcScreenDetailDTO{
+						Win:          int(d.Win),
+						SymbolID:     int16(d.Symbol),
+						LineID:       int(d.Line),
+						Count:        int(d.Count),
+						Combinations: int(d.Comb),
+						Direction:    uint8(d.Direction),
+						Multiplier:   int(d.Mult),
+						HitMap:       toInt16s(d.Hits),
+					}
+				}
+			}
+			if len(act.Ext) > 0 {
+				a.ExtendResult = json.RawMessage(act.Ext)
+			}
+			g.ActResults[i] = a
+		}
+	}
+	return g
+}
+
+func toInt32s(s []int16) []int32 {
+	if s == nil {
+		return nil
+	}
+	out := make([]int32, len(s))
+	for i, v := range s {
+		out[i] = int32(v)
+	}
+	return out
+}
+
+func toInt16s(s []int32) []int16 {
+	if s == nil {
+		return nil
+	}
+	out := make([]int16, len(s))
+	for i, v := range s {
+		out[i] = int16(v)
+	}
+	return out
+}
+
+// ============================================================
+// ** Health / PoolMetrics / ListGames **
+// ============================================================
+
+// HealthFromRuntime problab.RuntimeHealth → HealthResponse
+func HealthFromRuntime(h problab.RuntimeHealth) *HealthResponse {
+	pb := &HealthResponse{
+		AtUnixMs:  h.AtUnixMS,
+		RuntimeOk: h.RuntimeOK,
+		Reason:    h.Reason,
+		Overall:   h.Overall,
+	}
+	for _, id := range h.ClosedPools {
+		pb.ClosedPools = append(pb.ClosedPools, uint64(id))
+	}
+	return pb
+}
+
+// HealthToRuntime HealthResponse → problab.RuntimeHealth
+func HealthToRuntime(pb *HealthResponse) problab.RuntimeHealth {
+	h := problab.RuntimeHealth{
+		AtUnixMS:  pb.GetAtUnixMs(),
+		RuntimeOK: pb.GetRuntimeOk(),
+		Reason:    pb.GetReason(),
+		Overall:   pb.GetOverall(),
+	}
+	for _, id := range pb.GetClosedPools() {
+		h.ClosedPools = append(h.ClosedPools, spec.GID(id))
+	}
+	return h
+}
+
+// PoolMetricsFromRuntime problab.MachinePoolMetrics → PoolMetricsResponse
+func PoolMetricsFromRuntime(m problab.MachinePoolMetrics) *PoolMetricsResponse {
+	return &PoolMetricsResponse{
+		GameName:      m.GameName,
+		GameId:        uint64(m.GameID),
+		Variant:       m.Variant,
+		PoolSize:      int64(m.PoolSize),
+		Available:     int64(m.Available),
+		Inflight:      int64(m.Inflight),
+		BrokenBacklog: int64(m.BrokenBacklog),
+		Rebuild:       int64(m.Rebuild),
+		Panics:        int64(m.Panics),
+		Fatals:        int64(m.Fatals),
+		Closed:        m.Closed,
+		CloseReason:   m.CloseReason,
+		CloseInflight: int64(m.CloseInflight),
+		CloseAvail:    int64(m.CloseAvail),
+		CloseBroken:   int64(m.Closebroken),
+	}
+}
+
+// PoolMetricsToRuntime PoolMetricsResponse → problab.MachinePoolMetrics
+func PoolMetricsToRuntime(pb *PoolMetricsResponse) problab.MachinePoolMetrics {
+	return problab.MachinePoolMetrics{
+		GameName:      pb.GetGameName(),
+		GameID:        spec.GID(pb.GetGameId()),
+		Variant:       pb.GetVariant(),
+		PoolSize:      int(pb.GetPoolSize()),
+		Available:     int(pb.GetAvailable()),
+		Inflight:      int(pb.GetInflight()),
+		BrokenBacklog: int(pb.GetBrokenBacklog()),
+		Rebuild:       int(pb.GetRebuild()),
+		Panics:        int(pb.GetPanics()),
+		Fatals:        int(pb.GetFatals()),
+		Closed:        pb.GetClosed(),
+		CloseReason:   pb.GetCloseReason(),
+		CloseInflight: int(pb.GetCloseInflight()),
+		CloseAvail:    int(pb.GetCloseAvail()),
+		Closebroken:   int(pb.GetCloseBroken()),
+	}
+}
+
+// GamesFromCatalog []catalog.Summary → ListGamesResponse
+func GamesFromCatalog(sum []catalog.Summary) *ListGamesResponse {
+	pb := &ListGamesResponse{Games: make([]*GameSummary, len(sum))}
+	for i, s := range sum {
+		g := &GameSummary{
+			Gid:         uint64(s.GID),
+			Name:        s.Name,
+			Logic:       string(s.Logic),
+			Fingerprint: s.Fingerprint,
+			BetUnits:    make([]int64, len(s.BetUnits)),
+			Variants:    make([]*VariantSummary, len(s.Variants)),
+		}
+		for k, u := range s.BetUnits {
+			g.BetUnits[k] = int64(u)
+		}
+		for k, v := range s.Variants {
+			g.Variants[k] = &VariantSummary{
+				Variant:     v.Variant,
+				Rtp:         v.RTP,
+				Fingerprint: v.Fingerprint,
+				Default:     v.Default,
+			}
+		}
+		pb.Games[i] = g
+	}
+	return pb
+}
+
+// GamesToCatalog ListGamesResponse → []catalog.Summary
+func GamesToCatalog(pb *ListGamesResponse) []catalog.Summary {
+	sum := make([]catalog.Summary, len(pb.GetGames()))
+	for i, g := range pb.GetGames() {
+		s := catalog.Summary{
+			GID:         spec.GID(g.Gid),
+			Name:        g.Name,
+			Logic:       spec.LogicKey(g.Logic),
+			Fingerprint: g.Fingerprint,
+			BetUnits:    make([]int, len(g.BetUnits)),
+			Variants:    make([]catalog.VariantSummary, len(g.Variants)),
+		}
+		for k, u := range g.BetUnits {
+			s.BetUnits[k] = int(u)
+		}
+		for k, v := range g.Variants {
+			s.Variants[k] = catalog.VariantSummary{
+				Variant:     v.Variant,
+				RTP:         v.Rtp,
+				Fingerprint: v.Fingerprint,
+				Default:     v.Default,
+			}
+		}
+		sum[i] = s
+	}
+	return sum
+}
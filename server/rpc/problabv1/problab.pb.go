@@ -0,0 +1,1549 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// problab gRPC 介面：與 HTTP /v1/spin、/v1/health、/v1/poolmetrics 相同的語意，
+// 欄位對應 dto.SpinRequest / dto.SpinResult（見 server/rpc/problabv1/convert.go）。
+//
+// 重新產生：
+//
+//	cd server/rpc/problabv1 && buf generate
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: problab.proto
+
+package problabv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SpinRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Uid      string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Game     string                 `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
+	Gid      uint64                 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	Variant  string                 `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	Operator string                 `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
+	Bet      int64                  `protobuf:"varint,6,opt,name=bet,proto3" json:"bet,omitempty"`
+	BetMode  int64                  `protobuf:"varint,7,opt,name=bet_mode,json=betMode,proto3" json:"bet_mode,omitempty"`
+	BetMult  int64                  `protobuf:"varint,8,opt,name=bet_mult,json=betMult,proto3" json:"bet_mult,omitempty"`
+	Cycle    int64                  `protobuf:"varint,9,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	// choice 只有在 has_choice 為 true 時有效（同 dto.SpinRequest 的 contract）
+	Choice      int64       `protobuf:"varint,10,opt,name=choice,proto3" json:"choice,omitempty"`
+	HasChoice   bool        `protobuf:"varint,11,opt,name=has_choice,json=hasChoice,proto3" json:"has_choice,omitempty"`
+	StartState  *StartState `protobuf:"bytes,12,opt,name=start_state,json=startState,proto3" json:"start_state,omitempty"`
+	Fingerprint string      `protobuf:"bytes,13,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	// verbosity：回應詳細程度 summary / screens / full，留空為 full
+	Verbosity     string `protobuf:"bytes,14,opt,name=verbosity,proto3" json:"verbosity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpinRequest) Reset() {
+	*x = SpinRequest{}
+	mi := &file_problab_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpinRequest) ProtoMessage() {}
+
+func (x *SpinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpinRequest.ProtoReflect.Descriptor instead.
+func (*SpinRequest) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SpinRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetGid() uint64 {
+	if x != nil {
+		return x.Gid
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetBet() int64 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetBetMode() int64 {
+	if x != nil {
+		return x.BetMode
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetBetMult() int64 {
+	if x != nil {
+		return x.BetMult
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetChoice() int64 {
+	if x != nil {
+		return x.Choice
+	}
+	return 0
+}
+
+func (x *SpinRequest) GetHasChoice() bool {
+	if x != nil {
+		return x.HasChoice
+	}
+	return false
+}
+
+func (x *SpinRequest) GetStartState() *StartState {
+	if x != nil {
+		return x.StartState
+	}
+	return nil
+}
+
+func (x *SpinRequest) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetVerbosity() string {
+	if x != nil {
+		return x.Verbosity
+	}
+	return ""
+}
+
+type StartState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartB64U string                 `protobuf:"bytes,1,opt,name=start_b64u,json=startB64u,proto3" json:"start_b64u,omitempty"`
+	// cp：遊戲自定義的 checkpoint（JSON，由 dto.RegisterCheckpoint 註冊的型別編碼）
+	Cp            []byte `protobuf:"bytes,2,opt,name=cp,proto3" json:"cp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartState) Reset() {
+	*x = StartState{}
+	mi := &file_problab_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartState) ProtoMessage() {}
+
+func (x *StartState) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartState.ProtoReflect.Descriptor instead.
+func (*StartState) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StartState) GetStartB64U() string {
+	if x != nil {
+		return x.StartB64U
+	}
+	return ""
+}
+
+func (x *StartState) GetCp() []byte {
+	if x != nil {
+		return x.Cp
+	}
+	return nil
+}
+
+type SpinResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Gameid        uint64                 `protobuf:"varint,2,opt,name=gameid,proto3" json:"gameid,omitempty"`
+	Variant       string                 `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	Win           int64                  `protobuf:"varint,4,opt,name=win,proto3" json:"win,omitempty"`
+	Bet           int64                  `protobuf:"varint,5,opt,name=bet,proto3" json:"bet,omitempty"`
+	Betmode       int64                  `protobuf:"varint,6,opt,name=betmode,proto3" json:"betmode,omitempty"`
+	Betmult       int64                  `protobuf:"varint,7,opt,name=betmult,proto3" json:"betmult,omitempty"`
+	Gamemodes     []*GameModeResult      `protobuf:"bytes,8,rep,name=gamemodes,proto3" json:"gamemodes,omitempty"`
+	Isend         bool                   `protobuf:"varint,9,opt,name=isend,proto3" json:"isend,omitempty"`
+	SpinState     *SpinState             `protobuf:"bytes,10,opt,name=spin_state,json=spinState,proto3" json:"spin_state,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,11,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpinResult) Reset() {
+	*x = SpinResult{}
+	mi := &file_problab_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpinResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpinResult) ProtoMessage() {}
+
+func (x *SpinResult) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpinResult.ProtoReflect.Descriptor instead.
+func (*SpinResult) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SpinResult) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *SpinResult) GetGameid() uint64 {
+	if x != nil {
+		return x.Gameid
+	}
+	return 0
+}
+
+func (x *SpinResult) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *SpinResult) GetWin() int64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+func (x *SpinResult) GetBet() int64 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *SpinResult) GetBetmode() int64 {
+	if x != nil {
+		return x.Betmode
+	}
+	return 0
+}
+
+func (x *SpinResult) GetBetmult() int64 {
+	if x != nil {
+		return x.Betmult
+	}
+	return 0
+}
+
+func (x *SpinResult) GetGamemodes() []*GameModeResult {
+	if x != nil {
+		return x.Gamemodes
+	}
+	return nil
+}
+
+func (x *SpinResult) GetIsend() bool {
+	if x != nil {
+		return x.Isend
+	}
+	return false
+}
+
+func (x *SpinResult) GetSpinState() *SpinState {
+	if x != nil {
+		return x.SpinState
+	}
+	return nil
+}
+
+func (x *SpinResult) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+type SpinState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartB64U string                 `protobuf:"bytes,1,opt,name=start_b64u,json=startB64u,proto3" json:"start_b64u,omitempty"`
+	AfterB64U string                 `protobuf:"bytes,2,opt,name=after_b64u,json=afterB64u,proto3" json:"after_b64u,omitempty"`
+	// cp：同 StartState.cp
+	Cp            []byte `protobuf:"bytes,3,opt,name=cp,proto3" json:"cp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpinState) Reset() {
+	*x = SpinState{}
+	mi := &file_problab_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpinState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpinState) ProtoMessage() {}
+
+func (x *SpinState) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpinState.ProtoReflect.Descriptor instead.
+func (*SpinState) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SpinState) GetStartB64U() string {
+	if x != nil {
+		return x.StartB64U
+	}
+	return ""
+}
+
+func (x *SpinState) GetAfterB64U() string {
+	if x != nil {
+		return x.AfterB64U
+	}
+	return ""
+}
+
+func (x *SpinState) GetCp() []byte {
+	if x != nil {
+		return x.Cp
+	}
+	return nil
+}
+
+type GameModeResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Win           int64                  `protobuf:"varint,1,opt,name=win,proto3" json:"win,omitempty"`
+	Modeid        int64                  `protobuf:"varint,2,opt,name=modeid,proto3" json:"modeid,omitempty"`
+	Isend         bool                   `protobuf:"varint,3,opt,name=isend,proto3" json:"isend,omitempty"`
+	Trigger       int64                  `protobuf:"varint,4,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	Acts          []*ActResult           `protobuf:"bytes,5,rep,name=acts,proto3" json:"acts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameModeResult) Reset() {
+	*x = GameModeResult{}
+	mi := &file_problab_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameModeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameModeResult) ProtoMessage() {}
+
+func (x *GameModeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameModeResult.ProtoReflect.Descriptor instead.
+func (*GameModeResult) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GameModeResult) GetWin() int64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+func (x *GameModeResult) GetModeid() int64 {
+	if x != nil {
+		return x.Modeid
+	}
+	return 0
+}
+
+func (x *GameModeResult) GetIsend() bool {
+	if x != nil {
+		return x.Isend
+	}
+	return false
+}
+
+func (x *GameModeResult) GetTrigger() int64 {
+	if x != nil {
+		return x.Trigger
+	}
+	return 0
+}
+
+func (x *GameModeResult) GetActs() []*ActResult {
+	if x != nil {
+		return x.Acts
+	}
+	return nil
+}
+
+type ActResult struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Acttype     string                 `protobuf:"bytes,1,opt,name=acttype,proto3" json:"acttype,omitempty"`
+	Id          int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Round       int64                  `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"`
+	Step        int64                  `protobuf:"varint,4,opt,name=step,proto3" json:"step,omitempty"`
+	Act         int64                  `protobuf:"varint,5,opt,name=act,proto3" json:"act,omitempty"`
+	IsRoundEnd  bool                   `protobuf:"varint,6,opt,name=is_round_end,json=isRoundEnd,proto3" json:"is_round_end,omitempty"`
+	IsStepEnd   bool                   `protobuf:"varint,7,opt,name=is_step_end,json=isStepEnd,proto3" json:"is_step_end,omitempty"`
+	Nowtotalwin int64                  `protobuf:"varint,8,opt,name=nowtotalwin,proto3" json:"nowtotalwin,omitempty"`
+	Roundaccwin int64                  `protobuf:"varint,9,opt,name=roundaccwin,proto3" json:"roundaccwin,omitempty"`
+	Stepaccwin  int64                  `protobuf:"varint,10,opt,name=stepaccwin,proto3" json:"stepaccwin,omitempty"`
+	Actwin      int64                  `protobuf:"varint,11,opt,name=actwin,proto3" json:"actwin,omitempty"`
+	Basewin     int64                  `protobuf:"varint,12,opt,name=basewin,proto3" json:"basewin,omitempty"`
+	Mult        int64                  `protobuf:"varint,13,opt,name=mult,proto3" json:"mult,omitempty"`
+	// screen：row-major，-1 表示遮罩格
+	Screen  []int32             `protobuf:"zigzag32,14,rep,packed,name=screen,proto3" json:"screen,omitempty"`
+	Layout  []int32             `protobuf:"zigzag32,15,rep,packed,name=layout,proto3" json:"layout,omitempty"`
+	Details []*CalcScreenDetail `protobuf:"bytes,16,rep,name=details,proto3" json:"details,omitempty"`
+	// ext：遊戲自定義的 ExtendResult（JSON，由 dto.RegisterExtendRender 註冊的型別編碼）
+	Ext           []byte `protobuf:"bytes,17,opt,name=ext,proto3" json:"ext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActResult) Reset() {
+	*x = ActResult{}
+	mi := &file_problab_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActResult) ProtoMessage() {}
+
+func (x *ActResult) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActResult.ProtoReflect.Descriptor instead.
+func (*ActResult) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ActResult) GetActtype() string {
+	if x != nil {
+		return x.Acttype
+	}
+	return ""
+}
+
+func (x *ActResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ActResult) GetRound() int64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *ActResult) GetStep() int64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *ActResult) GetAct() int64 {
+	if x != nil {
+		return x.Act
+	}
+	return 0
+}
+
+func (x *ActResult) GetIsRoundEnd() bool {
+	if x != nil {
+		return x.IsRoundEnd
+	}
+	return false
+}
+
+func (x *ActResult) GetIsStepEnd() bool {
+	if x != nil {
+		return x.IsStepEnd
+	}
+	return false
+}
+
+func (x *ActResult) GetNowtotalwin() int64 {
+	if x != nil {
+		return x.Nowtotalwin
+	}
+	return 0
+}
+
+func (x *ActResult) GetRoundaccwin() int64 {
+	if x != nil {
+		return x.Roundaccwin
+	}
+	return 0
+}
+
+func (x *ActResult) GetStepaccwin() int64 {
+	if x != nil {
+		return x.Stepaccwin
+	}
+	return 0
+}
+
+func (x *ActResult) GetActwin() int64 {
+	if x != nil {
+		return x.Actwin
+	}
+	return 0
+}
+
+func (x *ActResult) GetBasewin() int64 {
+	if x != nil {
+		return x.Basewin
+	}
+	return 0
+}
+
+func (x *ActResult) GetMult() int64 {
+	if x != nil {
+		return x.Mult
+	}
+	return 0
+}
+
+func (x *ActResult) GetScreen() []int32 {
+	if x != nil {
+		return x.Screen
+	}
+	return nil
+}
+
+func (x *ActResult) GetLayout() []int32 {
+	if x != nil {
+		return x.Layout
+	}
+	return nil
+}
+
+func (x *ActResult) GetDetails() []*CalcScreenDetail {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *ActResult) GetExt() []byte {
+	if x != nil {
+		return x.Ext
+	}
+	return nil
+}
+
+type CalcScreenDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Win           int64                  `protobuf:"varint,1,opt,name=win,proto3" json:"win,omitempty"`
+	Symbol        int32                  `protobuf:"zigzag32,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Line          int64                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	Count         int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Comb          int64                  `protobuf:"varint,5,opt,name=comb,proto3" json:"comb,omitempty"`
+	Direction     uint32                 `protobuf:"varint,6,opt,name=direction,proto3" json:"direction,omitempty"`
+	Mult          int64                  `protobuf:"varint,7,opt,name=mult,proto3" json:"mult,omitempty"`
+	Hits          []int32                `protobuf:"zigzag32,8,rep,packed,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalcScreenDetail) Reset() {
+	*x = CalcScreenDetail{}
+	mi := &file_problab_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalcScreenDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalcScreenDetail) ProtoMessage() {}
+
+func (x *CalcScreenDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalcScreenDetail.ProtoReflect.Descriptor instead.
+func (*CalcScreenDetail) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CalcScreenDetail) GetWin() int64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetSymbol() int32 {
+	if x != nil {
+		return x.Symbol
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetComb() int64 {
+	if x != nil {
+		return x.Comb
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetDirection() uint32 {
+	if x != nil {
+		return x.Direction
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetMult() int64 {
+	if x != nil {
+		return x.Mult
+	}
+	return 0
+}
+
+func (x *CalcScreenDetail) GetHits() []int32 {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_problab_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{7}
+}
+
+type HealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AtUnixMs      int64                  `protobuf:"varint,1,opt,name=at_unix_ms,json=atUnixMs,proto3" json:"at_unix_ms,omitempty"`
+	RuntimeOk     bool                   `protobuf:"varint,2,opt,name=runtime_ok,json=runtimeOk,proto3" json:"runtime_ok,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Overall       string                 `protobuf:"bytes,4,opt,name=overall,proto3" json:"overall,omitempty"`
+	ClosedPools   []uint64               `protobuf:"varint,5,rep,packed,name=closed_pools,json=closedPools,proto3" json:"closed_pools,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_problab_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HealthResponse) GetAtUnixMs() int64 {
+	if x != nil {
+		return x.AtUnixMs
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetRuntimeOk() bool {
+	if x != nil {
+		return x.RuntimeOk
+	}
+	return false
+}
+
+func (x *HealthResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetOverall() string {
+	if x != nil {
+		return x.Overall
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetClosedPools() []uint64 {
+	if x != nil {
+		return x.ClosedPools
+	}
+	return nil
+}
+
+type PoolMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Gid   uint64                 `protobuf:"varint,1,opt,name=gid,proto3" json:"gid,omitempty"`
+	// variant 留空為預設變體
+	Variant       string `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolMetricsRequest) Reset() {
+	*x = PoolMetricsRequest{}
+	mi := &file_problab_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolMetricsRequest) ProtoMessage() {}
+
+func (x *PoolMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolMetricsRequest.ProtoReflect.Descriptor instead.
+func (*PoolMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PoolMetricsRequest) GetGid() uint64 {
+	if x != nil {
+		return x.Gid
+	}
+	return 0
+}
+
+func (x *PoolMetricsRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+type PoolMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameName      string                 `protobuf:"bytes,1,opt,name=game_name,json=gameName,proto3" json:"game_name,omitempty"`
+	GameId        uint64                 `protobuf:"varint,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Variant       string                 `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	PoolSize      int64                  `protobuf:"varint,4,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	Available     int64                  `protobuf:"varint,5,opt,name=available,proto3" json:"available,omitempty"`
+	Inflight      int64                  `protobuf:"varint,6,opt,name=inflight,proto3" json:"inflight,omitempty"`
+	BrokenBacklog int64                  `protobuf:"varint,7,opt,name=broken_backlog,json=brokenBacklog,proto3" json:"broken_backlog,omitempty"`
+	Rebuild       int64                  `protobuf:"varint,8,opt,name=rebuild,proto3" json:"rebuild,omitempty"`
+	Panics        int64                  `protobuf:"varint,9,opt,name=panics,proto3" json:"panics,omitempty"`
+	Fatals        int64                  `protobuf:"varint,10,opt,name=fatals,proto3" json:"fatals,omitempty"`
+	Closed        bool                   `protobuf:"varint,11,opt,name=closed,proto3" json:"closed,omitempty"`
+	CloseReason   string                 `protobuf:"bytes,12,opt,name=close_reason,json=closeReason,proto3" json:"close_reason,omitempty"`
+	CloseInflight int64                  `protobuf:"varint,13,opt,name=close_inflight,json=closeInflight,proto3" json:"close_inflight,omitempty"`
+	CloseAvail    int64                  `protobuf:"varint,14,opt,name=close_avail,json=closeAvail,proto3" json:"close_avail,omitempty"`
+	CloseBroken   int64                  `protobuf:"varint,15,opt,name=close_broken,json=closeBroken,proto3" json:"close_broken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolMetricsResponse) Reset() {
+	*x = PoolMetricsResponse{}
+	mi := &file_problab_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolMetricsResponse) ProtoMessage() {}
+
+func (x *PoolMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolMetricsResponse.ProtoReflect.Descriptor instead.
+func (*PoolMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PoolMetricsResponse) GetGameName() string {
+	if x != nil {
+		return x.GameName
+	}
+	return ""
+}
+
+func (x *PoolMetricsResponse) GetGameId() uint64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *PoolMetricsResponse) GetPoolSize() int64 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetAvailable() int64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetInflight() int64 {
+	if x != nil {
+		return x.Inflight
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetBrokenBacklog() int64 {
+	if x != nil {
+		return x.BrokenBacklog
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetRebuild() int64 {
+	if x != nil {
+		return x.Rebuild
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetPanics() int64 {
+	if x != nil {
+		return x.Panics
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetFatals() int64 {
+	if x != nil {
+		return x.Fatals
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *PoolMetricsResponse) GetCloseReason() string {
+	if x != nil {
+		return x.CloseReason
+	}
+	return ""
+}
+
+func (x *PoolMetricsResponse) GetCloseInflight() int64 {
+	if x != nil {
+		return x.CloseInflight
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetCloseAvail() int64 {
+	if x != nil {
+		return x.CloseAvail
+	}
+	return 0
+}
+
+func (x *PoolMetricsResponse) GetCloseBroken() int64 {
+	if x != nil {
+		return x.CloseBroken
+	}
+	return 0
+}
+
+type ListGamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesRequest) Reset() {
+	*x = ListGamesRequest{}
+	mi := &file_problab_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesRequest) ProtoMessage() {}
+
+func (x *ListGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesRequest.ProtoReflect.Descriptor instead.
+func (*ListGamesRequest) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{11}
+}
+
+type ListGamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []*GameSummary         `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesResponse) Reset() {
+	*x = ListGamesResponse{}
+	mi := &file_problab_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesResponse) ProtoMessage() {}
+
+func (x *ListGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesResponse.ProtoReflect.Descriptor instead.
+func (*ListGamesResponse) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListGamesResponse) GetGames() []*GameSummary {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type GameSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gid           uint64                 `protobuf:"varint,1,opt,name=gid,proto3" json:"gid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Logic         string                 `protobuf:"bytes,3,opt,name=logic,proto3" json:"logic,omitempty"`
+	BetUnits      []int64                `protobuf:"varint,4,rep,packed,name=bet_units,json=betUnits,proto3" json:"bet_units,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,5,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Variants      []*VariantSummary      `protobuf:"bytes,6,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameSummary) Reset() {
+	*x = GameSummary{}
+	mi := &file_problab_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSummary) ProtoMessage() {}
+
+func (x *GameSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSummary.ProtoReflect.Descriptor instead.
+func (*GameSummary) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GameSummary) GetGid() uint64 {
+	if x != nil {
+		return x.Gid
+	}
+	return 0
+}
+
+func (x *GameSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GameSummary) GetLogic() string {
+	if x != nil {
+		return x.Logic
+	}
+	return ""
+}
+
+func (x *GameSummary) GetBetUnits() []int64 {
+	if x != nil {
+		return x.BetUnits
+	}
+	return nil
+}
+
+func (x *GameSummary) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *GameSummary) GetVariants() []*VariantSummary {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type VariantSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variant       string                 `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	Rtp           float64                `protobuf:"fixed64,2,opt,name=rtp,proto3" json:"rtp,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,3,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Default       bool                   `protobuf:"varint,4,opt,name=default,proto3" json:"default,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariantSummary) Reset() {
+	*x = VariantSummary{}
+	mi := &file_problab_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantSummary) ProtoMessage() {}
+
+func (x *VariantSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_problab_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantSummary.ProtoReflect.Descriptor instead.
+func (*VariantSummary) Descriptor() ([]byte, []int) {
+	return file_problab_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VariantSummary) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *VariantSummary) GetRtp() float64 {
+	if x != nil {
+		return x.Rtp
+	}
+	return 0
+}
+
+func (x *VariantSummary) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *VariantSummary) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
+var File_problab_proto protoreflect.FileDescriptor
+
+const file_problab_proto_rawDesc = "" +
+	"\n" +
+	"\rproblab.proto\x12\n" +
+	"problab.v1\"\x89\x03\n" +
+	"\vSpinRequest\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x12\n" +
+	"\x04game\x18\x02 \x01(\tR\x04game\x12\x10\n" +
+	"\x03gid\x18\x03 \x01(\x04R\x03gid\x12\x18\n" +
+	"\avariant\x18\x04 \x01(\tR\avariant\x12\x1a\n" +
+	"\boperator\x18\x05 \x01(\tR\boperator\x12\x10\n" +
+	"\x03bet\x18\x06 \x01(\x03R\x03bet\x12\x19\n" +
+	"\bbet_mode\x18\a \x01(\x03R\abetMode\x12\x19\n" +
+	"\bbet_mult\x18\b \x01(\x03R\abetMult\x12\x14\n" +
+	"\x05cycle\x18\t \x01(\x03R\x05cycle\x12\x16\n" +
+	"\x06choice\x18\n" +
+	" \x01(\x03R\x06choice\x12\x1d\n" +
+	"\n" +
+	"has_choice\x18\v \x01(\bR\thasChoice\x127\n" +
+	"\vstart_state\x18\f \x01(\v2\x16.problab.v1.StartStateR\n" +
+	"startState\x12 \n" +
+	"\vfingerprint\x18\r \x01(\tR\vfingerprint\x12\x1c\n" +
+	"\tverbosity\x18\x0e \x01(\tR\tverbosity\";\n" +
+	"\n" +
+	"StartState\x12\x1d\n" +
+	"\n" +
+	"start_b64u\x18\x01 \x01(\tR\tstartB64u\x12\x0e\n" +
+	"\x02cp\x18\x02 \x01(\fR\x02cp\"\xd2\x02\n" +
+	"\n" +
+	"SpinResult\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\x12\x16\n" +
+	"\x06gameid\x18\x02 \x01(\x04R\x06gameid\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariant\x12\x10\n" +
+	"\x03win\x18\x04 \x01(\x03R\x03win\x12\x10\n" +
+	"\x03bet\x18\x05 \x01(\x03R\x03bet\x12\x18\n" +
+	"\abetmode\x18\x06 \x01(\x03R\abetmode\x12\x18\n" +
+	"\abetmult\x18\a \x01(\x03R\abetmult\x128\n" +
+	"\tgamemodes\x18\b \x03(\v2\x1a.problab.v1.GameModeResultR\tgamemodes\x12\x14\n" +
+	"\x05isend\x18\t \x01(\bR\x05isend\x124\n" +
+	"\n" +
+	"spin_state\x18\n" +
+	" \x01(\v2\x15.problab.v1.SpinStateR\tspinState\x12 \n" +
+	"\vfingerprint\x18\v \x01(\tR\vfingerprint\"Y\n" +
+	"\tSpinState\x12\x1d\n" +
+	"\n" +
+	"start_b64u\x18\x01 \x01(\tR\tstartB64u\x12\x1d\n" +
+	"\n" +
+	"after_b64u\x18\x02 \x01(\tR\tafterB64u\x12\x0e\n" +
+	"\x02cp\x18\x03 \x01(\fR\x02cp\"\x95\x01\n" +
+	"\x0eGameModeResult\x12\x10\n" +
+	"\x03win\x18\x01 \x01(\x03R\x03win\x12\x16\n" +
+	"\x06modeid\x18\x02 \x01(\x03R\x06modeid\x12\x14\n" +
+	"\x05isend\x18\x03 \x01(\bR\x05isend\x12\x18\n" +
+	"\atrigger\x18\x04 \x01(\x03R\atrigger\x12)\n" +
+	"\x04acts\x18\x05 \x03(\v2\x15.problab.v1.ActResultR\x04acts\"\xd7\x03\n" +
+	"\tActResult\x12\x18\n" +
+	"\aacttype\x18\x01 \x01(\tR\aacttype\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05round\x18\x03 \x01(\x03R\x05round\x12\x12\n" +
+	"\x04step\x18\x04 \x01(\x03R\x04step\x12\x10\n" +
+	"\x03act\x18\x05 \x01(\x03R\x03act\x12 \n" +
+	"\fis_round_end\x18\x06 \x01(\bR\n" +
+	"isRoundEnd\x12\x1e\n" +
+	"\vis_step_end\x18\a \x01(\bR\tisStepEnd\x12 \n" +
+	"\vnowtotalwin\x18\b \x01(\x03R\vnowtotalwin\x12 \n" +
+	"\vroundaccwin\x18\t \x01(\x03R\vroundaccwin\x12\x1e\n" +
+	"\n" +
+	"stepaccwin\x18\n" +
+	" \x01(\x03R\n" +
+	"stepaccwin\x12\x16\n" +
+	"\x06actwin\x18\v \x01(\x03R\x06actwin\x12\x18\n" +
+	"\abasewin\x18\f \x01(\x03R\abasewin\x12\x12\n" +
+	"\x04mult\x18\r \x01(\x03R\x04mult\x12\x16\n" +
+	"\x06screen\x18\x0e \x03(\x11R\x06screen\x12\x16\n" +
+	"\x06layout\x18\x0f \x03(\x11R\x06layout\x126\n" +
+	"\adetails\x18\x10 \x03(\v2\x1c.problab.v1.CalcScreenDetailR\adetails\x12\x10\n" +
+	"\x03ext\x18\x11 \x01(\fR\x03ext\"\xc0\x01\n" +
+	"\x10CalcScreenDetail\x12\x10\n" +
+	"\x03win\x18\x01 \x01(\x03R\x03win\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\x11R\x06symbol\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\x03R\x04line\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x03R\x05count\x12\x12\n" +
+	"\x04comb\x18\x05 \x01(\x03R\x04comb\x12\x1c\n" +
+	"\tdirection\x18\x06 \x01(\rR\tdirection\x12\x12\n" +
+	"\x04mult\x18\a \x01(\x03R\x04mult\x12\x12\n" +
+	"\x04hits\x18\b \x03(\x11R\x04hits\"\x0f\n" +
+	"\rHealthRequest\"\xa2\x01\n" +
+	"\x0eHealthResponse\x12\x1c\n" +
+	"\n" +
+	"at_unix_ms\x18\x01 \x01(\x03R\batUnixMs\x12\x1d\n" +
+	"\n" +
+	"runtime_ok\x18\x02 \x01(\bR\truntimeOk\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\aoverall\x18\x04 \x01(\tR\aoverall\x12!\n" +
+	"\fclosed_pools\x18\x05 \x03(\x04R\vclosedPools\"@\n" +
+	"\x12PoolMetricsRequest\x12\x10\n" +
+	"\x03gid\x18\x01 \x01(\x04R\x03gid\x12\x18\n" +
+	"\avariant\x18\x02 \x01(\tR\avariant\"\xd3\x03\n" +
+	"\x13PoolMetricsResponse\x12\x1b\n" +
+	"\tgame_name\x18\x01 \x01(\tR\bgameName\x12\x17\n" +
+	"\agame_id\x18\x02 \x01(\x04R\x06gameId\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariant\x12\x1b\n" +
+	"\tpool_size\x18\x04 \x01(\x03R\bpoolSize\x12\x1c\n" +
+	"\tavailable\x18\x05 \x01(\x03R\tavailable\x12\x1a\n" +
+	"\binflight\x18\x06 \x01(\x03R\binflight\x12%\n" +
+	"\x0ebroken_backlog\x18\a \x01(\x03R\rbrokenBacklog\x12\x18\n" +
+	"\arebuild\x18\b \x01(\x03R\arebuild\x12\x16\n" +
+	"\x06panics\x18\t \x01(\x03R\x06panics\x12\x16\n" +
+	"\x06fatals\x18\n" +
+	" \x01(\x03R\x06fatals\x12\x16\n" +
+	"\x06closed\x18\v \x01(\bR\x06closed\x12!\n" +
+	"\fclose_reason\x18\f \x01(\tR\vcloseReason\x12%\n" +
+	"\x0eclose_inflight\x18\r \x01(\x03R\rcloseInflight\x12\x1f\n" +
+	"\vclose_avail\x18\x0e \x01(\x03R\n" +
+	"closeAvail\x12!\n" +
+	"\fclose_broken\x18\x0f \x01(\x03R\vcloseBroken\"\x12\n" +
+	"\x10ListGamesRequest\"B\n" +
+	"\x11ListGamesResponse\x12-\n" +
+	"\x05games\x18\x01 \x03(\v2\x17.problab.v1.GameSummaryR\x05games\"\xc0\x01\n" +
+	"\vGameSummary\x12\x10\n" +
+	"\x03gid\x18\x01 \x01(\x04R\x03gid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05logic\x18\x03 \x01(\tR\x05logic\x12\x1b\n" +
+	"\tbet_units\x18\x04 \x03(\x03R\bbetUnits\x12 \n" +
+	"\vfingerprint\x18\x05 \x01(\tR\vfingerprint\x126\n" +
+	"\bvariants\x18\x06 \x03(\v2\x1a.problab.v1.VariantSummaryR\bvariants\"x\n" +
+	"\x0eVariantSummary\x12\x18\n" +
+	"\avariant\x18\x01 \x01(\tR\avariant\x12\x10\n" +
+	"\x03rtp\x18\x02 \x01(\x01R\x03rtp\x12 \n" +
+	"\vfingerprint\x18\x03 \x01(\tR\vfingerprint\x12\x18\n" +
+	"\adefault\x18\x04 \x01(\bR\adefault2\xa1\x02\n" +
+	"\vSlotRuntime\x127\n" +
+	"\x04Spin\x12\x17.problab.v1.SpinRequest\x1a\x16.problab.v1.SpinResult\x12?\n" +
+	"\x06Health\x12\x19.problab.v1.HealthRequest\x1a\x1a.problab.v1.HealthResponse\x12N\n" +
+	"\vPoolMetrics\x12\x1e.problab.v1.PoolMetricsRequest\x1a\x1f.problab.v1.PoolMetricsResponse\x12H\n" +
+	"\tListGames\x12\x1c.problab.v1.ListGamesRequest\x1a\x1d.problab.v1.ListGamesResponseB5Z3github.com/zintix-labs/problab/server/rpc/problabv1b\x06proto3"
+
+var (
+	file_problab_proto_rawDescOnce sync.Once
+	file_problab_proto_rawDescData []byte
+)
+
+func file_problab_proto_rawDescGZIP() []byte {
+	file_problab_proto_rawDescOnce.Do(func() {
+		file_problab_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_problab_proto_rawDesc), len(file_problab_proto_rawDesc)))
+	})
+	return file_problab_proto_rawDescData
+}
+
+var file_problab_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_problab_proto_goTypes = []any{
+	(*SpinRequest)(nil),         // 0: problab.v1.SpinRequest
+	(*StartState)(nil),          // 1: problab.v1.StartState
+	(*SpinResult)(nil),          // 2: problab.v1.SpinResult
+	(*SpinState)(nil),           // 3: problab.v1.SpinState
+	(*GameModeResult)(nil),      // 4: problab.v1.GameModeResult
+	(*ActResult)(nil),           // 5: problab.v1.ActResult
+	(*CalcScreenDetail)(nil),    // 6: problab.v1.CalcScreenDetail
+	(*HealthRequest)(nil),       // 7: problab.v1.HealthRequest
+	(*HealthResponse)(nil),      // 8: problab.v1.HealthResponse
+	(*PoolMetricsRequest)(nil),  // 9: problab.v1.PoolMetricsRequest
+	(*PoolMetricsResponse)(nil), // 10: problab.v1.PoolMetricsResponse
+	(*ListGamesRequest)(nil),    // 11: problab.v1.ListGamesRequest
+	(*ListGamesResponse)(nil),   // 12: problab.v1.ListGamesResponse
+	(*GameSummary)(nil),         // 13: problab.v1.GameSummary
+	(*VariantSummary)(nil),      // 14: problab.v1.VariantSummary
+}
+var file_problab_proto_depIdxs = []int32{
+	1,  // 0: problab.v1.SpinRequest.start_state:type_name -> problab.v1.StartState
+	4,  // 1: problab.v1.SpinResult.gamemodes:type_name -> problab.v1.GameModeResult
+	3,  // 2: problab.v1.SpinResult.spin_state:type_name -> problab.v1.SpinState
+	5,  // 3: problab.v1.GameModeResult.acts:type_name -> problab.v1.ActResult
+	6,  // 4: problab.v1.ActResult.details:type_name -> problab.v1.CalcScreenDetail
+	13, // 5: problab.v1.ListGamesResponse.games:type_name -> problab.v1.GameSummary
+	14, // 6: problab.v1.GameSummary.variants:type_name -> problab.v1.VariantSummary
+	0,  // 7: problab.v1.SlotRuntime.Spin:input_type -> problab.v1.SpinRequest
+	7,  // 8: problab.v1.SlotRuntime.Health:input_type -> problab.v1.HealthRequest
+	9,  // 9: problab.v1.SlotRuntime.PoolMetrics:input_type -> problab.v1.PoolMetricsRequest
+	11, // 10: problab.v1.SlotRuntime.ListGames:input_type -> problab.v1.ListGamesRequest
+	2,  // 11: problab.v1.SlotRuntime.Spin:output_type -> problab.v1.SpinResult
+	8,  // 12: problab.v1.SlotRuntime.Health:output_type -> problab.v1.HealthResponse
+	10, // 13: problab.v1.SlotRuntime.PoolMetrics:output_type -> problab.v1.PoolMetricsResponse
+	12, // 14: problab.v1.SlotRuntime.ListGames:output_type -> problab.v1.ListGamesResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_problab_proto_init() }
+func file_problab_proto_init() {
+	if File_problab_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_problab_proto_rawDesc), len(file_problab_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_problab_proto_goTypes,
+		DependencyIndexes: file_problab_proto_depIdxs,
+		MessageInfos:      file_problab_proto_msgTypes,
+	}.Build()
+	File_problab_proto = out.File
+	file_problab_proto_goTypes = nil
+	file_problab_proto_depIdxs = nil
+}
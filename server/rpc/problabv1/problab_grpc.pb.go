@@ -0,0 +1,264 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// problab gRPC 介面：與 HTTP /v1/spin、/v1/health、/v1/poolmetrics 相同的語意，
+// 欄位對應 dto.SpinRequest / dto.SpinResult（見 server/rpc/problabv1/convert.go）。
+//
+// 重新產生：
+//
+//	cd server/rpc/problabv1 && buf generate
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: problab.proto
+
+package problabv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SlotRuntime_Spin_FullMethodName        = "/problab.v1.SlotRuntime/Spin"
+	SlotRuntime_Health_FullMethodName      = "/problab.v1.SlotRuntime/Health"
+	SlotRuntime_PoolMetrics_FullMethodName = "/problab.v1.SlotRuntime/PoolMetrics"
+	SlotRuntime_ListGames_FullMethodName   = "/problab.v1.SlotRuntime/ListGames"
+)
+
+// SlotRuntimeClient is the client API for SlotRuntime service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SlotRuntimeClient interface {
+	// Spin 執行一次 spin；deadline 會傳入 SlotRuntime.Spin 的 context。
+	Spin(ctx context.Context, in *SpinRequest, opts ...grpc.CallOption) (*SpinResult, error)
+	// Health 回傳 runtime 健康快照。
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	// PoolMetrics 回傳指定遊戲（變體）的 pool 觀測快照。
+	PoolMetrics(ctx context.Context, in *PoolMetricsRequest, opts ...grpc.CallOption) (*PoolMetricsResponse, error)
+	// ListGames 列出所有遊戲與變體（Problab.Summary）。
+	ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error)
+}
+
+type slotRuntimeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSlotRuntimeClient(cc grpc.ClientConnInterface) SlotRuntimeClient {
+	return &slotRuntimeClient{cc}
+}
+
+func (c *slotRuntimeClient) Spin(ctx context.Context, in *SpinRequest, opts ...grpc.CallOption) (*SpinResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpinResult)
+	err := c.cc.Invoke(ctx, SlotRuntime_Spin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slotRuntimeClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, SlotRuntime_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slotRuntimeClient) PoolMetrics(ctx context.Context, in *PoolMetricsRequest, opts ...grpc.CallOption) (*PoolMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PoolMetricsResponse)
+	err := c.cc.Invoke(ctx, SlotRuntime_PoolMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slotRuntimeClient) ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGamesResponse)
+	err := c.cc.Invoke(ctx, SlotRuntime_ListGames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SlotRuntimeServer is the server API for SlotRuntime service.
+// All implementations must embed UnimplementedSlotRuntimeServer
+// for forward compatibility.
+type SlotRuntimeServer interface {
+	// Spin 執行一次 spin；deadline 會傳入 SlotRuntime.Spin 的 context。
+	Spin(context.Context, *SpinRequest) (*SpinResult, error)
+	// Health 回傳 runtime 健康快照。
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	// PoolMetrics 回傳指定遊戲（變體）的 pool 觀測快照。
+	PoolMetrics(context.Context, *PoolMetricsRequest) (*PoolMetricsResponse, error)
+	// ListGames 列出所有遊戲與變體（Problab.Summary）。
+	ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error)
+	mustEmbedUnimplementedSlotRuntimeServer()
+}
+
+// UnimplementedSlotRuntimeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSlotRuntimeServer struct{}
+
+func (UnimplementedSlotRuntimeServer) Spin(context.Context, *SpinRequest) (*SpinResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method Spin not implemented")
+}
+func (UnimplementedSlotRuntimeServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedSlotRuntimeServer) PoolMetrics(context.Context, *PoolMetricsRequest) (*PoolMetricsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PoolMetrics not implemented")
+}
+func (UnimplementedSlotRuntimeServer) ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedSlotRuntimeServer) mustEmbedUnimplementedSlotRuntimeServer() {}
+func (UnimplementedSlotRuntimeServer) testEmbeddedByValue()                     {}
+
+// UnsafeSlotRuntimeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SlotRuntimeServer will
+// result in compilation errors.
+type UnsafeSlotRuntimeServer interface {
+	mustEmbedUnimplementedSlotRuntimeServer()
+}
+
+func RegisterSlotRuntimeServer(s grpc.ServiceRegistrar, srv SlotRuntimeServer) {
+	// If the following call panics, it indicates UnimplementedSlotRuntimeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SlotRuntime_ServiceDesc, srv)
+}
+
+func _SlotRuntime_Spin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotRuntimeServer).Spin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotRuntime_Spin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotRuntimeServer).Spin(ctx, req.(*SpinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlotRuntime_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotRuntimeServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotRuntime_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotRuntimeServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlotRuntime_PoolMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotRuntimeServer).PoolMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotRuntime_PoolMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotRuntimeServer).PoolMetrics(ctx, req.(*PoolMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlotRuntime_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotRuntimeServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotRuntime_ListGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotRuntimeServer).ListGames(ctx, req.(*ListGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SlotRuntime_ServiceDesc is the grpc.ServiceDesc for SlotRuntime service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SlotRuntime_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "problab.v1.SlotRuntime",
+	HandlerType: (*SlotRuntimeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Spin",
+			Handler:    _SlotRuntime_Spin_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _SlotRuntime_Health_Handler,
+		},
+		{
+			MethodName: "PoolMetrics",
+			Handler:    _SlotRuntime_PoolMetrics_Handler,
+		},
+		{
+			MethodName: "ListGames",
+			Handler:    _SlotRuntime_ListGames_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "problab.proto",
+}
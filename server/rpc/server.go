@@ -0,0 +1,104 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpc 以 gRPC 對外提供 SlotRuntime（介面定義見 problabv1/problab.proto）。
+//
+// Server 實作 app.Component，可與 HTTP server 一起交給 app.App 管理生命週期：
+//
+//	rs, err := rpc.NewServer(":5809", sCfg, rt) // rt 與 HTTP handler 共用
+//	app.NewWith(httpSvr, rs).Run()
+//
+// Go 客戶端見 server/rpc/client。
+package rpc
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/server/app"
+	"github.com/zintix-labs/problab/server/rpc/problabv1"
+	"github.com/zintix-labs/problab/server/svrcfg"
+	"google.golang.org/grpc"
+)
+
+// Server gRPC 服務（實作 app.Component）
+type Server struct {
+	addr string
+	srv  *grpc.Server
+
+	mu  sync.Mutex
+	lis net.Listener
+}
+
+// NewServer 建立監聽 addr 的 gRPC Server 並註冊 SlotRuntime 服務
+func NewServer(addr string, sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime, opts ...grpc.ServerOption) (*Server, error) {
+	if addr == "" {
+		return nil, errs.NewFatal("rpc addr is required")
+	}
+	svc, err := NewService(sCfg, rt)
+	if err != nil {
+		return nil, err
+	}
+	srv := grpc.NewServer(opts...)
+	problabv1.RegisterSlotRuntimeServer(srv, svc)
+	return &Server{addr: addr, srv: srv}, nil
+}
+
+// Run 開始監聽並服務，直到 Shutdown
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return errs.Wrap(err, "rpc listen failed")
+	}
+	return s.Serve(lis)
+}
+
+// Serve 在指定 listener 上服務（測試或自訂 listener 使用）
+func (s *Server) Serve(lis net.Listener) error {
+	s.mu.Lock()
+	s.lis = lis
+	s.mu.Unlock()
+	return s.srv.Serve(lis)
+}
+
+// Shutdown 優雅關閉：等待進行中的 RPC 完成，ctx 到期則強制關閉
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// Address 回傳監聽位址（已開始監聽時為實際位址）
+func (s *Server) Address() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lis != nil {
+		return s.lis.Addr().String()
+	}
+	return s.addr
+}
+
+var _ app.Component = (*Server)(nil)
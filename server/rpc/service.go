@@ -0,0 +1,101 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/server/rpc/problabv1"
+	"github.com/zintix-labs/problab/server/svrcfg"
+	"github.com/zintix-labs/problab/spec"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSpinTimeout 客戶端未帶 deadline 時的 spin 超時（同 HTTP handler）
+const defaultSpinTimeout = 5 * time.Second
+
+// Service 以 gRPC 提供 SlotRuntime（Spin / Health / PoolMetrics / ListGames）
+type Service struct {
+	problabv1.UnimplementedSlotRuntimeServer
+
+	lab *problab.Problab
+	rt  *problab.SlotRuntime
+	log *slog.Logger
+}
+
+// NewService 建立 Service；rt 與 HTTP SpinHandler 共用同一份 SlotRuntime
+func NewService(sCfg *svrcfg.SvrCfg, rt *problab.SlotRuntime) (*Service, error) {
+	if rt == nil {
+		return nil, errs.NewFatal("build rpc service error: slot runtime is required")
+	}
+	return &Service{lab: sCfg.Problab, rt: rt, log: sCfg.Log}, nil
+}
+
+// Spin 執行一次 spin
+//
+// 客戶端的 deadline 會隨 ctx 傳入 SlotRuntime.Spin；未帶 deadline 時套用 defaultSpinTimeout。
+func (s *Service) Spin(ctx context.Context, in *problabv1.SpinRequest) (*problabv1.SpinResult, error) {
+	req, err := problabv1.SpinRequestToDTO(in)
+	if err != nil {
+		return nil, Status(err)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSpinTimeout)
+		defer cancel()
+	}
+	result, err := s.rt.Spin(ctx, req)
+	if err != nil {
+		Log(s.log, "rpc spin failed", err)
+		return nil, Status(err)
+	}
+	out, err := problabv1.SpinResultFromDTO(&result)
+	if err != nil {
+		Log(s.log, "rpc spin encode failed", err)
+		return nil, Status(err)
+	}
+	return out, nil
+}
+
+// Health 回傳 runtime 健康快照
+func (s *Service) Health(ctx context.Context, _ *problabv1.HealthRequest) (*problabv1.HealthResponse, error) {
+	return problabv1.HealthFromRuntime(s.rt.Health()), nil
+}
+
+// PoolMetrics 回傳指定遊戲（變體）的 pool 觀測快照；不存在時回傳 NotFound
+func (s *Service) PoolMetrics(ctx context.Context, in *problabv1.PoolMetricsRequest) (*problabv1.PoolMetricsResponse, error) {
+	m, ok := s.rt.VariantPoolMetrics(spec.GID(in.GetGid()), in.GetVariant())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "gid/variant is not exist: %d %s", in.GetGid(), in.GetVariant())
+	}
+	return problabv1.PoolMetricsFromRuntime(m), nil
+}
+
+// ListGames 列出所有遊戲與變體
+func (s *Service) ListGames(ctx context.Context, _ *problabv1.ListGamesRequest) (*problabv1.ListGamesResponse, error) {
+	sum, err := s.lab.Summary()
+	if err != nil {
+		Log(s.log, "rpc list games failed", err)
+		return nil, Status(err)
+	}
+	return problabv1.GamesFromCatalog(sum), nil
+}
+
+var _ problabv1.SlotRuntimeServer = (*Service)(nil)
@@ -0,0 +1,79 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/zintix-labs/problab/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code 將錯誤映射成 gRPC status code。
+//
+// 規則（與 httperr.StatusCode 對齊）：
+//   - ctx timeout/cancel → DeadlineExceeded / Canceled
+//   - errs.Warn         → InvalidArgument（請求/參數問題）
+//   - errs.Fatal        → Internal（系統/不可恢復問題）
+//   - 已是 gRPC status 的錯誤沿用其 code
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+	var e *errs.E
+	if errors.As(err, &e) {
+		if e.ErrLv == errs.Warn {
+			return codes.InvalidArgument
+		}
+		return codes.Internal
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	return codes.Internal
+}
+
+// Status 把錯誤轉成 gRPC status error（nil 回傳 nil）
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+	return status.Error(Code(err), err.Error())
+}
+
+// Log 依 status code 記錄錯誤：取消/超時記 Warn，伺服器端錯誤記 Error，請求錯誤不記
+func Log(log *slog.Logger, msg string, err error) {
+	if err == nil {
+		return
+	}
+	switch Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted:
+		log.Warn(msg, slog.Any("err", err))
+	case codes.Internal, codes.Unavailable, codes.Unknown, codes.DataLoss:
+		log.Error(msg, slog.Any("err", err))
+	}
+}
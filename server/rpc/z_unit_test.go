@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/demo/demo_configs"
+	"github.com/zintix-labs/problab/demo/demo_logic"
+	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/server/logger"
+	"github.com/zintix-labs/problab/server/rpc/client"
+	"github.com/zintix-labs/problab/server/svrcfg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) (*client.Client, *problab.SlotRuntime) {
+	t.Helper()
+	lab, err := problab.NewAuto(core.Default(), problab.Configs(demo_configs.FS), problab.Logics(demo_logic.Logics))
+	if err != nil {
+		t.Fatalf("new problab failed: %v", err)
+	}
+	log, _ := logger.NewAsync(64, logger.ModeSilence)
+	sCfg := &svrcfg.SvrCfg{Log: log, SlotBufSize: 1, Problab: lab}
+	rt, err := lab.BuildRuntime(1)
+	if err != nil {
+		t.Fatalf("build runtime failed: %v", err)
+	}
+	t.Cleanup(rt.Close)
+	rs, err := NewServer(":0", sCfg, rt)
+	if err != nil {
+		t.Fatalf("new server failed: %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = rs.Serve(lis) }()
+	t.Cleanup(func() { _ = rs.Shutdown(context.Background()) })
+
+	c, err := client.Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c, rt
+}
+
+func TestRPCSpinMatchesRuntime(t *testing.T) {
+	c, rt := newTestClient(t)
+	ctx := context.Background()
+
+	games, err := c.ListGames(ctx)
+	if err != nil || len(games) == 0 {
+		t.Fatalf("list games: %v %v", games, err)
+	}
+	g := games[0]
+	req := &dto.SpinRequest{GameName: g.Name, GameId: g.GID, Bet: g.BetUnits[0], BetMult: 1}
+
+	// 以同一個 start 回放，gRPC 與直接呼叫 runtime 的結果必須一致
+	first, err := c.Spin(ctx, req)
+	if err != nil {
+		t.Fatalf("spin: %v", err)
+	}
+	req.StartState = &dto.StartState{StartCoreSnapB64U: first.State.StartCoreSnapB64U}
+	got, err := c.Spin(ctx, req)
+	if err != nil {
+		t.Fatalf("replay spin: %v", err)
+	}
+	want, err := rt.Spin(ctx, req)
+	if err != nil {
+		t.Fatalf("runtime spin: %v", err)
+	}
+	gj, _ := json.Marshal(got)
+	wj, _ := json.Marshal(want)
+	if string(gj) != string(wj) {
+		t.Fatalf("rpc result differs from runtime:\n rpc %s\n rt  %s", gj, wj)
+	}
+
+	req.Verbosity = dto.VerbositySummary
+	sum, err := c.Spin(ctx, req)
+	if err != nil || sum.TotalWin != want.TotalWin || len(sum.GameModes) == 0 || sum.GameModes[0].ActResults != nil {
+		t.Fatalf("summary spin: %+v %v", sum, err)
+	}
+	req.Verbosity = "all"
+	if _, err := c.Spin(ctx, req); err == nil {
+		t.Fatalf("expected invalid verbosity error")
+	}
+
+	h, err := c.Health(ctx)
+	if err != nil || !h.RuntimeOK {
+		t.Fatalf("health: %+v %v", h, err)
+	}
+	m, err := c.PoolMetrics(ctx, g.GID, "")
+	if err != nil || m.GameID != g.GID || m.PoolSize != 1 {
+		t.Fatalf("pool metrics: %+v %v", m, err)
+	}
+}
+
+func TestRPCErrors(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	// 請求錯誤 → errs.Warn
+	_, err := c.Spin(context.Background(), &dto.SpinRequest{GameId: 999, Bet: 1, BetMult: 1})
+	if e, ok := errs.AsErr(err); !ok || e.ErrLv != errs.Warn {
+		t.Fatalf("unknown game: expected warn, got %v", err)
+	}
+	_, err = c.Spin(context.Background(), &dto.SpinRequest{Bet: 1, BetMult: 1, Choice: 2})
+	if e, ok := errs.AsErr(err); !ok || e.ErrLv != errs.Warn {
+		t.Fatalf("choice contract: expected warn, got %v", err)
+	}
+	if _, err := c.PoolMetrics(context.Background(), 999, ""); err == nil {
+		t.Fatalf("expected not found")
+	}
+
+	// 已過期的 deadline 會傳到伺服器端
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := c.Spin(ctx, &dto.SpinRequest{Bet: 1, BetMult: 1}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{nil, codes.OK},
+		{errs.NewWarn("bad"), codes.InvalidArgument},
+		{errs.NewFatal("boom"), codes.Internal},
+		{errs.Wrap(context.DeadlineExceeded, "spin"), codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{errors.New("plain"), codes.Internal},
+	}
+	for _, c := range cases {
+		if got := Code(c.err); got != c.want {
+			t.Fatalf("Code(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -15,14 +15,18 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"sync"
 
+	"github.com/zintix-labs/problab"
 	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/server/api"
 	"github.com/zintix-labs/problab/server/app"
 	"github.com/zintix-labs/problab/server/netsvr"
+	"github.com/zintix-labs/problab/server/rpc"
 	"github.com/zintix-labs/problab/server/svrcfg"
 )
 
@@ -57,14 +61,14 @@ func Run(sCfg *svrcfg.SvrCfg) {
 	// register svr = problab
 	sCfg.Log = sCfg.Log.With("svr", "problab")
 
-	// Register routes
-	if err := api.RegisterRoutes(svr, sCfg); err != nil {
-		sCfg.Log.Error("register route error:" + err.Error())
+	// Register routes / rpc
+	app, err := assemble(svr, sCfg)
+	if err != nil {
+		sCfg.Log.Error(err.Error())
 		return
 	}
 
 	// run
-	app := app.NewWith(svr)
 	sCfg.Log.Info("[problab] listening on http://localhost" + svr.Address())
 	if err := app.Run(); err != nil {
 		sCfg.Log.Error("app stopped:", slog.Any("err", err))
@@ -94,16 +98,69 @@ func RunWithSvr(sCfg *svrcfg.SvrCfg, svr netsvr.NetSvr) {
 		}
 	}
 
-	// Register routes
-	if err := api.RegisterRoutes(svr, sCfg); err != nil {
-		sCfg.Log.Error("register route error:" + err.Error())
+	// Register routes / rpc
+	app, err := assemble(svr, sCfg)
+	if err != nil {
+		sCfg.Log.Error(err.Error())
 		return
 	}
 
 	// 運行
-	app := app.NewWith(svr)
 	sCfg.Log.Info("[problab] listening")
 	if err := app.Run(); err != nil {
 		sCfg.Log.Error("app stopped:", slog.Any("err", err))
 	}
 }
+
+// assemble 建立共用的 SlotRuntime，掛上 HTTP 路由與（可選的）gRPC 服務，組成 app。
+//
+// HTTP 與 gRPC 使用同一份 runtime（同一組 machine pool），runtime 在 app 關閉時最後關閉。
+func assemble(svr netsvr.NetSvr, sCfg *svrcfg.SvrCfg) (*app.App, error) {
+	rt, err := sCfg.Problab.BuildRuntime(sCfg.SlotBufSize)
+	if err != nil {
+		return nil, errs.Wrap(err, "build runtime error")
+	}
+	if err := api.RegisterRoutes(svr, sCfg, rt); err != nil {
+		rt.Close()
+		return nil, errs.Wrap(err, "register route error")
+	}
+	a := app.NewWith(svr)
+	if sCfg.GRPCAddr != "" {
+		rs, err := rpc.NewServer(sCfg.GRPCAddr, sCfg, rt)
+		if err != nil {
+			rt.Close()
+			return nil, errs.Wrap(err, "register rpc error")
+		}
+		a.Register(rs)
+		sCfg.Log.Info("[problab] grpc listening on " + rs.Address())
+	}
+	// 最後註冊：關閉時在所有服務停止後才關閉 runtime
+	a.Register(newRuntimeComponent(rt))
+	return a, nil
+}
+
+// runtimeComponent 讓共用的 SlotRuntime 納入 app 生命週期（Shutdown 時關閉）
+type runtimeComponent struct {
+	rt   *problab.SlotRuntime
+	stop chan struct{}
+	once sync.Once
+}
+
+func newRuntimeComponent(rt *problab.SlotRuntime) *runtimeComponent {
+	return &runtimeComponent{rt: rt, stop: make(chan struct{})}
+}
+
+// Run 阻塞到 Shutdown
+func (c *runtimeComponent) Run() error {
+	<-c.stop
+	return nil
+}
+
+// Shutdown 關閉 runtime
+func (c *runtimeComponent) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		c.rt.Close()
+		close(c.stop)
+	})
+	return nil
+}
@@ -62,6 +62,8 @@ type SvrCfg struct {
 	SlotBufSize int
 	Problab     *problab.Problab
 	Mode        RunMode
+	// GRPCAddr gRPC 服務監聽位址（例如 ":5809"），空字串表示不啟用 gRPC。
+	GRPCAddr string
 }
 
 func (sc *SvrCfg) Vaild() error {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/cheggaaa/pb/v3"
 	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/par"
 	"github.com/zintix-labs/problab/recorder"
 	"github.com/zintix-labs/problab/sdk/core"
 	"github.com/zintix-labs/problab/sdk/slot"
@@ -46,20 +47,21 @@ type Simulator struct {
 	initSeed  int64                    // 初始下的種子
 	seedmaker *SeedMaker               // 種子生成器
 	optimalFS fs.FS                    // 優化文件系統（可選）
+	fp        string                   // 設定檔指紋（蓋在統計報表上）
 	mBuf      []*Machine               // 併發執行機台實例
 	rBuf      []*recorder.SpinRecorder // 併發遊戲紀錄員
 	sBuf      []*stats.StatReport      // 併發統計結果報表(僅Players需要)
 }
 
-func newSimulator(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, optimalFS fs.FS) (*Simulator, error) {
+func newSimulator(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, optimalFS fs.FS, fp string) (*Simulator, error) {
 	seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 	if err != nil {
 		return nil, err
 	}
-	return newSimulatorWithSeed(gs, reg, cf, seed.Int64(), optimalFS)
+	return newSimulatorWithSeed(gs, reg, cf, seed.Int64(), optimalFS, fp)
 }
 
-func newSimulatorWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, optimalFS fs.FS) (*Simulator, error) {
+func newSimulatorWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core.PRNGFactory, seed int64, optimalFS fs.FS, fp string) (*Simulator, error) {
 	s := &Simulator{
 		GameName:  gs.GameName,
 		GameId:    gs.GameID,
@@ -70,11 +72,12 @@ func newSimulatorWithSeed(gs *spec.GameSetting, reg *slot.LogicRegistry, cf core
 		initSeed:  seed,
 		seedmaker: NewSeedMaker(seed),
 		optimalFS: optimalFS,
+		fp:        fp,
 		mBuf:      make([]*Machine, 1, capPrepare),
 		rBuf:      make([]*recorder.SpinRecorder, 0, capPrepare),
 		sBuf:      make([]*stats.StatReport, 0, capPrepare),
 	}
-	m, err := newMachineWithSeed(gs, reg, cf, s.initSeed, true, optimalFS)
+	m, err := newMachineWithSeed(gs, reg, cf, s.initSeed, true, optimalFS, fp)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +99,7 @@ func (s *Simulator) Sim(betMode int, round int, showpb bool) (*stats.StatReport,
 		if err != nil {
 			return nil, 0, err
 		}
+		r.Fingerprint = s.fp
 		s.rBuf = append(s.rBuf, r)
 	}
 	r := s.rBuf[0]
@@ -119,9 +123,57 @@ func (s *Simulator) Sim(betMode int, round int, showpb bool) (*stats.StatReport,
 	return result, used, nil
 }
 
+// SimRecord 單線模擬器：以一台機台連續跑指定 round，回傳尚未 Done 的紀錄員
+//
+// 供分散式模擬使用：各分片的紀錄員序列化後，由協調者以 recorder.MergeSpinRecorder 合併。
+func (s *Simulator) SimRecord(betMode int, round int) (*recorder.SpinRecorder, error) {
+	defer s.reset()
+	if betMode < 0 || betMode >= len(s.gs.BetUnits) {
+		return nil, errs.NewWarn("bet mode err: must >= 0 and < len(betunits)")
+	}
+	if round < 1 {
+		return nil, errs.NewWarn("round must > 0")
+	}
+	r, err := recorder.NewSpinRecorder(s.GameName, s.GameId, s.gs.BetUnits, s.initBets, betMode)
+	if err != nil {
+		return nil, err
+	}
+	r.Fingerprint = s.fp
+	m := s.mBuf[0]
+	for i := 0; i < round; i++ {
+		sr := m.SpinInternal(betMode)
+		r.Record(sr)
+	}
+	return r, nil
+}
+
 // SimMP 平行執行多個機台，總計 rounds*mp 次 spin，合併統計結果後 回傳統計結果與用時
 func (s *Simulator) SimMP(betMode int, rounds int, mp int, showpb bool) (*stats.StatReport, time.Duration, error) {
 	defer s.reset()
+	return s.simMP(betMode, rounds, mp, showpb, nil)
+}
+
+// SimPAR 與 SimMP 相同，並同時收集 PAR 表所需的得分組合與模式進入統計
+func (s *Simulator) SimPAR(betMode int, rounds int, mp int, showpb bool) (*stats.StatReport, *par.Collector, time.Duration, error) {
+	defer s.reset()
+	cols := make([]*par.Collector, max(mp, 0))
+	for i := range cols {
+		cols[i] = par.NewCollector(len(s.gs.GameModeSettings))
+	}
+	result, used, err := s.simMP(betMode, rounds, mp, showpb, cols)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for _, c := range cols[1:] {
+		if err := cols[0].Merge(c); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	return result, cols[0], used, nil
+}
+
+// simMP SimMP / SimPAR 共用的平行迴圈，cols 非 nil 時每個 worker 另外以 cols[i] 收集 PAR 統計
+func (s *Simulator) simMP(betMode int, rounds int, mp int, showpb bool, cols []*par.Collector) (*stats.StatReport, time.Duration, error) {
 	if mp <= 0 {
 		return nil, 0, errs.NewWarn("workers must > 0")
 	}
@@ -132,7 +184,7 @@ func (s *Simulator) SimMP(betMode int, rounds int, mp int, showpb bool) (*stats.
 		return nil, 0, errs.NewWarn("round must > 0")
 	}
 	for len(s.mBuf) < mp {
-		m, err := newMachineWithSeed(s.gs, s.logic, s.cf, s.seedmaker.Next(), true, s.optimalFS)
+		m, err := newMachineWithSeed(s.gs, s.logic, s.cf, s.seedmaker.Next(), true, s.optimalFS, s.fp)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -144,6 +196,7 @@ func (s *Simulator) SimMP(betMode int, rounds int, mp int, showpb bool) (*stats.
 		if err != nil {
 			return nil, 0, err
 		}
+		r.Fingerprint = s.fp
 		s.rBuf = append(s.rBuf, r)
 	}
 
@@ -159,9 +212,16 @@ func (s *Simulator) SimMP(betMode int, rounds int, mp int, showpb bool) (*stats.
 			defer wg.Done()
 			g := s.mBuf[i]
 			st := s.rBuf[i]
+			var col *par.Collector
+			if cols != nil {
+				col = cols[i]
+			}
 			for r := 0; r < rounds; r++ {
 				sr := g.SpinInternal(betMode)
 				st.Record(sr)
+				if col != nil {
+					col.Record(sr)
+				}
 				bar.Increment()
 			}
 		}(i)
@@ -170,7 +230,10 @@ func (s *Simulator) SimMP(betMode int, rounds int, mp int, showpb bool) (*stats.
 	used := time.Since(bar.StartTime())
 	bar.Finish()
 
-	st, _ := recorder.MergeSpinRecorder(s.rBuf)
+	st, err := recorder.MergeSpinRecorder(s.rBuf[:mp])
+	if err != nil {
+		return nil, 0, err
+	}
 	result := st.Done()
 	result.Done()
 
@@ -187,7 +250,7 @@ func (s *Simulator) SimPlayers(mp int, players int, initBets int, betMode int, r
 
 	// 	準備並行機台
 	for len(s.mBuf) < mp {
-		m, err := newMachineWithSeed(s.gs, s.logic, s.cf, s.seedmaker.Next(), true, s.optimalFS)
+		m, err := newMachineWithSeed(s.gs, s.logic, s.cf, s.seedmaker.Next(), true, s.optimalFS, s.fp)
 		if err != nil {
 			return nil, nil, 0, err
 		}
@@ -201,6 +264,7 @@ func (s *Simulator) SimPlayers(mp int, players int, initBets int, betMode int, r
 		if err != nil {
 			return nil, nil, 0, err
 		}
+		r.Fingerprint = s.fp
 		s.rBuf = append(s.rBuf, r)
 	}
 	// 作一個2048大小的緩衝channel 使player依序處理
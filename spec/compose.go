@@ -0,0 +1,418 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/zintix-labs/problab/errs"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigLoader 依名稱讀取其他設定檔（供 includes 使用）；名稱為相對於設定來源根目錄的路徑
+type ConfigLoader func(name string) ([]byte, error)
+
+// FSLoader 以 fs.FS 作為 includes 的讀取來源
+func FSLoader(fsys fs.FS) ConfigLoader {
+	return func(name string) ([]byte, error) { return fs.ReadFile(fsys, name) }
+}
+
+// ConfigLibrary 可重複使用的具名區塊，各模式以名稱引用：
+//   - reel_set_group 中的 {ref: name}（weight 未填時沿用區塊的 weight）
+//   - symbol_setting.pay_table_ref
+//   - hit_setting.line_table_ref
+//
+// 區塊可寫在本檔的 library，也可透過 includes 從同一來源的其他檔案引入（同名重複定義視為錯誤）。
+type ConfigLibrary struct {
+	ReelSets   map[string]ReelSet   `yaml:"reel_sets,omitempty"   json:"reel_sets,omitempty"`
+	PayTables  map[string][][]int   `yaml:"pay_tables,omitempty"  json:"pay_tables,omitempty"`
+	LineTables map[string][][]int16 `yaml:"line_tables,omitempty" json:"line_tables,omitempty"`
+}
+
+// libraryKeys 只含這些頂層欄位的檔案為共用區塊檔，不是遊戲設定
+var libraryKeys = map[string]bool{"includes": true, "library": true}
+
+// IsLibraryConfig 回傳內容是否為共用區塊檔（頂層只有 includes / library）
+func IsLibraryConfig(data []byte) bool {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return false
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode || len(root.Content) == 0 {
+		return false
+	}
+	for i := 0; i < len(root.Content); i += 2 {
+		if !libraryKeys[root.Content[i].Value] {
+			return false
+		}
+	}
+	return true
+}
+
+// composeErr 組合設定時的錯誤，保留節點位置供 ValidateYAML 回報行號
+type composeErr struct {
+	ci ConfigIssue
+}
+
+func (e *composeErr) Error() string { return e.ci.String() }
+
+func newComposeErr(n *yaml.Node, p string, format string, a ...any) error {
+	return &composeErr{ci: ConfigIssue{Line: n.Line, Column: n.Column, Path: p, Level: errs.Fatal, Message: fmt.Sprintf(format, a...)}}
+}
+
+// composeNode 就地展開 includes 與模式繼承（extends），name 為本檔在來源中的路徑
+func composeNode(root *yaml.Node, name string, load ConfigLoader) error {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	c := &composer{load: load, visiting: map[string]bool{name: true}, loaded: map[string]bool{}}
+	if err := c.includes(root, name); err != nil {
+		return err
+	}
+	if err := expandModes(root); err != nil {
+		return err
+	}
+	refWeights(root)
+	return nil
+}
+
+// refWeights 為未填 weight 的 {ref: name} 補上 library 區塊的 weight。
+// 以節點是否含 weight 判斷，明確寫出的 weight: 0 會保留。
+func refWeights(root *yaml.Node) {
+	sets := mappingValue(mappingValue(root, "library"), "reel_sets")
+	modes := mappingValue(root, "game_mode_settings")
+	if isNull(sets) || isNull(modes) || modes.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, m := range modes.Content {
+		rsg := mappingValue(mappingValue(m, "gen_screen_setting"), "reel_set_group")
+		if isNull(rsg) || rsg.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, rs := range rsg.Content {
+			ref := mappingValue(rs, "ref")
+			if isNull(ref) || mappingValue(rs, "weight") != nil {
+				continue
+			}
+			if w := mappingValue(mappingValue(sets, ref.Value), "weight"); w != nil {
+				rs.Content = append(rs.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "weight"}, cloneNode(w))
+			}
+		}
+	}
+}
+
+type composer struct {
+	load     ConfigLoader
+	visiting map[string]bool
+	loaded   map[string]bool
+}
+
+// includes 把 includes 檔案中的 library 合併進 root，並移除 includes 欄位
+func (c *composer) includes(root *yaml.Node, name string) error {
+	inc := mappingValue(root, "includes")
+	if isNull(inc) {
+		return nil
+	}
+	if inc.Kind != yaml.SequenceNode {
+		return newComposeErr(inc, "includes", "expected array of file names")
+	}
+	if c.load == nil {
+		return newComposeErr(inc, "includes", "includes require a config source (load from fs)")
+	}
+	for i, item := range inc.Content {
+		p := fmt.Sprintf("includes[%d]", i)
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			return newComposeErr(item, p, "expected file name")
+		}
+		file := path.Join(path.Dir(name), item.Value)
+		if c.visiting[file] {
+			return newComposeErr(item, p, "include cycle at %s", file)
+		}
+		if c.loaded[file] {
+			continue
+		}
+		raw, err := c.load(file)
+		if err != nil {
+			return newComposeErr(item, p, "read include %s: %v", file, err)
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return newComposeErr(item, p, "parse include %s: %v", file, err)
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			return newComposeErr(item, p, "include %s is not a mapping", file)
+		}
+		c.visiting[file] = true
+		if err := c.includes(doc.Content[0], file); err != nil {
+			return err
+		}
+		delete(c.visiting, file)
+		c.loaded[file] = true
+		if err := mergeLibrary(root, mappingValue(doc.Content[0], "library"), file); err != nil {
+			return err
+		}
+	}
+	removeKey(root, "includes")
+	return nil
+}
+
+// mergeLibrary 將 src library 的每個區塊加入 root 的 library（同名視為錯誤）
+func mergeLibrary(root, src *yaml.Node, from string) error {
+	if isNull(src) || src.Kind != yaml.MappingNode {
+		return nil
+	}
+	dst := mappingValue(root, "library")
+	if isNull(dst) {
+		dst = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setKey(root, "library", dst)
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		section, blocks := src.Content[i], src.Content[i+1]
+		if blocks.Kind != yaml.MappingNode {
+			continue
+		}
+		ds := mappingValue(dst, section.Value)
+		if isNull(ds) {
+			ds = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setKey(dst, section.Value, ds)
+		}
+		for j := 0; j+1 < len(blocks.Content); j += 2 {
+			k := blocks.Content[j]
+			if mappingValue(ds, k.Value) != nil {
+				return newComposeErr(k, "library."+section.Value+"."+k.Value, "%s already defined (again in %s)", k.Value, from)
+			}
+			ds.Content = append(ds.Content, k, blocks.Content[j+1])
+		}
+	}
+	return nil
+}
+
+// expandModes 依 extends 以父模式為底深度合併子模式：mapping 逐鍵合併，其餘（陣列、純量）整個覆寫
+func expandModes(root *yaml.Node) error {
+	modes := mappingValue(root, "game_mode_settings")
+	if isNull(modes) || modes.Kind != yaml.SequenceNode {
+		return nil
+	}
+	byName := make(map[string]int, len(modes.Content))
+	for i, m := range modes.Content {
+		if n := mappingValue(m, "name"); n != nil && n.Value != "" {
+			if k, ok := byName[n.Value]; ok {
+				return newComposeErr(n, fmt.Sprintf("game_mode_settings[%d].name", i), "duplicate mode name %s (first at index %d)", n.Value, k)
+			}
+			byName[n.Value] = i
+		}
+	}
+	done := make([]bool, len(modes.Content))
+	var resolve func(i int, stack map[int]bool) error
+	resolve = func(i int, stack map[int]bool) error {
+		if done[i] {
+			return nil
+		}
+		m := modes.Content[i]
+		ext := mappingValue(m, "extends")
+		if isNull(ext) {
+			done[i] = true
+			return nil
+		}
+		p := fmt.Sprintf("game_mode_settings[%d].extends", i)
+		parent, ok := byName[ext.Value]
+		if !ok {
+			return newComposeErr(ext, p, "unknown mode %q", ext.Value)
+		}
+		if stack[parent] {
+			return newComposeErr(ext, p, "extends cycle at mode %q", ext.Value)
+		}
+		stack[i] = true
+		if err := resolve(parent, stack); err != nil {
+			return err
+		}
+		delete(stack, i)
+		base := cloneNode(modes.Content[parent])
+		removeKey(base, "name")
+		merged := mergeNode(base, m)
+		removeKey(merged, "extends")
+		modes.Content[i] = merged
+		done[i] = true
+		return nil
+	}
+	for i := range modes.Content {
+		if err := resolve(i, map[int]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeNode 將 src 深度合併進 dst 並回傳 dst
+func mergeNode(dst, src *yaml.Node) *yaml.Node {
+	for src.Kind == yaml.AliasNode && src.Alias != nil {
+		src = src.Alias
+	}
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return src
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		k, v := src.Content[i], src.Content[i+1]
+		if cur := mappingValue(dst, k.Value); cur != nil {
+			setKey(dst, k.Value, mergeNode(cur, v))
+			continue
+		}
+		dst.Content = append(dst.Content, k, v)
+	}
+	return dst
+}
+
+func cloneNode(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, ch := range n.Content {
+		c.Content[i] = cloneNode(ch)
+	}
+	return &c
+}
+
+func setKey(n *yaml.Node, key string, v *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content[i+1] = v
+			return
+		}
+	}
+	n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+}
+
+func removeKey(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// resolveRefs 以 library 中的具名區塊填入各模式的 ref 欄位（深拷貝，避免模式之間共用底層陣列）
+func (gs *GameSetting) resolveRefs() error {
+	lib := gs.Library
+	for i := range gs.GameModeSettings {
+		m := &gs.GameModeSettings[i]
+		for j := range m.GenScreenSetting.ReelSetGroup {
+			rs := &m.GenScreenSetting.ReelSetGroup[j]
+			if rs.Ref == "" {
+				continue
+			}
+			src, ok := lib.ReelSets[rs.Ref]
+			if !ok {
+				return errs.NewFatal(fmt.Sprintf("%s: unknown reel set %q", modePath(i, fmt.Sprintf("gen_screen_setting.reel_set_group[%d].ref", j)), rs.Ref))
+			}
+			if len(rs.Reels) > 0 {
+				return errs.NewFatal(fmt.Sprintf("%s: ref and reels are mutually exclusive", modePath(i, fmt.Sprintf("gen_screen_setting.reel_set_group[%d]", j))))
+			}
+			rs.Reels = make([]Reel, len(src.Reels))
+			for k, r := range src.Reels {
+				rs.Reels[k] = Reel{ReelSymbols: append([]int16(nil), r.ReelSymbols...), ReelWeights: append([]int(nil), r.ReelWeights...)}
+			}
+			if rs.Name == "" {
+				rs.Name = rs.Ref
+			}
+		}
+		if ref := m.SymbolSetting.PayTableRef; ref != "" {
+			src, ok := lib.PayTables[ref]
+			if !ok {
+				return errs.NewFatal(fmt.Sprintf("%s: unknown pay table %q", modePath(i, "symbol_setting.pay_table_ref"), ref))
+			}
+			if len(m.SymbolSetting.PayTable) > 0 {
+				return errs.NewFatal(fmt.Sprintf("%s: pay_table_ref and pay_table are mutually exclusive", modePath(i, "symbol_setting")))
+			}
+			m.SymbolSetting.PayTable = make([][]int, len(src))
+			for k, row := range src {
+				m.SymbolSetting.PayTable[k] = append([]int(nil), row...)
+			}
+		}
+		if ref := m.HitSetting.LineTableRef; ref != "" {
+			src, ok := lib.LineTables[ref]
+			if !ok {
+				return errs.NewFatal(fmt.Sprintf("%s: unknown line table %q", modePath(i, "hit_setting.line_table_ref"), ref))
+			}
+			if len(m.HitSetting.LineTable) > 0 {
+				return errs.NewFatal(fmt.Sprintf("%s: line_table_ref and line_table are mutually exclusive", modePath(i, "hit_setting")))
+			}
+			m.HitSetting.LineTable = make([][]int16, len(src))
+			for k, row := range src {
+				m.HitSetting.LineTable[k] = append([]int16(nil), row...)
+			}
+		}
+	}
+	return nil
+}
+
+// Expanded 輸出完全展開後的設定（YAML）：引用已內嵌、不含 includes / library / extends，
+// 可直接以 GetGameSettingByYAML 重新載入，供稽核比對。
+func (gs *GameSetting) Expanded() ([]byte, error) {
+	out := *gs
+	out.Includes = nil
+	out.Library = ConfigLibrary{}
+	out.GameModeSettings = make([]GameModeSetting, len(gs.GameModeSettings))
+	for i, m := range gs.GameModeSettings {
+		m.Extends = ""
+		m.SymbolSetting.PayTableRef = ""
+		if len(m.SymbolSetting.Symbols) > 0 {
+			m.SymbolSetting.SymbolUsedStr = nil
+		}
+		m.HitSetting.LineTableRef = ""
+		rsg := make([]ReelSet, len(m.GenScreenSetting.ReelSetGroup))
+		for j, rs := range m.GenScreenSetting.ReelSetGroup {
+			rs.Ref = ""
+			rsg[j] = rs
+		}
+		m.GenScreenSetting.ReelSetGroup = rsg
+		out.GameModeSettings[i] = m
+	}
+	var n yaml.Node
+	if err := n.Encode(&out); err != nil {
+		return nil, errs.Wrap(err, "marshal expanded game setting failed")
+	}
+	flowScalarSeqs(&n)
+	b, err := yaml.Marshal(&n)
+	if err != nil {
+		return nil, errs.Wrap(err, "marshal expanded game setting failed")
+	}
+	return b, nil
+}
+
+// flowScalarSeqs 只含純量的陣列（輪帶、賠付列、線）改為單行輸出，方便閱讀與比對
+func flowScalarSeqs(n *yaml.Node) {
+	if n.Kind == yaml.SequenceNode && len(n.Content) > 0 {
+		flow := true
+		for _, c := range n.Content {
+			if c.Kind != yaml.ScalarNode {
+				flow = false
+				break
+			}
+		}
+		if flow {
+			n.Style = yaml.FlowStyle
+			return
+		}
+	}
+	for _, c := range n.Content {
+		flowScalarSeqs(c)
+	}
+}
@@ -16,6 +16,10 @@ package spec
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
 
 	"github.com/zintix-labs/problab/errs"
 	"gopkg.in/yaml.v3"
@@ -23,32 +27,94 @@ import (
 
 // GetGameSettingByYAML
 // 會讀取 YAML 設定、初始化各子設定並執行基本檢查後回傳。
+// 模式繼承（extends）與本檔 library 的引用會在此展開；includes 需要讀取來源，請改用 GetGameSettingFromFS。
 func GetGameSettingByYAML(data []byte) (*GameSetting, error) {
-	gs := &GameSetting{}
-	if err := yaml.Unmarshal(data, gs); err != nil {
-		return nil, errs.Wrap(err, "failed to unmarshall yaml")
+	return decodeGameSetting(data, "", nil)
+}
+
+// GetGameSettingByJSON
+// 會讀取 Json 設定、初始化各子設定並執行基本檢查後回傳
+//
+// JSON 視為 YAML 子集合，與 GetGameSettingByYAML 共用同一流程（library 引用、extends 皆可使用）。
+// 因此 fixed 內的數值依 YAML 規則解碼：整數字面值為 int、含小數點者為 float64（encoding/json 一律為 float64）；
+// 以 DecodeFixed 解成具型別的結構則不受影響。
+func GetGameSettingByJSON(data []byte) (*GameSetting, error) {
+	if err := json.Unmarshal(data, &json.RawMessage{}); err != nil {
+		return nil, errs.Wrap(err, "can not unmarshall json byte")
+	}
+	return decodeGameSetting(data, "", nil)
+}
+
+// GetGameSettingFromFS 從 fs.FS 讀取設定檔（依副檔名判斷 YAML/JSON），includes 以同一 fs.FS 解析
+func GetGameSettingFromFS(fsys fs.FS, name string) (*GameSetting, error) {
+	raw, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errs.Wrap(err, "read game setting failed")
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+	case ".json":
+		if err := json.Unmarshal(raw, &json.RawMessage{}); err != nil {
+			return nil, errs.Wrap(err, "can not unmarshall json byte")
+		}
+	default:
+		return nil, errs.NewFatal(fmt.Sprintf("unsupported config format: %q", name))
+	}
+	return decodeGameSetting(raw, name, FSLoader(fsys))
+}
+
+// decodeGameSetting 解析（JSON 為 YAML 子集合，共用同一流程）→ 展開 includes / extends → 解碼 → 展開引用 → 初始化
+func decodeGameSetting(data []byte, name string, load ConfigLoader) (*GameSetting, error) {
+	root, err := composeYAML(data, name, load)
+	if err != nil {
+		return nil, err
+	}
+	return decodeComposed(root)
+}
+
+// decodeComposed 將展開後的節點解碼為 GameSetting 並初始化
+func decodeComposed(root *yaml.Node) (*GameSetting, error) {
+	gs, err := decodeSetting(root)
+	if err != nil {
+		return nil, err
 	}
 
 	// 設定檔初始化
 	if err := gs.init(); err != nil {
 		return nil, errs.Wrap(err, "game setting initialized err")
 	}
+	// Invariant 規則不可降級，違反時執行期會出錯，解碼時即拒絕
+	if err := checkInvariants(gs); err != nil {
+		return nil, err
+	}
 
 	return gs, nil
 }
 
-// GetGameSettingByJSON
-// 會讀取 Json 設定、初始化各子設定並執行基本檢查後回傳
-func GetGameSettingByJSON(data []byte) (*GameSetting, error) {
+// decodeSetting 解碼展開後的根節點並解析 ref（尚未初始化）
+func decodeSetting(root *yaml.Node) (*GameSetting, error) {
 	gs := &GameSetting{}
-	if err := json.Unmarshal(data, gs); err != nil {
-		return nil, errs.Wrap(err, "can not unmarshall json byte")
+	if err := root.Decode(gs); err != nil {
+		return nil, errs.Wrap(err, "failed to unmarshall yaml")
 	}
-
-	// 設定檔初始化
-	if err := gs.init(); err != nil {
+	if err := gs.resolveRefs(); err != nil {
 		return nil, errs.Wrap(err, "game setting initialized err")
 	}
-
 	return gs, nil
 }
+
+// composeYAML 解析內容並展開 includes 與模式繼承，回傳展開後的根節點
+func composeYAML(data []byte, name string, load ConfigLoader) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errs.Wrap(err, "failed to unmarshall yaml")
+	}
+	if len(doc.Content) == 0 {
+		return nil, errs.NewFatal("empty game setting")
+	}
+	root := doc.Content[0]
+	if err := composeNode(root, name, load); err != nil {
+		return nil, errs.Wrap(err, "compose game setting failed")
+	}
+	return root, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// FingerprintPrefix 指紋字串前綴（標示雜湊演算法）
+const FingerprintPrefix string = "sha256:"
+
+// Fingerprint 回傳設定檔指紋：完全展開後設定（Expanded）的 SHA-256。
+//
+// 與排版、註解、欄位順序以及 includes / extends 的拆分方式無關；只要展開後的數學內容相同，指紋就相同。
+// 注意 reel set 名稱屬於設定內容（cascade refill 等以名稱查找）：`{ref: base}` 未另給 name 時以 ref 為名，
+// 等同於內嵌寫成 `{name: base, ...}`；內嵌但不具名的 reel set 指紋不同。
+func (gs *GameSetting) Fingerprint() (string, error) {
+	b, err := gs.Expanded()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return FingerprintPrefix + hex.EncodeToString(sum[:]), nil
+}
@@ -14,26 +14,37 @@
 
 package spec
 
+import "errors"
+
 // GameModeSetting 將單一遊戲模式（如主遊戲、免費遊戲等）所需設定統整在一起。
+//
+// 模式可設定 name，並以 extends 繼承另一個具名模式：子模式的欄位深度覆寫父模式（mapping 逐鍵合併，陣列整個取代）。
+// hold_and_win 為可選的特色區塊，見 HoldAndWinSetting。
 type GameModeSetting struct {
-	ScreenSetting    ScreenSetting    `yaml:"screen_setting"     json:"screen_setting"`
-	GenScreenSetting GenScreenSetting `yaml:"gen_screen_setting" json:"gen_screen_setting"`
-	SymbolSetting    SymbolSetting    `yaml:"symbol_setting"     json:"symbol_setting"`
-	HitSetting       HitSetting       `yaml:"hit_setting"        json:"hit_setting"`
+	Name             string             `yaml:"name,omitempty"     json:"name,omitempty"`
+	Extends          string             `yaml:"extends,omitempty"  json:"extends,omitempty"`
+	ScreenSetting    ScreenSetting      `yaml:"screen_setting"     json:"screen_setting"`
+	GenScreenSetting GenScreenSetting   `yaml:"gen_screen_setting" json:"gen_screen_setting"`
+	SymbolSetting    SymbolSetting      `yaml:"symbol_setting"     json:"symbol_setting"`
+	HitSetting       HitSetting         `yaml:"hit_setting"        json:"hit_setting"`
+	HoldAndWin       *HoldAndWinSetting `yaml:"hold_and_win,omitempty" json:"hold_and_win,omitempty"`
+	initFailed       bool               // 初始化失敗（ValidateConfig 不對其執行規則）
 }
 
+// init 初始化模式內各設定並收集錯誤（各錯誤帶有相對於模式的 YAML 路徑）；
+// 相依的檢查（盤面相容性、hold_and_win）只在前置設定成功時執行
 func (gms *GameModeSetting) init() error {
-	if err := gms.ScreenSetting.Init(); err != nil {
-		return err
-	}
-	if err := gms.GenScreenSetting.Init(); err != nil {
-		return err
-	}
-	if err := gms.SymbolSetting.Init(); err != nil {
-		return err
+	errList := make([]error, 0)
+	screenErr := gms.ScreenSetting.Init()
+	genErr := gms.GenScreenSetting.Init()
+	errList = append(errList, atPath("screen_setting", screenErr), atPath("gen_screen_setting", genErr))
+	if screenErr == nil && genErr == nil {
+		errList = append(errList, atPath("gen_screen_setting", gms.GenScreenSetting.checkScreen(&gms.ScreenSetting)))
 	}
-	if err := gms.HitSetting.Init(); err != nil {
-		return err
+	symErr := gms.SymbolSetting.Init()
+	errList = append(errList, atPath("symbol_setting", symErr), atPath("hit_setting", gms.HitSetting.Init()))
+	if gms.HoldAndWin != nil && symErr == nil {
+		errList = append(errList, atPath("hold_and_win", gms.HoldAndWin.init(&gms.SymbolSetting)))
 	}
-	return nil
+	return errors.Join(errList...)
 }
@@ -15,6 +15,7 @@
 package spec
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/zintix-labs/problab/errs"
@@ -24,80 +25,106 @@ import (
 type GameSetting struct {
 	GameName         string            `yaml:"game_name"           json:"game_name"`
 	GameID           GID               `yaml:"game_id"             json:"game_id"`
+	Variant          string            `yaml:"variant,omitempty"   json:"variant,omitempty"` // 變體名稱（同一 game_id 的不同 RTP 版本；空字串為預設版本）
+	RTP              float64           `yaml:"rtp,omitempty"       json:"rtp,omitempty"`     // 設定的目標 RTP（0~1，僅供列舉與報表，不影響計算）
 	LogicKey         LogicKey          `yaml:"logic_key"           json:"logic_key"`
 	BetUnits         []int             `yaml:"bet_units"           json:"bet_units"`
 	MaxWinLimit      int               `yaml:"max_win_limit"       json:"max_win_limit"`
 	OptimalSetting   OptimalSetting    `yaml:"optimal_setting"     json:"optimal_setting"`
 	GameModeSettings []GameModeSetting `yaml:"game_mode_settings"  json:"game_mode_settings"`
 	Fixed            map[string]any    `yaml:"fixed"               json:"fixed"`
+	Includes         []string          `yaml:"includes,omitempty"  json:"includes,omitempty"` // 引入其他檔案的 library（解析時展開）
+	Library          ConfigLibrary     `yaml:"library,omitempty"   json:"library,omitempty"`  // 具名共用區塊
 }
 
-// init
+// init 初始化各模式並檢查設定，收集全部錯誤（不會在第一個錯誤就停止）。
+// 每個錯誤都帶有 YAML 路徑（見 atPath），初始化失敗的模式會標記起來，ValidateConfig 不對其執行規則。
 func (gs *GameSetting) init() error {
+	errList := make([]error, 0)
 	for i := range gs.GameModeSettings {
 		mode := &gs.GameModeSettings[i]
 		if err := mode.init(); err != nil {
-			return err
+			mode.initFailed = true
+			errList = append(errList, atPath(fmt.Sprintf("game_mode_settings[%d]", i), err))
 		}
 	}
-	return gs.valid()
+	errList = append(errList, gs.valid()...)
+	return errors.Join(errList...)
 }
 
 // valid 執行最基本的設定檔檢查，如需更多驗證可在此擴充。
-func (gs *GameSetting) valid() error {
+func (gs *GameSetting) valid() []error {
+	errList := make([]error, 0)
+	add := func(path string, err error) {
+		errList = append(errList, atPath(path, err))
+	}
+
+	// valid Variant / RTP
+	if gs.Variant != "" && !validSymbolName(gs.Variant) {
+		add("variant", errs.NewFatal(fmt.Sprintf("game_name: %s err:invalid variant %q (only [A-Za-z0-9_], max %d chars)", gs.GameName, gs.Variant, MaxSymbolNameLen)))
+	}
+	if gs.RTP < 0 || gs.RTP > 1 {
+		add("rtp", errs.NewFatal(fmt.Sprintf("game_name: %s err:rtp must be within [0, 1], got %v", gs.GameName, gs.RTP)))
+	}
 
 	// valid BetUnits
 	if len(gs.BetUnits) == 0 {
-		return errs.NewFatal(fmt.Sprintf("game_name: %s err:empty bet_units", gs.GameName))
+		add("bet_units", errs.NewFatal(fmt.Sprintf("game_name: %s err:empty bet_units", gs.GameName)))
 	}
 
-	for _, b := range gs.BetUnits {
+	for i, b := range gs.BetUnits {
 		if b < 1 {
-			return errs.NewFatal(fmt.Sprintf("game_name: %s err:invalid bet unit", gs.GameName))
+			add(fmt.Sprintf("bet_units[%d]", i), errs.NewFatal(fmt.Sprintf("game_name: %s err:invalid bet unit", gs.GameName)))
 		}
 		if gs.MaxWinLimit < b {
-			return errs.NewFatal(fmt.Sprintf("game_name: %s err:empty game mode settings", gs.GameName))
+			add("max_win_limit", errs.NewFatal(fmt.Sprintf("game_name: %s err:max_win_limit must be >= every bet unit", gs.GameName)))
+			break
 		}
 	}
 	if err := gs.OptimalSetting.valid(); err != nil {
-		return err
+		add("optimal_setting", err)
 	}
 	if gs.OptimalSetting.UseOptimal {
 		if len(gs.OptimalSetting.Gachas) > len(gs.BetUnits) {
-			return errs.NewFatal("optimal_setting: gachas must be less than or equal to bet_units")
+			add("optimal_setting.gachas", errs.NewFatal("optimal_setting: gachas must be less than or equal to bet_units"))
 		}
 		if len(gs.OptimalSetting.SeedBank) > len(gs.BetUnits) {
-			return errs.NewFatal("optimal_setting: seed_bank must be less than or equal to bet_units")
+			add("optimal_setting.seed_bank", errs.NewFatal("optimal_setting: seed_bank must be less than or equal to bet_units"))
 		}
 	}
 
 	// 檢查 GameModeSettings 不能為空
 	if len(gs.GameModeSettings) == 0 {
-		return errs.NewFatal("empty game_mode_settings")
+		add("game_mode_settings", errs.NewFatal("empty game_mode_settings"))
 	}
 
-	// GameModeSetting 檢查
-	for i := 0; i < len(gs.GameModeSettings); i++ {
-		gms := gs.GameModeSettings[i]
-		screenSetting := gms.ScreenSetting
-		if screenSetting.Columns <= 0 || screenSetting.Rows <= 0 {
-			return errs.NewFatal(fmt.Sprintf("invalid screen dimensions: cols=%d rows=%d", screenSetting.Columns, screenSetting.Rows))
-		}
-		count := int16(gms.SymbolSetting.SymbolCount)
-		rsGp := gms.GenScreenSetting.ReelSetGroup
-		if len(rsGp) != 0 {
-			for rsid := range rsGp {
-				rs := rsGp[rsid].Reels
-				for j := range rs {
-					for k, s := range rs[j].ReelSymbols {
-						if s < 0 || s > count {
-							return errs.NewFatal(fmt.Sprintf("symbol out of range: reelset %d reel %d index %d : %d", rsid, j, k, s))
-						}
-					}
-				}
-			}
+	return errList
+}
+
+// pathErr 帶有 YAML 路徑的初始化錯誤，讓 ValidateConfig 對回行號
+type pathErr struct {
+	path string
+	err  error
+}
+
+func (e *pathErr) Error() string { return e.path + ": " + e.err.Error() }
+
+func (e *pathErr) Unwrap() error { return e.err }
+
+// atPath 為 err 加上路徑前綴：已帶路徑的錯誤接成完整路徑，errors.Join 的多個錯誤逐一處理
+func atPath(path string, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *pathErr:
+		return &pathErr{path: joinPath(path, e.path), err: e.err}
+	case interface{ Unwrap() []error }:
+		list := e.Unwrap()
+		out := make([]error, len(list))
+		for i, x := range list {
+			out[i] = atPath(path, x)
 		}
+		return errors.Join(out...)
 	}
-
-	return nil
+	return &pathErr{path: path, err: err}
 }
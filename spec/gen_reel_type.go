@@ -0,0 +1,113 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+
+	"github.com/zintix-labs/problab/errs"
+	"gopkg.in/yaml.v3"
+)
+
+// GenParamsChecker 自訂生成參數可選實作：設定解析時與 ScreenSetting 一併檢查
+type GenParamsChecker interface {
+	CheckScreen(ss *ScreenSetting) error
+}
+
+type genReelTypeDef struct {
+	typeName string
+	decode   func(params map[string]any) (any, error)
+}
+
+var (
+	genMu       sync.RWMutex
+	genReelDefs = map[GenReelType]genReelTypeDef{}
+	genNextType = GenReelByHeight + 1
+)
+
+// RegisterGenReelType 註冊自訂 gen_reel_type，回傳配發的 GenReelType。
+//
+// T 為 gen_screen_setting.params 解碼後的型別（非指標 struct），解析設定時以嚴格模式解碼，
+// 結果以 *T 存放於 GenScreenSetting.Custom；*T 若實作 GenParamsChecker 會再與盤面設定一併檢查。
+// 同名重複註冊僅允許相同的 T（回傳同一個 GenReelType）。
+//
+// 一般請使用 gen.Register 同時註冊生成函式；單獨呼叫本函式時生成器將無法建立。
+func RegisterGenReelType[T any](name string) (GenReelType, error) {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() != reflect.Struct {
+		return GenReelTypeNone, errs.NewFatal("RegisterGenReelType: T must be a non-pointer struct type")
+	}
+	if name == "" {
+		return GenReelTypeNone, errs.NewFatal("RegisterGenReelType: name is required")
+	}
+	typeName := rt.PkgPath() + "." + rt.Name()
+
+	genMu.Lock()
+	defer genMu.Unlock()
+	if t, ok := genReelTypeMap[name]; ok {
+		if d, custom := genReelDefs[t]; custom && d.typeName == typeName {
+			return t, nil
+		}
+		return GenReelTypeNone, errs.Fatalf("gen reel type already registered: %s", name)
+	}
+	t := genNextType
+	genNextType++
+	genReelTypeMap[name] = t
+	genReelDefs[t] = genReelTypeDef{
+		typeName: typeName,
+		decode: func(params map[string]any) (any, error) {
+			out := new(T)
+			if len(params) == 0 {
+				return out, nil
+			}
+			bs, err := yaml.Marshal(params)
+			if err != nil {
+				return nil, errs.Wrap(err, "gen params : marshal failed")
+			}
+			dec := yaml.NewDecoder(bytes.NewReader(bs))
+			dec.KnownFields(true) // 嚴格檢查：多寫/拼錯欄位就報錯
+			if err = dec.Decode(out); err != nil {
+				return nil, errs.Wrap(err, "gen params : decode failed")
+			}
+			return out, nil
+		},
+	}
+	return t, nil
+}
+
+// IsCustomGenReelType 回傳 t 是否為自訂（註冊）的生成類型
+func IsCustomGenReelType(t GenReelType) bool {
+	genMu.RLock()
+	defer genMu.RUnlock()
+	_, ok := genReelDefs[t]
+	return ok
+}
+
+// ParseGenReelType 依名稱查詢 gen_reel_type（含已註冊的自訂類型）
+func ParseGenReelType(name string) (GenReelType, bool) {
+	genMu.RLock()
+	defer genMu.RUnlock()
+	t, ok := genReelTypeMap[name]
+	return t, ok
+}
+
+func genReelTypeDefOf(t GenReelType) (genReelTypeDef, bool) {
+	genMu.RLock()
+	defer genMu.RUnlock()
+	d, ok := genReelDefs[t]
+	return d, ok
+}
@@ -15,6 +15,10 @@
 package spec
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+
 	"github.com/zintix-labs/problab/errs"
 	"github.com/zintix-labs/problab/sdk/sampler"
 )
@@ -26,34 +30,67 @@ const (
 	GenReelTypeNone GenReelType = iota
 	GenReelByReelIdx
 	GenReelBySymbolWeight
+	GenReelByHeight // 每軸每局依權重表抽出可見高度（Megaways 類型）
 )
 
-var GenReelTypeMap = map[string]GenReelType{
+// genReelTypeMap gen_reel_type 名稱對應表（受 genMu 保護）；自訂類型以 RegisterGenReelType（或 gen.Register）加入，
+// 外部查詢請使用 ParseGenReelType / GenReelTypeNames
+var genReelTypeMap = map[string]GenReelType{
 	"GenReelTypeNone":       GenReelTypeNone,
 	"GenReelByReelIdx":      GenReelByReelIdx,
 	"GenReelBySymbolWeight": GenReelBySymbolWeight,
+	"GenReelByHeight":       GenReelByHeight,
 }
 
 // Reel 一條輪帶設定，可以產出一軸結果的最小單位
 type Reel struct {
 	ReelSymbols []int16     `yaml:"symbols" json:"symbols"`
-	ReelWeights []int       `yaml:"weights" json:"weights"`
+	ReelWeights []int       `yaml:"weights,omitempty" json:"weights,omitempty"`
 	ReelLength  int         `yaml:"-"       json:"-"`
 	ReelLUT     sampler.LUT `yaml:"-"       json:"-"`
 }
 
 // ReelSet 一組輪帶設定，可以產出一個盤面的最小單位
+//
+// Name 供邏輯以名稱取得輪帶組（ReelSetByName）；Ref 引用 library.reel_sets 中的具名輪帶組。
 type ReelSet struct {
-	Weight int    `yaml:"weight" json:"weight"`
-	Reels  []Reel `yaml:"reels"  json:"reels"`
+	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
+	Ref    string `yaml:"ref,omitempty"  json:"ref,omitempty"`
+	Weight int    `yaml:"weight"         json:"weight"`
+	Reels  []Reel `yaml:"reels"          json:"reels"`
+}
+
+// HeightTable 單軸可見高度的權重表（GenReelByHeight 使用）
+type HeightTable struct {
+	Heights []int       `yaml:"heights"           json:"heights"`
+	Weights []int       `yaml:"weights,omitempty" json:"weights,omitempty"`
+	LUT     sampler.LUT `yaml:"-"                 json:"-"`
+}
+
+// TopReel 橫向頂部輪帶（GenReelByHeight 使用）
+//
+// 佔據盤面第 0 列的 Columns 各一格，由左到右連續取 Reel 上的圖標；
+// 設定後所有直向輪帶自第 1 列開始排列。
+type TopReel struct {
+	Columns []int `yaml:"columns" json:"columns"`
+	Reel    Reel  `yaml:"reel"    json:"reel"`
 }
 
 // GenScreenSetting 生成盤面的設定
+//
+// ReelHeights / TopReel 僅用於 GenReelByHeight：ReelHeights 每軸一張高度權重表，
+// 每局抽出的高度以下的格子為 CellBlocked；ScreenSetting.Rows 須容納最大高度（含 TopReel 一列）。
+//
+// Params 僅用於自訂生成類型（RegisterGenReelType），Init 時解碼為 Custom（*T）。
 type GenScreenSetting struct {
-	GenReelTypeStr string      `yaml:"gen_reel_type"   json:"gen_reel_type"`
-	GenReelType    GenReelType `yaml:"-"               json:"-"`
-	ReelSetGroup   []ReelSet   `yaml:"reel_set_group"  json:"reel_set_group"`
-	ReelSetLUT     sampler.LUT `yaml:"-"               json:"-"`
+	GenReelTypeStr string         `yaml:"gen_reel_type"          json:"gen_reel_type"`
+	GenReelType    GenReelType    `yaml:"-"                      json:"-"`
+	ReelSetGroup   []ReelSet      `yaml:"reel_set_group"         json:"reel_set_group"`
+	ReelHeights    []HeightTable  `yaml:"reel_heights,omitempty" json:"reel_heights,omitempty"`
+	TopReel        *TopReel       `yaml:"top_reel,omitempty"     json:"top_reel,omitempty"`
+	Params         map[string]any `yaml:"params,omitempty"       json:"params,omitempty"`
+	Custom         any            `yaml:"-"                      json:"-"`
+	ReelSetLUT     sampler.LUT    `yaml:"-"                      json:"-"`
 	initFlag       bool
 }
 
@@ -66,14 +103,24 @@ func (gs *GenScreenSetting) Init() error {
 
 	// 1. 解析 GenReelType
 	if gs.GenReelType == GenReelTypeNone {
-		grt, ok := GenReelTypeMap[gs.GenReelTypeStr]
-		if !ok {
-			return errs.NewFatal("invalid gen reel type")
+		grt, ok := ParseGenReelType(gs.GenReelTypeStr)
+		if !ok || grt == GenReelTypeNone {
+			return errs.Fatalf("invalid gen reel type: %q", gs.GenReelTypeStr)
 		}
 		gs.GenReelType = grt
 	}
+	if d, ok := genReelTypeDefOf(gs.GenReelType); ok {
+		custom, err := d.decode(gs.Params)
+		if err != nil {
+			return errs.Wrap(err, "gen_screen_setting.params ("+gs.GenReelTypeStr+")")
+		}
+		gs.Custom = custom
+	} else if len(gs.Params) > 0 {
+		return errs.Fatalf("gen_screen_setting.params is only supported by custom gen reel types, got %s", gs.GenReelTypeStr)
+	}
 
-	// 2. 建立 ReelSet 選擇用的 LUT
+	// 2. 建立 ReelSet 選擇用的 LUT（各輪帶的錯誤全部收集，帶上路徑）
+	errList := make([]error, 0)
 	weights := make([]int, len(gs.ReelSetGroup))
 	for i := range gs.ReelSetGroup {
 		rs := &gs.ReelSetGroup[i]
@@ -81,27 +128,162 @@ func (gs *GenScreenSetting) Init() error {
 
 		// 轉Reel內部資料
 		for j := range rs.Reels {
-			reel := &rs.Reels[j]
-			if len(reel.ReelSymbols) == 0 {
-				return errs.NewFatal("len(ReelSymbols) == 0")
-			}
-			if len(reel.ReelWeights) == 0 {
-				// 如果長度為0 / nil 默認等權重
-				reel.ReelWeights = make([]int, len(reel.ReelSymbols))
-				for i := range len(reel.ReelSymbols) {
-					reel.ReelWeights[i] = 1
-				}
+			errList = append(errList, atPath(fmt.Sprintf("reel_set_group[%d].reels[%d]", i, j), rs.Reels[j].init()))
+		}
+	}
+
+	// 3. 可變高度設定
+	if gs.GenReelType == GenReelByHeight {
+		errList = append(errList, gs.initHeights())
+	}
+
+	if err := errors.Join(errList...); err != nil {
+		return err
+	}
+	// 建立最外層 ReelSet 權重表（權重由 reel_weights 規則檢查）
+	if validWeights(weights) {
+		gs.ReelSetLUT = sampler.BuildLUT(weights)
+	}
+	gs.initFlag = true
+	return nil
+}
+
+// init 補齊預設權重並建立輪帶查表
+func (reel *Reel) init() error {
+	if len(reel.ReelSymbols) == 0 {
+		return errs.NewFatal("len(ReelSymbols) == 0")
+	}
+	if len(reel.ReelWeights) == 0 {
+		// 如果長度為0 / nil 默認等權重
+		reel.ReelWeights = make([]int, len(reel.ReelSymbols))
+		for i := range len(reel.ReelSymbols) {
+			reel.ReelWeights[i] = 1
+		}
+	}
+	if len(reel.ReelSymbols) != len(reel.ReelWeights) {
+		return errs.NewFatal("len(ReelSymbols) != len(ReelWeights)")
+	}
+	reel.ReelLength = len(reel.ReelSymbols)
+	// 權重由 reel_weights 規則檢查（違規的設定不會通過解碼），不合法時不建表以免 BuildLUT panic
+	if validWeights(reel.ReelWeights) {
+		reel.ReelLUT = sampler.BuildLUT(reel.ReelWeights)
+	}
+	return nil
+}
+
+// validWeights 回傳權重是否可建立 LUT（空表回傳空 LUT）
+func validWeights(ws []int) bool {
+	return len(ws) == 0 || checkWeights("", ws) == nil
+}
+
+// checkWeights 在建立 LUT 前檢查權重：不可為負且不可全為 0（否則 BuildLUT 會 panic）
+func checkWeights(what string, ws []int) error {
+	sum := 0
+	for k, w := range ws {
+		if w < 0 {
+			return errs.Fatalf("%s: negative weight %d at [%d]", what, w, k)
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return errs.Fatalf("%s: all weights are zero", what)
+	}
+	return nil
+}
+
+func (gs *GenScreenSetting) initHeights() error {
+	if len(gs.ReelHeights) == 0 {
+		return errs.NewFatal("GenReelByHeight requires reel_heights")
+	}
+	for i := range gs.ReelHeights {
+		ht := &gs.ReelHeights[i]
+		if len(ht.Heights) == 0 {
+			return errs.NewFatal(fmt.Sprintf("reel_heights[%d]: heights is empty", i))
+		}
+		for _, h := range ht.Heights {
+			if h < 1 {
+				return errs.NewFatal(fmt.Sprintf("reel_heights[%d]: height must be >= 1, got %d", i, h))
 			}
-			if len(reel.ReelSymbols) != len(reel.ReelWeights) {
-				return errs.NewFatal("len(ReelSymbols) != len(ReelWeights)")
+		}
+		if len(ht.Weights) == 0 {
+			ht.Weights = make([]int, len(ht.Heights))
+			for k := range ht.Weights {
+				ht.Weights[k] = 1
 			}
-			reel.ReelLength = len(reel.ReelSymbols)
-			reel.ReelLUT = sampler.BuildLUT(reel.ReelWeights)
+		}
+		if len(ht.Weights) != len(ht.Heights) {
+			return errs.NewFatal(fmt.Sprintf("reel_heights[%d]: len(heights) != len(weights)", i))
+		}
+		if err := checkWeights(fmt.Sprintf("reel_heights[%d] weights", i), ht.Weights); err != nil {
+			return err
+		}
+		ht.LUT = sampler.BuildLUT(ht.Weights)
+	}
+	if gs.TopReel != nil {
+		if len(gs.TopReel.Columns) == 0 {
+			return errs.NewFatal("top_reel.columns is empty")
+		}
+		if err := gs.TopReel.Reel.init(); err != nil {
+			return atPath("top_reel", err)
 		}
 	}
+	return nil
+}
 
-	// 建立最外層 ReelSet 權重表
-	gs.ReelSetLUT = sampler.BuildLUT(weights)
-	gs.initFlag = true
+// TopRows 回傳直向輪帶的起始列（有 TopReel 時為 1）
+func (gs *GenScreenSetting) TopRows() int {
+	if gs.TopReel != nil {
+		return 1
+	}
+	return 0
+}
+
+// MaxHeight 回傳各軸高度權重表中的最大高度
+func (gs *GenScreenSetting) MaxHeight() int {
+	m := 0
+	for _, ht := range gs.ReelHeights {
+		m = max(m, slices.Max(ht.Heights))
+	}
+	return m
+}
+
+// checkScreen 檢查可變高度 / 自訂生成參數與盤面是否相容（由 GameModeSetting 初始化時呼叫）
+func (gs *GenScreenSetting) checkScreen(ss *ScreenSetting) error {
+	if c, ok := gs.Custom.(GenParamsChecker); ok {
+		if err := c.CheckScreen(ss); err != nil {
+			return errs.Wrap(err, "gen_screen_setting.params ("+gs.GenReelTypeStr+")")
+		}
+	}
+	if gs.GenReelType != GenReelByHeight {
+		return nil
+	}
+	if len(ss.Mask) > 0 {
+		return errs.NewFatal("GenReelByHeight does not support screen mask")
+	}
+	if len(gs.ReelHeights) != ss.Columns {
+		return errs.NewFatal(fmt.Sprintf("reel_heights: %d tables for %d columns", len(gs.ReelHeights), ss.Columns))
+	}
+	if need := gs.MaxHeight() + gs.TopRows(); need > ss.Rows {
+		return errs.NewFatal(fmt.Sprintf("reel_heights: max height needs %d rows, screen has %d", need, ss.Rows))
+	}
+	if gs.TopReel != nil {
+		seen := make(map[int]bool, len(gs.TopReel.Columns))
+		for _, c := range gs.TopReel.Columns {
+			if c < 0 || c >= ss.Columns || seen[c] {
+				return errs.NewFatal(fmt.Sprintf("top_reel.columns: invalid or duplicate column %d", c))
+			}
+			seen[c] = true
+		}
+	}
 	return nil
 }
+
+// ReelSetByName 依名稱取得輪帶組
+func (gs *GenScreenSetting) ReelSetByName(name string) (*ReelSet, bool) {
+	for i := range gs.ReelSetGroup {
+		if gs.ReelSetGroup[i].Name == name {
+			return &gs.ReelSetGroup[i], true
+		}
+	}
+	return nil, false
+}
@@ -16,6 +16,8 @@ package spec
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/zintix-labs/problab/errs"
 )
@@ -45,11 +47,49 @@ var betTypeMap = map[string]BetType{
 	"cluster":   BetTypeCluster,
 }
 
+var (
+	betTypeMu      sync.RWMutex
+	customBetTypes = map[BetType]string{}
+	betTypeNext    = BetTypeCluster + 1
+)
+
 func ParseBetType(s string) (BetType, bool) {
+	betTypeMu.RLock()
+	defer betTypeMu.RUnlock()
 	betType, ok := betTypeMap[s]
 	return betType, ok
 }
 
+// RegisterBetType 註冊自訂 bet_type 名稱並配發 BetType；同名重複註冊回傳同一個 BetType，內建名稱不可覆寫。
+//
+// 一般請使用 calc.RegisterBetType 同時註冊算分函式；單獨呼叫本函式時算分器將無法建立。
+func RegisterBetType(name string) (BetType, error) {
+	if name == "" {
+		return 0, errs.NewFatal("RegisterBetType: name is required")
+	}
+	betTypeMu.Lock()
+	defer betTypeMu.Unlock()
+	if bt, ok := betTypeMap[name]; ok {
+		if _, custom := customBetTypes[bt]; custom {
+			return bt, nil
+		}
+		return 0, errs.Fatalf("bet type already registered: %s", name)
+	}
+	bt := betTypeNext
+	betTypeNext++
+	betTypeMap[name] = bt
+	customBetTypes[bt] = name
+	return bt, nil
+}
+
+// IsBetTypeCustom 回傳是否為自訂（註冊）的下注型態
+func IsBetTypeCustom(b BetType) bool {
+	betTypeMu.RLock()
+	defer betTypeMu.RUnlock()
+	_, ok := customBetTypes[b]
+	return ok
+}
+
 // IsBetTypeLine 回傳是否屬於線類型下注
 func IsBetTypeLine(b BetType) bool {
 	return b == BetTypeLineLTR || b == BetTypeLineRTL || b == BetTypeLineBoth
@@ -80,12 +120,106 @@ func IsRightToLeft(b BetType) bool {
 	return (b == BetTypeLineRTL) || (b == BetTypeLineBoth) || (b == BetTypeWayRTL) || (b == BetTypeWayBoth)
 }
 
+// MultiplierMode 乘數合併方式
+const (
+	MultiplierModeMul = "mul" // 相乘（預設）：2x 與 3x 合併為 6x
+	MultiplierModeAdd = "add" // 相加：2x 與 3x 合併為 5x
+)
+
+// Cluster 盤面拓樸
+const (
+	ClusterTopologySquare     = "square"       // 方格（預設），鄰接數由 adjacency 決定
+	ClusterTopologyHexOddCol  = "hex_odd_col"  // 六角格，奇數軸下移半格
+	ClusterTopologyHexEvenCol = "hex_even_col" // 六角格，偶數軸下移半格
+	ClusterTopologyHexOddRow  = "hex_odd_row"  // 六角格，奇數列右移半格
+	ClusterTopologyHexEvenRow = "hex_even_row" // 六角格，偶數列右移半格
+)
+
+// ClusterTopologyNames 回傳所有合法的 cluster topology
+func ClusterTopologyNames() []string {
+	return []string{ClusterTopologyHexEvenCol, ClusterTopologyHexEvenRow, ClusterTopologyHexOddCol, ClusterTopologyHexOddRow, ClusterTopologySquare}
+}
+
+// ClusterSetting Cluster 算分規則（僅 bet_type: cluster 使用，全部留空即為預設行為）
+//
+// Fields:
+//   - Topology: 盤面拓樸，見 ClusterTopology*；六角格固定 6 鄰接
+//   - Adjacency: 方格鄰接數 4（預設，上下左右）或 8（含斜角）
+//   - MinSize: 最小集群大小（含 wild），0 表示只依 pay_table 決定
+//   - WildExclusive: wild 只能屬於一個派彩集群（預設可同時加入多個集群）；
+//     集群依盤面掃描順序（起點格子索引由小到大）結算，wild 歸屬最先結算且派彩的集群，而非派彩最高者
+//   - PayTiers: 分段派彩下限（遞增），例如 [5, 7, 9] 表示 5-6、7-8、9+ 三段，
+//     此時 pay_table 每列依序對應各段；留空時 pay_table 依集群大小逐顆對應
+type ClusterSetting struct {
+	Topology      string `yaml:"topology,omitempty"       json:"topology,omitempty"`
+	Adjacency     int    `yaml:"adjacency,omitempty"      json:"adjacency,omitempty"`
+	MinSize       int    `yaml:"min_size,omitempty"       json:"min_size,omitempty"`
+	WildExclusive bool   `yaml:"wild_exclusive,omitempty" json:"wild_exclusive,omitempty"`
+	PayTiers      []int  `yaml:"pay_tiers,omitempty"      json:"pay_tiers,omitempty"`
+}
+
+// IsZero 回傳是否為預設（未設定）
+func (cs *ClusterSetting) IsZero() bool {
+	return cs.Topology == "" && cs.Adjacency == 0 && cs.MinSize == 0 && !cs.WildExclusive && len(cs.PayTiers) == 0
+}
+
+// PayColumn 回傳集群大小對應的 pay_table 欄位（-1 表示未達最低分段）；
+// 未截斷到列長，超出列長時以最後一欄派彩（與算分一致）
+func (cs *ClusterSetting) PayColumn(size int) int {
+	if len(cs.PayTiers) == 0 {
+		return size - 1
+	}
+	col := -1
+	for k, t := range cs.PayTiers {
+		if size < t {
+			break
+		}
+		col = k
+	}
+	return col
+}
+
+// IsHex 回傳是否為六角格拓樸
+func (cs *ClusterSetting) IsHex() bool {
+	return strings.HasPrefix(cs.Topology, "hex_")
+}
+
+func (cs *ClusterSetting) valid() error {
+	switch cs.Topology {
+	case "", ClusterTopologySquare:
+		if cs.Adjacency != 0 && cs.Adjacency != 4 && cs.Adjacency != 8 {
+			return errs.Fatalf("cluster.adjacency must be 4 or 8, got %d", cs.Adjacency)
+		}
+	case ClusterTopologyHexOddCol, ClusterTopologyHexEvenCol, ClusterTopologyHexOddRow, ClusterTopologyHexEvenRow:
+		if cs.Adjacency != 0 && cs.Adjacency != 6 {
+			return errs.Fatalf("cluster.adjacency must be 6 for %s, got %d", cs.Topology, cs.Adjacency)
+		}
+	default:
+		return errs.Fatalf("cluster.topology error: %s", cs.Topology)
+	}
+	if cs.MinSize < 0 {
+		return errs.Fatalf("cluster.min_size must be >= 0, got %d", cs.MinSize)
+	}
+	for i, t := range cs.PayTiers {
+		if t < 1 || (i > 0 && t <= cs.PayTiers[i-1]) {
+			return errs.NewFatal("cluster.pay_tiers must be positive and strictly increasing")
+		}
+	}
+	return nil
+}
+
 // HitSetting 描述遊戲模式輸贏計算的押注型態與線表。
+//
+// MultiplierMode 決定同一筆中獎內多個乘數（符號乘數、格子乘數）的合併方式，留空為相乘；Way 以每條 way 各自合併。
 type HitSetting struct {
-	BetType    BetType   `yaml:"-"           json:"-"`
-	BetTypeStr string    `yaml:"bet_type"    json:"bet_type"`
-	LineTable  [][]int16 `yaml:"line_table"  json:"line_table"`
-	initFlag   bool
+	BetType        BetType        `yaml:"-"           json:"-"`
+	BetTypeStr     string         `yaml:"bet_type"    json:"bet_type"`
+	LineTable      [][]int16      `yaml:"line_table"               json:"line_table"`
+	LineTableRef   string         `yaml:"line_table_ref,omitempty" json:"line_table_ref,omitempty"` // 引用 library.line_tables
+	MultiplierMode string         `yaml:"multiplier_mode,omitempty" json:"multiplier_mode,omitempty"`
+	MultAdd        bool           `yaml:"-"           json:"-"` // MultiplierMode == add
+	Cluster        ClusterSetting `yaml:"cluster,omitempty"        json:"cluster,omitempty"`
+	initFlag       bool
 }
 
 // Init 標記 HitSetting 已初始完成，如後續需要新增驗證可在此擴充。
@@ -107,6 +241,22 @@ func (hs *HitSetting) Init() error {
 	if IsBetTypeLine(hs.BetType) && len(hs.LineTable) == 0 {
 		return errs.NewFatal("bet_type is line but line_table is empty")
 	}
+	if !hs.Cluster.IsZero() {
+		if !IsBetTypeCluster(hs.BetType) {
+			return errs.Fatalf("hit_setting.cluster requires bet_type cluster, got %s", hs.BetTypeStr)
+		}
+		if err := hs.Cluster.valid(); err != nil {
+			return err
+		}
+	}
+	switch hs.MultiplierMode {
+	case "", MultiplierModeMul:
+		hs.MultAdd = false
+	case MultiplierModeAdd:
+		hs.MultAdd = true
+	default:
+		return errs.Fatalf("multiplier_mode error: %s", hs.MultiplierMode)
+	}
 	hs.initFlag = true
 	return nil
 }
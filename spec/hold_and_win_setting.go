@@ -0,0 +1,112 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"github.com/zintix-labs/problab/errs"
+	"github.com/zintix-labs/problab/sdk/sampler"
+)
+
+// HoldAndWinSetting Hold-and-win（respin 收集）設定，供 sdk/holdwin 使用
+//
+// 寫在 game_mode_settings[].hold_and_win，respin 盤面依該模式 gen_screen_setting 的輪帶逐格抽樣。
+//
+// Fields:
+//   - Respins: 初始 respin 次數，每次有新的 coin / collector 落下時重置
+//   - Coins: coin 符號名稱，落下後鎖定並依 CoinValues 抽出數值（押注倍數）
+//   - Collectors: collector 符號名稱（可選），落下時收集盤面上所有 coin 的數值作為自身數值
+//   - CoinValues / CoinWeights: coin 數值與權重，CoinWeights 留空為均等
+//   - FullScreenPrize: 全盤鎖定時的額外獎（押注倍數，0 表示無）
+type HoldAndWinSetting struct {
+	Respins         int      `yaml:"respins"                     json:"respins"`
+	Coins           []string `yaml:"coins"                       json:"coins"`
+	Collectors      []string `yaml:"collectors,omitempty"        json:"collectors,omitempty"`
+	CoinValues      []int    `yaml:"coin_values"                 json:"coin_values"`
+	CoinWeights     []int    `yaml:"coin_weights,omitempty"      json:"coin_weights,omitempty"`
+	FullScreenPrize int      `yaml:"full_screen_prize,omitempty" json:"full_screen_prize,omitempty"`
+
+	CoinMask      uint64      `yaml:"-" json:"-"` // coin 符號遮罩
+	CollectorMask uint64      `yaml:"-" json:"-"` // collector 符號遮罩
+	CoinValueLUT  sampler.LUT `yaml:"-" json:"-"` // coin 數值抽樣表
+}
+
+// init 以已初始化的 SymbolSetting 解析符號並建立數值抽樣表
+func (hw *HoldAndWinSetting) init(ss *SymbolSetting) error {
+	if hw.Respins < 1 {
+		return errs.Fatalf("hold_and_win.respins must be >= 1, got %d", hw.Respins)
+	}
+	if len(hw.Coins) == 0 {
+		return errs.NewFatal("hold_and_win.coins is empty")
+	}
+	var err error
+	if hw.CoinMask, err = symbolMaskOf(ss, "coins", hw.Coins); err != nil {
+		return err
+	}
+	if hw.CollectorMask, err = symbolMaskOf(ss, "collectors", hw.Collectors); err != nil {
+		return err
+	}
+	if hw.CoinMask&hw.CollectorMask != 0 {
+		return errs.NewFatal("hold_and_win: a symbol cannot be both coin and collector")
+	}
+
+	if len(hw.CoinValues) == 0 {
+		return errs.NewFatal("hold_and_win.coin_values is empty")
+	}
+	for _, v := range hw.CoinValues {
+		if v < 0 {
+			return errs.Fatalf("hold_and_win.coin_values must be non-negative, got %d", v)
+		}
+	}
+	if len(hw.CoinWeights) == 0 {
+		hw.CoinWeights = make([]int, len(hw.CoinValues))
+		for i := range hw.CoinWeights {
+			hw.CoinWeights[i] = 1
+		}
+	}
+	if len(hw.CoinWeights) != len(hw.CoinValues) {
+		return errs.NewFatal("hold_and_win: len(coin_weights) != len(coin_values)")
+	}
+	sum := 0
+	for _, w := range hw.CoinWeights {
+		if w < 0 {
+			return errs.NewFatal("hold_and_win.coin_weights must be non-negative")
+		}
+		sum += w
+	}
+	if sum == 0 {
+		return errs.NewFatal("hold_and_win.coin_weights are all zero")
+	}
+	if hw.FullScreenPrize < 0 {
+		return errs.Fatalf("hold_and_win.full_screen_prize must be >= 0, got %d", hw.FullScreenPrize)
+	}
+	hw.CoinValueLUT = sampler.BuildLUT(hw.CoinWeights)
+	return nil
+}
+
+// symbolMaskOf 依名稱查出符號遮罩
+func symbolMaskOf(ss *SymbolSetting, field string, names []string) (uint64, error) {
+	mask := uint64(0)
+	for _, name := range names {
+		id, ok := ss.Lookup(name)
+		if !ok {
+			return 0, errs.Fatalf("hold_and_win.%s: unknown symbol %s", field, name)
+		}
+		if id >= 64 {
+			return 0, errs.Fatalf("hold_and_win.%s: symbol %s index out of range", field, name)
+		}
+		mask |= 1 << uint(id)
+	}
+	return mask, nil
+}
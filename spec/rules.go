@@ -0,0 +1,479 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/zintix-labs/problab/errs"
+)
+
+// MaxSymbolCount 單一模式可使用的最大符號數（calc.SymbolMask 為 uint64）
+const MaxSymbolCount int = 64
+
+// RuleCheckFn 檢查已初始化的 GameSetting，透過 report 回報每一個違規（path 為 YAML 路徑）
+type RuleCheckFn func(gs *GameSetting, report func(path string, format string, a ...any))
+
+// Rule 具名的語意檢查規則
+//
+// Level 為預設嚴重度：
+//   - errs.Fatal：阻止註冊
+//   - errs.Warn / errs.Log：僅回報
+//   - errs.None：停用
+//
+// Invariant 規則保護執行期的前提（違反時算分 / 生成會 panic 或算錯），一律為 Fatal：
+// 嚴重度不可覆寫，且解碼設定檔（GetGameSettingByYAML 等）時即強制檢查。
+type Rule struct {
+	Name      string
+	Level     errs.ErrLevel
+	Desc      string
+	Check     RuleCheckFn
+	Invariant bool
+}
+
+// RuleLevels 以規則名稱覆寫嚴重度（errs.None 代表停用該規則）
+type RuleLevels map[string]errs.ErrLevel
+
+// Valid 檢查覆寫的規則名稱皆存在，且未降級 Invariant 規則
+func (rl RuleLevels) Valid() error {
+	for name, lv := range rl {
+		r, ok := ruleByName(name)
+		if !ok {
+			return errs.Warnf("unknown rule: %s", name)
+		}
+		if r.Invariant && lv != errs.Fatal {
+			return errs.Warnf("rule %s is an invariant and cannot be downgraded", name)
+		}
+	}
+	return nil
+}
+
+// Violation 單一規則違規
+type Violation struct {
+	File    string        `json:"file,omitempty"`
+	Rule    string        `json:"rule"`
+	Level   errs.ErrLevel `json:"level"`
+	Path    string        `json:"path"`
+	Message string        `json:"message"`
+}
+
+func (v Violation) String() string {
+	var b strings.Builder
+	if v.File != "" {
+		b.WriteString(v.File)
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "[%s %s] ", errs.ErrLv(v.Level), v.Rule)
+	if v.Path != "" {
+		b.WriteString(v.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(v.Message)
+	return b.String()
+}
+
+// HasFatal 回傳違規清單中是否存在 Fatal 等級
+func HasFatal(vs []Violation) bool {
+	for _, v := range vs {
+		if v.Level == errs.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ruleMu sync.RWMutex
+	rules  = builtinRules()
+)
+
+// Rules 回傳目前所有規則（依註冊順序）
+func Rules() []Rule {
+	ruleMu.RLock()
+	defer ruleMu.RUnlock()
+	return slices.Clone(rules)
+}
+
+// RegisterRule 註冊自訂規則（例如特定 LogicKey 專屬的檢查），名稱不可重複
+func RegisterRule(r Rule) error {
+	if r.Name == "" || r.Check == nil {
+		return errs.NewFatal("rule name and check are required")
+	}
+	ruleMu.Lock()
+	defer ruleMu.Unlock()
+	for _, x := range rules {
+		if x.Name == r.Name {
+			return errs.Fatalf("rule already registered: %s", r.Name)
+		}
+	}
+	rules = append(rules, r)
+	return nil
+}
+
+func ruleByName(name string) (Rule, bool) {
+	ruleMu.RLock()
+	defer ruleMu.RUnlock()
+	for _, r := range rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// CheckRules 對已初始化的 GameSetting 執行所有規則，回傳全部違規（不會在第一個違規就停止）
+//
+// Invariant 規則忽略 levels 的覆寫。
+func CheckRules(gs *GameSetting, levels RuleLevels) []Violation {
+	vs := make([]Violation, 0)
+	for _, r := range Rules() {
+		lv := r.Level
+		if o, ok := levels[r.Name]; ok && !r.Invariant {
+			lv = o
+		}
+		if lv == errs.None {
+			continue
+		}
+		r.Check(gs, func(path string, format string, a ...any) {
+			vs = append(vs, Violation{
+				Rule:    r.Name,
+				Level:   lv,
+				Path:    path,
+				Message: fmt.Sprintf(format, a...),
+			})
+		})
+	}
+	return vs
+}
+
+// checkInvariants 執行 Invariant 規則，任一違規即回傳錯誤（解碼設定檔時呼叫）
+func checkInvariants(gs *GameSetting) error {
+	lines := make([]string, 0)
+	for _, r := range Rules() {
+		if !r.Invariant {
+			continue
+		}
+		r.Check(gs, func(path string, format string, a ...any) {
+			lines = append(lines, Violation{Rule: r.Name, Level: errs.Fatal, Path: path, Message: fmt.Sprintf(format, a...)}.String())
+		})
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return errs.Fatalf("rule violations:\n%s", strings.Join(lines, "\n"))
+}
+
+// ============================================================
+// ** 內建規則 **
+// ============================================================
+
+func builtinRules() []Rule {
+	return []Rule{
+		{Name: "symbol_count_max", Level: errs.Fatal, Desc: "a mode must not define more than 64 symbols (SymbolMask is uint64)", Check: ruleSymbolCountMax, Invariant: true},
+		{Name: "symbol_duplicate", Level: errs.Fatal, Desc: "symbol names must be unique within a mode", Check: ruleSymbolDuplicate},
+		{Name: "pay_table_negative", Level: errs.Fatal, Desc: "pay_table must not contain negative pays", Check: rulePayTableNegative},
+		{Name: "pay_table_width", Level: errs.Fatal, Desc: "pay_table rows must share one width, covering every column for line/way", Check: rulePayTableWidth, Invariant: true},
+		{Name: "line_table_width", Level: errs.Fatal, Desc: "every line must have one entry per column", Check: ruleLineTableWidth, Invariant: true},
+		{Name: "line_table_bounds", Level: errs.Fatal, Desc: "line entries must address a row inside the screen", Check: ruleLineTableBounds, Invariant: true},
+		{Name: "line_table_masked", Level: errs.Fatal, Desc: "line entries should not address a masked cell", Check: ruleLineTableMasked},
+		{Name: "line_table_duplicate", Level: errs.Warn, Desc: "lines should be unique", Check: ruleLineTableDuplicate},
+		{Name: "cluster_wild_pay", Level: errs.Warn, Desc: "wild symbols should not pay on cluster games", Check: ruleClusterWildPay},
+		{Name: "cluster_pay_tiers", Level: errs.Fatal, Desc: "cluster pay_table rows must have one entry per pay tier", Check: ruleClusterPayTiers},
+		{Name: "mask_values", Level: errs.Fatal, Desc: "mask values must be 0 or 1", Check: ruleMaskValues},
+		{Name: "mask_bet_type", Level: errs.Fatal, Desc: "line and way screens must keep at least one open cell per column", Check: ruleMaskBetType},
+		{Name: "reel_count", Level: errs.Fatal, Desc: "every reel set must provide one reel per column", Check: ruleReelCount, Invariant: true},
+		{Name: "reel_symbols", Level: errs.Fatal, Desc: "reel symbols must index a symbol of the mode", Check: ruleReelSymbols, Invariant: true},
+		{Name: "reel_weights", Level: errs.Fatal, Desc: "reel and reel set weights must be non-negative and not all zero", Check: ruleReelWeights, Invariant: true},
+		{Name: "reel_heights", Level: errs.Fatal, Desc: "variable reel heights need a non-line bet type", Check: ruleReelHeights},
+	}
+}
+
+func modePath(i int, rest string) string {
+	return fmt.Sprintf("game_mode_settings[%d].%s", i, rest)
+}
+
+// symbolPath 回傳符號設定的路徑：依設定方式指向 symbol_used 或 symbols（j < 0 表示整個清單）
+func symbolPath(i int, ss *SymbolSetting, j int) string {
+	switch {
+	case len(ss.Symbols) > 0 && j >= 0:
+		return modePath(i, fmt.Sprintf("symbol_setting.symbols[%d].name", j))
+	case len(ss.Symbols) > 0:
+		return modePath(i, "symbol_setting.symbols")
+	case j >= 0:
+		return modePath(i, fmt.Sprintf("symbol_setting.symbol_used[%d]", j))
+	}
+	return modePath(i, "symbol_setting.symbol_used")
+}
+
+func ruleSymbolCountMax(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if m.SymbolSetting.SymbolCount > MaxSymbolCount {
+			report(symbolPath(i, &m.SymbolSetting, -1), "%d symbols used, max %d", m.SymbolSetting.SymbolCount, MaxSymbolCount)
+		}
+	}
+}
+
+func ruleSymbolDuplicate(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		seen := make(map[string]int, len(m.SymbolSetting.SymbolUsedStr))
+		for j, s := range m.SymbolSetting.SymbolUsedStr {
+			if k, ok := seen[s]; ok {
+				report(symbolPath(i, &m.SymbolSetting, j), "duplicate symbol %s (first at index %d)", s, k)
+				continue
+			}
+			seen[s] = j
+		}
+	}
+}
+
+func rulePayTableNegative(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		for r, row := range m.SymbolSetting.PayTable {
+			for c, v := range row {
+				if v < 0 {
+					report(modePath(i, fmt.Sprintf("symbol_setting.pay_table[%d][%d]", r, c)), "negative pay %d", v)
+				}
+			}
+		}
+	}
+}
+
+func rulePayTableWidth(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		pt := m.SymbolSetting.PayTable
+		if len(pt) == 0 {
+			continue
+		}
+		width := len(pt[0])
+		bt := m.HitSetting.BetType
+		if cols := m.ScreenSetting.Columns; (IsBetTypeLine(bt) || IsBetTypeWay(bt)) && width < cols {
+			report(modePath(i, "symbol_setting.pay_table[0]"), "row has %d pays but screen has %d columns", width, cols)
+		}
+		for r, row := range pt[1:] {
+			if len(row) != width {
+				report(modePath(i, fmt.Sprintf("symbol_setting.pay_table[%d]", r+1)), "row has %d pays, pay_table[0] has %d", len(row), width)
+			}
+		}
+	}
+}
+
+func ruleLineTableWidth(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if !IsBetTypeLine(m.HitSetting.BetType) {
+			continue
+		}
+		cols := m.ScreenSetting.Columns
+		for l, line := range m.HitSetting.LineTable {
+			if len(line) != cols {
+				report(modePath(i, fmt.Sprintf("hit_setting.line_table[%d]", l)), "line has %d entries but screen has %d columns", len(line), cols)
+			}
+		}
+	}
+}
+
+func ruleLineTableBounds(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if !IsBetTypeLine(m.HitSetting.BetType) {
+			continue
+		}
+		rows := m.ScreenSetting.Rows
+		for l, line := range m.HitSetting.LineTable {
+			for c, r := range line {
+				if r < 0 || int(r) >= rows {
+					report(modePath(i, fmt.Sprintf("hit_setting.line_table[%d][%d]", l, c)), "row %d outside screen (rows=%d)", r, rows)
+				}
+			}
+		}
+	}
+}
+
+func ruleLineTableMasked(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		ss := m.ScreenSetting
+		if !IsBetTypeLine(m.HitSetting.BetType) || len(ss.Mask) != ss.ScreenSize {
+			continue
+		}
+		for l, line := range m.HitSetting.LineTable {
+			for c, r := range line {
+				if r >= 0 && int(r) < ss.Rows && c < ss.Columns && ss.Mask[int(r)*ss.Columns+c] == 0 {
+					report(modePath(i, fmt.Sprintf("hit_setting.line_table[%d][%d]", l, c)), "row %d column %d is masked out", r, c)
+				}
+			}
+		}
+	}
+}
+
+func ruleLineTableDuplicate(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if !IsBetTypeLine(m.HitSetting.BetType) {
+			continue
+		}
+		seen := make(map[string]int, len(m.HitSetting.LineTable))
+		for l, line := range m.HitSetting.LineTable {
+			key := fmt.Sprint(line)
+			if k, ok := seen[key]; ok {
+				report(modePath(i, fmt.Sprintf("hit_setting.line_table[%d]", l)), "duplicate of line %d", k)
+				continue
+			}
+			seen[key] = l
+		}
+	}
+}
+
+func ruleClusterWildPay(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if !IsBetTypeCluster(m.HitSetting.BetType) {
+			continue
+		}
+		ss := m.SymbolSetting
+		for s := range ss.SymbolCount {
+			if !ss.HasRole(s, SymbolRoleWild) || s >= len(ss.PayTable) {
+				continue
+			}
+			if slices.ContainsFunc(ss.PayTable[s], func(v int) bool { return v != 0 }) {
+				report(modePath(i, fmt.Sprintf("symbol_setting.pay_table[%d]", s)), "wild %s pays on a cluster game", ss.SymbolUsedStr[s])
+			}
+		}
+	}
+}
+
+func ruleClusterPayTiers(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		tiers := m.HitSetting.Cluster.PayTiers
+		if !IsBetTypeCluster(m.HitSetting.BetType) || len(tiers) == 0 {
+			continue
+		}
+		for s, row := range m.SymbolSetting.PayTable {
+			if len(row) != len(tiers) {
+				report(modePath(i, fmt.Sprintf("symbol_setting.pay_table[%d]", s)), "%d pays for %d pay tiers", len(row), len(tiers))
+			}
+		}
+	}
+}
+
+func ruleMaskValues(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		for j, v := range m.ScreenSetting.Mask {
+			if v > 1 {
+				report(modePath(i, fmt.Sprintf("screen_setting.mask[%d]", j)), "mask value must be 0 or 1, got %d", v)
+			}
+		}
+	}
+}
+
+func ruleMaskBetType(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		bt := m.HitSetting.BetType
+		if !IsBetTypeLine(bt) && !IsBetTypeWay(bt) {
+			continue
+		}
+		ss := m.ScreenSetting
+		if len(ss.Mask) != ss.ScreenSize {
+			continue
+		}
+		// 線與 Ways 皆需逐軸連線，整軸被遮罩的盤面永遠無法連到之後的軸
+		for c := range ss.Columns {
+			open := false
+			for r := range ss.Rows {
+				if ss.Mask[r*ss.Columns+c] != 0 {
+					open = true
+					break
+				}
+			}
+			if !open {
+				report(modePath(i, "screen_setting.mask"), "column %d has no open cell for bet_type %s", c, m.HitSetting.BetTypeStr)
+			}
+		}
+	}
+}
+
+func ruleReelCount(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		cols := m.ScreenSetting.Columns
+		for r, rs := range m.GenScreenSetting.ReelSetGroup {
+			if len(rs.Reels) < cols {
+				report(modePath(i, fmt.Sprintf("gen_screen_setting.reel_set_group[%d].reels", r)), "%d reels for %d columns", len(rs.Reels), cols)
+			}
+		}
+	}
+}
+
+func ruleReelSymbols(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		count := int16(m.SymbolSetting.SymbolCount)
+		check := func(path string, reel Reel) {
+			for k, sym := range reel.ReelSymbols {
+				if sym < 0 || sym >= count {
+					report(modePath(i, fmt.Sprintf("%s.symbols[%d]", path, k)), "symbol %d out of range (%d symbols)", sym, count)
+				}
+			}
+		}
+		g := m.GenScreenSetting
+		for r, rs := range g.ReelSetGroup {
+			for j, reel := range rs.Reels {
+				check(fmt.Sprintf("gen_screen_setting.reel_set_group[%d].reels[%d]", r, j), reel)
+			}
+		}
+		if g.TopReel != nil {
+			check("gen_screen_setting.top_reel.reel", g.TopReel.Reel)
+		}
+	}
+}
+
+func ruleReelHeights(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		if m.GenScreenSetting.GenReelType == GenReelByHeight && IsBetTypeLine(m.HitSetting.BetType) {
+			report(modePath(i, "hit_setting.bet_type"), "bet_type %s does not support variable reel heights", m.HitSetting.BetTypeStr)
+		}
+	}
+}
+
+func ruleReelWeights(gs *GameSetting, report func(string, string, ...any)) {
+	for i, m := range gs.GameModeSettings {
+		check := func(path string, reel Reel) {
+			sum := 0
+			for k, w := range reel.ReelWeights {
+				if w < 0 {
+					report(modePath(i, fmt.Sprintf("%s.weights[%d]", path, k)), "negative weight %d", w)
+				}
+				sum += max(w, 0)
+			}
+			if sum == 0 {
+				report(modePath(i, path+".weights"), "all weights are zero")
+			}
+		}
+		g := m.GenScreenSetting
+		total := 0
+		for r, rs := range g.ReelSetGroup {
+			rsPath := fmt.Sprintf("gen_screen_setting.reel_set_group[%d]", r)
+			if rs.Weight < 0 {
+				report(modePath(i, rsPath+".weight"), "negative weight %d", rs.Weight)
+			}
+			total += max(rs.Weight, 0)
+			for j, reel := range rs.Reels {
+				check(fmt.Sprintf("%s.reels[%d]", rsPath, j), reel)
+			}
+		}
+		if len(g.ReelSetGroup) > 0 && total == 0 {
+			report(modePath(i, "gen_screen_setting.reel_set_group"), "all reel set weights are zero")
+		}
+		if g.TopReel != nil {
+			check("gen_screen_setting.top_reel.reel", g.TopReel.Reel)
+		}
+	}
+}
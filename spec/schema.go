@@ -0,0 +1,184 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaDraft JSON Schema 版本
+const SchemaDraft string = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema JSON Schema 的子集合，足以描述 GameSetting，並同時供 ValidateYAML 使用。
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// schemaRequired 各設定結構的必填欄位（以 yaml 名稱表示）
+var schemaRequired = map[reflect.Type][]string{
+	reflect.TypeFor[GameSetting]():       {"game_name", "game_id", "logic_key", "bet_units", "max_win_limit", "game_mode_settings"},
+	reflect.TypeFor[GameModeSetting]():   {"screen_setting", "gen_screen_setting", "symbol_setting", "hit_setting"},
+	reflect.TypeFor[ScreenSetting]():     {"columns", "rows"},
+	reflect.TypeFor[GenScreenSetting]():  {"gen_reel_type"},
+	reflect.TypeFor[ReelSet]():           {}, // reels 與 ref 擇一
+	reflect.TypeFor[Reel]():              {"symbols"},
+	reflect.TypeFor[HeightTable]():       {"heights"},
+	reflect.TypeFor[TopReel]():           {"columns", "reel"},
+	reflect.TypeFor[SymbolSetting]():     {}, // symbol_used / symbols 與 pay_table / pay_table_ref 各擇一，由 Init 檢查
+	reflect.TypeFor[SymbolDef]():         {"name"},
+	reflect.TypeFor[HitSetting]():        {"bet_type"},
+	reflect.TypeFor[HoldAndWinSetting](): {"respins", "coins", "coin_values"},
+}
+
+// schemaEnums 字串欄位的列舉值，key 為 (結構型別, yaml 名稱)；切片欄位套用在 items 上
+func schemaEnums() map[reflect.Type]map[string][]string {
+	return map[reflect.Type]map[string][]string{
+		reflect.TypeFor[HitSetting]():       {"bet_type": BetTypeNames(), "multiplier_mode": {MultiplierModeAdd, MultiplierModeMul}},
+		reflect.TypeFor[GenScreenSetting](): {"gen_reel_type": GenReelTypeNames()},
+		reflect.TypeFor[ClusterSetting]():   {"topology": ClusterTopologyNames()},
+		reflect.TypeFor[SymbolSetting]():    {"symbol_used": SymbolNames()},
+		reflect.TypeFor[SymbolDef]():        {"roles": SymbolRoleNames()},
+	}
+}
+
+// BetTypeNames 回傳所有合法的 bet_type（含已註冊的自訂類型；已排序）
+func BetTypeNames() []string {
+	betTypeMu.RLock()
+	defer betTypeMu.RUnlock()
+	return sortedKeys(betTypeMap)
+}
+
+// GenReelTypeNames 回傳所有合法的 gen_reel_type（含已註冊的自訂類型；已排序，不含 GenReelTypeNone）
+func GenReelTypeNames() []string {
+	genMu.RLock()
+	defer genMu.RUnlock()
+	names := make([]string, 0, len(genReelTypeMap))
+	for k, v := range genReelTypeMap {
+		if v != GenReelTypeNone {
+			names = append(names, k)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SymbolNames 回傳所有合法的符號名稱（依 Symbol 數值排序）
+func SymbolNames() []string {
+	names := sortedKeys(symbolMap)
+	slices.SortFunc(names, func(a, b string) int { return int(symbolMap[a]) - int(symbolMap[b]) })
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// GameSettingSchema 由 GameSetting 的 yaml tag 反射產生 JSON Schema
+func GameSettingSchema() *Schema {
+	s := schemaOf(reflect.TypeFor[GameSetting](), schemaEnums())
+	s.Draft = SchemaDraft
+	s.Title = "problab GameSetting"
+	return s
+}
+
+// GameSettingSchemaJSON 以縮排 JSON 輸出 GameSettingSchema
+func GameSettingSchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(GameSettingSchema(), "", "  ")
+}
+
+func schemaOf(t reflect.Type, enums map[reflect.Type]map[string][]string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		closed := false
+		s := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: &closed,
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name := yamlName(f)
+			if name == "" {
+				continue
+			}
+			fs := schemaOf(f.Type, enums)
+			if e, ok := enums[t][name]; ok {
+				if fs.Items != nil {
+					fs.Items.Enum = e
+				} else {
+					fs.Enum = e
+				}
+			}
+			s.Properties[name] = fs
+		}
+		s.Required = schemaRequired[t]
+		return s
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), enums)}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+// yamlName 回傳欄位的 yaml 名稱；未匯出或標記為 "-" 者回傳空字串
+func yamlName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag, ok := f.Tag.Lookup("yaml")
+	if !ok {
+		return strings.ToLower(f.Name)
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
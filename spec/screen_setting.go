@@ -16,6 +16,11 @@ package spec
 
 import "github.com/zintix-labs/problab/errs"
 
+// CellBlocked 被遮罩（不存在）的格子在盤面上的值。
+//
+// 生成器不會寫入這些格子；算分器視為非圖標直接略過；Gravity / 補盤會跳過它們（圖標穿過遮罩格往下掉）。
+const CellBlocked int16 = -1
+
 // ScreenSetting 描述盤面樣式的設定。
 //
 // Fields:
@@ -23,12 +28,15 @@ import "github.com/zintix-labs/problab/errs"
 //   - Rows: 盤面列數
 //   - Damp: 額外上下圖標顆數
 //   - Mask: 盤面遮罩，0 為空、1 代表有格子；若留空則代表完整矩陣。
+//     例如 5x5 的 3-4-5-4-3 菱形盤面，或任意封鎖格。
 type ScreenSetting struct {
 	Columns    int     `yaml:"columns"   json:"columns"`
 	Rows       int     `yaml:"rows"      json:"rows"`
 	Damp       int     `yaml:"damp"      json:"damp"`
-	Mask       []uint8 `yaml:"mask"      json:"mask"`
+	Mask       []uint8 `yaml:"mask,omitempty" json:"mask,omitempty"`
 	ScreenSize int     `yaml:"-"         json:"-"`
+	Irregular  bool    `yaml:"-"         json:"-"` // Mask 中有 0（非完整矩陣）
+	ColCells   [][]int `yaml:"-"         json:"-"` // 每軸可用格子的盤面 idx（由上到下）
 	initFlag   bool
 }
 
@@ -39,6 +47,9 @@ func (ss *ScreenSetting) Init() error {
 		return nil
 	}
 	// 檢查合法性
+	if ss.Columns <= 0 || ss.Rows <= 0 {
+		return errs.Fatalf("invalid screen dimensions: cols=%d rows=%d", ss.Columns, ss.Rows)
+	}
 	ss.ScreenSize = ss.Rows * ss.Columns
 	// 如果Mask 不是nil，Columns x Rows 要等於 Mask長度
 	if ss.Mask != nil {
@@ -46,6 +57,29 @@ func (ss *ScreenSetting) Init() error {
 			return errs.NewFatal("len(mask) != screen size")
 		}
 	}
+	ss.Irregular = false
+	ss.ColCells = make([][]int, ss.Columns)
+	for c := range ss.Columns {
+		ss.ColCells[c] = make([]int, 0, ss.Rows)
+		for r := range ss.Rows {
+			idx := r*ss.Columns + c
+			if !ss.Open(idx) {
+				ss.Irregular = true
+				continue
+			}
+			ss.ColCells[c] = append(ss.ColCells[c], idx)
+		}
+	}
 	ss.initFlag = true
 	return nil
 }
+
+// Open 回傳盤面 idx 是否為可用格子（未設定 Mask 時全部可用）
+func (ss *ScreenSetting) Open(idx int) bool {
+	return len(ss.Mask) == 0 || ss.Mask[idx] != 0
+}
+
+// ColHeight 回傳第 c 軸的可用格數
+func (ss *ScreenSetting) ColHeight(c int) int {
+	return len(ss.ColCells[c])
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zintix-labs/problab/errs"
+)
+
+// 試算表 CSV 格式
+//
+// 輪帶：每軸一欄，表頭為 "Reel N"（內容為圖標名稱），可選 "Weight N" 欄位（該軸各停點權重）；
+// "Stop" 欄位（停點編號）會被忽略。較短的輪帶在尾端留空。
+//
+//	Stop,Reel 1,Reel 2,Reel 3,Weight 3
+//	0,H1,L1,W1,3
+//	1,L2,H1,L1,1
+//
+// 賠付表：第一欄為圖標名稱，其後為數量 1..N 的賠付；未列出的圖標視為全 0。
+//
+//	Symbol,1,2,3,4,5
+//	H1,0,0,50,100,500
+
+// ReadReelsCSV 讀取輪帶 CSV，依 ss 的圖標名稱驗證並轉成 []Reel（ss 需已初始化）
+func ReadReelsCSV(r io.Reader, ss *SymbolSetting) ([]Reel, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errs.NewFatal("reels csv: empty")
+	}
+	symCol := map[int]int{} // 欄位 -> 軸
+	wCol := map[int]int{}
+	reels := 0
+	for c, h := range rows[0] {
+		h = strings.TrimSpace(h)
+		switch {
+		case strings.EqualFold(h, "stop"), h == "":
+		case hasIndexPrefix(h, "reel"):
+			n, err := headerIndex(h, "reel")
+			if err != nil {
+				return nil, err
+			}
+			symCol[c] = n
+			reels = max(reels, n+1)
+		case hasIndexPrefix(h, "weight"):
+			n, err := headerIndex(h, "weight")
+			if err != nil {
+				return nil, err
+			}
+			wCol[c] = n
+		default:
+			return nil, errs.NewFatal(fmt.Sprintf("reels csv: unknown column %q", h))
+		}
+	}
+	if reels == 0 {
+		return nil, errs.NewFatal("reels csv: no \"Reel N\" columns")
+	}
+	if len(symCol) != reels {
+		return nil, errs.NewFatal("reels csv: reel columns must be Reel 1..N without gaps or duplicates")
+	}
+	out := make([]Reel, reels)
+	ended := make([]bool, reels)
+	for i, row := range rows[1:] {
+		line := i + 2
+		for c, v := range row {
+			v = strings.TrimSpace(v)
+			if k, ok := symCol[c]; ok {
+				if v == "" {
+					ended[k] = true
+					continue
+				}
+				if ended[k] {
+					return nil, errs.NewFatal(fmt.Sprintf("reels csv:%d: Reel %d: gap in reel strip", line, k+1))
+				}
+				s, ok := ss.Lookup(v)
+				if !ok {
+					return nil, errs.NewFatal(fmt.Sprintf("reels csv:%d: Reel %d: unknown symbol %q (symbol_used: %s)", line, k+1, v, strings.Join(ss.SymbolUsedStr, ",")))
+				}
+				out[k].ReelSymbols = append(out[k].ReelSymbols, int16(s))
+			}
+		}
+		for c, v := range row {
+			k, ok := wCol[c]
+			if !ok || strings.TrimSpace(v) == "" {
+				continue
+			}
+			if k >= reels {
+				return nil, errs.NewFatal(fmt.Sprintf("reels csv:%d: Weight %d has no matching reel", line, k+1))
+			}
+			w, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil || w < 0 {
+				return nil, errs.NewFatal(fmt.Sprintf("reels csv:%d: Weight %d: invalid weight %q", line, k+1, v))
+			}
+			out[k].ReelWeights = append(out[k].ReelWeights, w)
+		}
+	}
+	for k := range out {
+		if len(out[k].ReelSymbols) == 0 {
+			return nil, errs.NewFatal(fmt.Sprintf("reels csv: Reel %d is empty", k+1))
+		}
+		if out[k].ReelWeights != nil && len(out[k].ReelWeights) != len(out[k].ReelSymbols) {
+			return nil, errs.NewFatal(fmt.Sprintf("reels csv: Reel %d has %d symbols but %d weights", k+1, len(out[k].ReelSymbols), len(out[k].ReelWeights)))
+		}
+	}
+	return out, nil
+}
+
+// WriteReelsCSV 輸出輪帶 CSV；只有非等權重的輪帶才輸出 Weight 欄位
+func WriteReelsCSV(w io.Writer, reels []Reel, ss *SymbolSetting) error {
+	header := []string{"Stop"}
+	length := 0
+	weighted := make([]int, 0)
+	for k, reel := range reels {
+		header = append(header, fmt.Sprintf("Reel %d", k+1))
+		length = max(length, len(reel.ReelSymbols))
+		for _, wt := range reel.ReelWeights {
+			if wt != 1 {
+				weighted = append(weighted, k)
+				break
+			}
+		}
+	}
+	for _, k := range weighted {
+		header = append(header, fmt.Sprintf("Weight %d", k+1))
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for p := range length {
+		row := []string{strconv.Itoa(p)}
+		for k, reel := range reels {
+			cell := ""
+			if p < len(reel.ReelSymbols) {
+				s := int(reel.ReelSymbols[p])
+				if s < 0 || s >= len(ss.SymbolUsedStr) {
+					return errs.NewFatal(fmt.Sprintf("reel %d stop %d: symbol %d out of range", k+1, p, s))
+				}
+				cell = ss.SymbolUsedStr[s]
+			}
+			row = append(row, cell)
+		}
+		for _, k := range weighted {
+			cell := ""
+			if p < len(reels[k].ReelWeights) {
+				cell = strconv.Itoa(reels[k].ReelWeights[p])
+			}
+			row = append(row, cell)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadPayTableCSV 讀取賠付表 CSV，依 symbol_used 順序回傳（ss 需已初始化）
+func ReadPayTableCSV(r io.Reader, ss *SymbolSetting) ([][]int, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || len(rows[0]) < 2 {
+		return nil, errs.NewFatal("pay table csv: header must be Symbol,1,2,...,N")
+	}
+	width := len(rows[0]) - 1
+	for c, h := range rows[0][1:] {
+		if n, err := strconv.Atoi(strings.TrimSpace(h)); err != nil || n != c+1 {
+			return nil, errs.NewFatal(fmt.Sprintf("pay table csv: column %d header must be %d, got %q", c+2, c+1, h))
+		}
+	}
+	pt := make([][]int, ss.SymbolCount)
+	for i := range pt {
+		pt[i] = make([]int, width)
+	}
+	seen := make(map[int]int, ss.SymbolCount)
+	for i, row := range rows[1:] {
+		line := i + 2
+		name := strings.TrimSpace(row[0])
+		if name == "" {
+			continue
+		}
+		s, ok := ss.Lookup(name)
+		if !ok {
+			return nil, errs.NewFatal(fmt.Sprintf("pay table csv:%d: unknown symbol %q (symbol_used: %s)", line, name, strings.Join(ss.SymbolUsedStr, ",")))
+		}
+		if prev, ok := seen[s]; ok {
+			return nil, errs.NewFatal(fmt.Sprintf("pay table csv:%d: duplicate symbol %s (first at line %d)", line, name, prev))
+		}
+		seen[s] = line
+		if len(row)-1 > width {
+			return nil, errs.NewFatal(fmt.Sprintf("pay table csv:%d: %d pays but header has %d", line, len(row)-1, width))
+		}
+		for c, v := range row[1:] {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			pay, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, errs.NewFatal(fmt.Sprintf("pay table csv:%d: column %d: invalid pay %q", line, c+1, v))
+			}
+			pt[s][c] = pay
+		}
+	}
+	return pt, nil
+}
+
+// WritePayTableCSV 依 symbol_used 順序輸出賠付表 CSV
+func WritePayTableCSV(w io.Writer, ss *SymbolSetting) error {
+	width := 0
+	for _, row := range ss.PayTable {
+		width = max(width, len(row))
+	}
+	header := []string{"Symbol"}
+	for c := 1; c <= width; c++ {
+		header = append(header, strconv.Itoa(c))
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for s, row := range ss.PayTable {
+		r := make([]string, 1, width+1)
+		r[0] = ss.SymbolUsedStr[s]
+		for c := range width {
+			v := 0
+			if c < len(row) {
+				v = row[c]
+			}
+			r = append(r, strconv.Itoa(v))
+		}
+		if err := cw.Write(r); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, errs.Wrap(err, "read csv failed")
+	}
+	return rows, nil
+}
+
+func hasIndexPrefix(h, prefix string) bool {
+	return len(h) > len(prefix) && strings.EqualFold(h[:len(prefix)], prefix)
+}
+
+// headerIndex 解析 "Reel 3" / "Weight3" 形式的表頭，回傳 0 起算的索引
+func headerIndex(h, prefix string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(h[len(prefix):]))
+	if err != nil || n < 1 {
+		return 0, errs.NewFatal(fmt.Sprintf("csv: invalid column %q", h))
+	}
+	return n - 1, nil
+}
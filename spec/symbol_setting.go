@@ -20,51 +20,96 @@ import (
 	"github.com/zintix-labs/problab/errs"
 )
 
-// SymbolSetting 統整模式中的所有符號，並記錄衍生屬性（類型、賠付表、總數等）。
+// SymbolSetting 統整模式中的所有符號，並記錄衍生屬性（類型、角色、賠付表、總數等）。
+//
+// 符號有兩種寫法（擇一）：
+//   - symbol_used：沿用固定短名稱表（Z/S/C/W/H/L + 1~9），角色由前綴推導。
+//   - symbols：自訂名稱、角色旗標（可多個，例如同時是 scatter 與 wild）與顯示資訊。
+//     名稱若為短名稱且未填 roles，角色同樣由前綴推導。
 type SymbolSetting struct {
-	SymbolUsedStr []string     `yaml:"symbol_used"  json:"symbol_used"`
-	PayTable      [][]int      `yaml:"pay_table"    json:"pay_table"`
-	SymbolUsed    []Symbol     `yaml:"-"           json:"-"`
-	SymbolTypes   []SymbolType `yaml:"-"           json:"-"`
-	SymbolCount   int          `yaml:"-"           json:"-"`
-	PayTableFlat  []int        `yaml:"-"           json:"-"`
-	PayTableIndex []int        `yaml:"-"           json:"-"`
+	SymbolUsedStr []string       `yaml:"symbol_used,omitempty" json:"symbol_used,omitempty"`
+	Symbols       []SymbolDef    `yaml:"symbols,omitempty"     json:"symbols,omitempty"`
+	PayTable      [][]int        `yaml:"pay_table"             json:"pay_table"`
+	PayTableRef   string         `yaml:"pay_table_ref,omitempty" json:"pay_table_ref,omitempty"` // 引用 library.pay_tables
+	SymbolUsed    []Symbol       `yaml:"-"           json:"-"`                                   // 短名稱對應的 Symbol，自訂名稱為 SymbolCustom
+	SymbolTypes   []SymbolType   `yaml:"-"           json:"-"`                                   // 主要類型（第一個角色），供舊邏輯相容
+	SymbolRoles   []SymbolRole   `yaml:"-"           json:"-"`                                   // 全部角色旗標
+	SymbolIndex   map[string]int `yaml:"-"           json:"-"`                                   // 名稱 -> 符號索引
+	SymbolCount   int            `yaml:"-"           json:"-"`
+	PayTableFlat  []int          `yaml:"-"           json:"-"`
+	PayTableIndex []int          `yaml:"-"           json:"-"`
+	SymbolMults   []int          `yaml:"-"           json:"-"` // 每個符號自帶的乘數（0 表示無），來自 symbols[].multiplier
 	initFlag      bool
 }
 
+// SymbolDef 自訂符號定義
+//
+// Multiplier 大於 1 時，該符號參與的每筆中獎都會套用此乘數（例如 2 倍 wild），合併方式見 HitSetting.MultiplierMode。
+type SymbolDef struct {
+	Name       string        `yaml:"name"                 json:"name"`
+	Roles      []string      `yaml:"roles,omitempty"      json:"roles,omitempty"`
+	Multiplier int           `yaml:"multiplier,omitempty" json:"multiplier,omitempty"`
+	Display    SymbolDisplay `yaml:"display,omitempty"    json:"display,omitempty"`
+}
+
+// SymbolDisplay 符號的顯示資訊，僅供前端與報表使用，不影響算分
+type SymbolDisplay struct {
+	Label string `yaml:"label,omitempty" json:"label,omitempty"`
+	Color string `yaml:"color,omitempty" json:"color,omitempty"`
+	Asset string `yaml:"asset,omitempty" json:"asset,omitempty"`
+}
+
+// MaxSymbolNameLen 自訂符號名稱長度上限
+const MaxSymbolNameLen int = 32
+
 // Init 檢查設定並賦值
 func (ss *SymbolSetting) Init() error {
 	// 檢查初始化旗標
 	if ss.initFlag {
 		return nil
 	}
-	// 解析SymbolUsed
-	if ss.SymbolUsed == nil {
+	if len(ss.SymbolUsedStr) > 0 && len(ss.Symbols) > 0 {
+		return errs.NewFatal("symbol_used and symbols are mutually exclusive")
+	}
+	// 解析符號定義
+	if len(ss.Symbols) > 0 {
+		if err := ss.initSymbolDefs(); err != nil {
+			return err
+		}
+	} else if ss.SymbolUsed == nil {
 		ss.SymbolUsed = make([]Symbol, len(ss.SymbolUsedStr))
 		for id, str := range ss.SymbolUsedStr {
 			su, ok := ParseSymbol(str)
 			if !ok {
-				return errs.NewFatal(fmt.Sprintf("symbol used has wrong elem %s", str))
+				return atPath(fmt.Sprintf("symbol_used[%d]", id), errs.NewFatal(fmt.Sprintf("symbol used has wrong elem %s", str)))
 			}
 			ss.SymbolUsed[id] = su
 		}
 	}
+	if len(ss.SymbolUsedStr) < len(ss.SymbolUsed) {
+		// 直接以 SymbolUsed 建立設定時補上名稱
+		ss.SymbolUsedStr = make([]string, len(ss.SymbolUsed))
+		for i, su := range ss.SymbolUsed {
+			ss.SymbolUsedStr[i] = su.String()
+		}
+	}
 
 	if len(ss.SymbolUsed) != len(ss.PayTable) {
-		return errs.NewFatal("len(simbol_used) != len(pay_table)")
+		return atPath("pay_table", errs.NewFatal("len(simbol_used) != len(pay_table)"))
 	}
 	// 檢查 PayTable
 	if len(ss.PayTable) == 0 {
 		return errs.NewFatal("pay_table is empty")
 	}
-	payLen := len(ss.PayTable[0])
+	// 各列寬度是否一致由 pay_table_width 規則檢查，此處以最長列展平（較短的列補 0）
+	payLen := 0
+	for _, payRow := range ss.PayTable {
+		payLen = max(payLen, len(payRow))
+	}
 	ss.PayTableFlat = make([]int, len(ss.SymbolUsed)*payLen)
 	ss.PayTableIndex = make([]int, len(ss.SymbolUsed))
 	write := 0
 	for rowIdx, payRow := range ss.PayTable {
-		if len(payRow) != payLen {
-			return errs.NewFatal("inconsistent pay table lengths")
-		}
 		ss.PayTableIndex[rowIdx] = write
 		for i, v := range payRow {
 			ss.PayTableFlat[write+i] = v
@@ -72,17 +117,127 @@ func (ss *SymbolSetting) Init() error {
 		write += payLen
 	}
 	// 賦值
-	for _, s := range ss.SymbolUsed {
+	if ss.SymbolRoles == nil {
+		ss.SymbolRoles = make([]SymbolRole, len(ss.SymbolUsed))
+		for i, s := range ss.SymbolUsed {
+			ss.SymbolRoles[i] = s.GetSymbolType().Role()
+		}
+	}
+	ss.SymbolTypes = make([]SymbolType, 0, len(ss.SymbolUsed))
+	for i, s := range ss.SymbolUsed {
+		if s == SymbolCustom {
+			ss.SymbolTypes = append(ss.SymbolTypes, ss.primaryType(i))
+			continue
+		}
 		ss.SymbolTypes = append(ss.SymbolTypes, s.GetSymbolType())
 	}
+	ss.SymbolIndex = make(map[string]int, len(ss.SymbolUsedStr))
+	for i, name := range ss.SymbolUsedStr {
+		if _, ok := ss.SymbolIndex[name]; !ok {
+			ss.SymbolIndex[name] = i
+		}
+	}
 	ss.SymbolCount = len(ss.SymbolUsed)
 	// set 初始化旗標
 	ss.initFlag = true
 	return nil
 }
 
+// initSymbolDefs 解析 symbols 區塊，填入 SymbolUsedStr / SymbolUsed / SymbolRoles
+func (ss *SymbolSetting) initSymbolDefs() error {
+	n := len(ss.Symbols)
+	ss.SymbolUsedStr = make([]string, n)
+	ss.SymbolUsed = make([]Symbol, n)
+	ss.SymbolRoles = make([]SymbolRole, n)
+	ss.SymbolMults = make([]int, n)
+	for i, def := range ss.Symbols {
+		if !validSymbolName(def.Name) {
+			return errs.NewFatal(fmt.Sprintf("symbols[%d]: invalid name %q (letters, digits and _ only, max %d chars)", i, def.Name, MaxSymbolNameLen))
+		}
+		if def.Multiplier < 0 {
+			return errs.NewFatal(fmt.Sprintf("symbols[%d]: multiplier must be >= 0, got %d", i, def.Multiplier))
+		}
+		ss.SymbolMults[i] = def.Multiplier
+		ss.SymbolUsedStr[i] = def.Name
+		su, short := ParseSymbol(def.Name)
+		if !short {
+			su = SymbolCustom
+		}
+		ss.SymbolUsed[i] = su
+		if len(def.Roles) == 0 {
+			if !short {
+				return errs.NewFatal(fmt.Sprintf("symbols[%d]: roles required for custom symbol %s", i, def.Name))
+			}
+			ss.SymbolRoles[i] = su.GetSymbolType().Role()
+			continue
+		}
+		for _, r := range def.Roles {
+			role, ok := ParseSymbolRole(r)
+			if !ok {
+				return errs.NewFatal(fmt.Sprintf("symbols[%d]: unknown role %q", i, r))
+			}
+			ss.SymbolRoles[i] |= role
+		}
+	}
+	return nil
+}
+
+// primaryType 自訂符號的主要類型由 symbols 定義中的第一個角色決定
+func (ss *SymbolSetting) primaryType(i int) SymbolType {
+	if i < len(ss.Symbols) && len(ss.Symbols[i].Roles) > 0 {
+		role, _ := ParseSymbolRole(ss.Symbols[i].Roles[0])
+		return role.Type()
+	}
+	return ss.SymbolRoles[i].Type()
+}
+
+// HasRole 回傳第 i 個符號是否具有指定角色
+func (ss *SymbolSetting) HasRole(i int, role SymbolRole) bool {
+	return i >= 0 && i < len(ss.SymbolRoles) && ss.SymbolRoles[i]&role != 0
+}
+
+// RoleMask 回傳具有指定角色的符號位元遮罩（bit i 對應符號索引 i），供算分熱路徑使用
+func (ss *SymbolSetting) RoleMask(role SymbolRole) uint64 {
+	var m uint64
+	for i, r := range ss.SymbolRoles {
+		if r&role != 0 && i < 64 {
+			m |= 1 << uint(i)
+		}
+	}
+	return m
+}
+
+// Lookup 依名稱取得符號索引
+func (ss *SymbolSetting) Lookup(name string) (int, bool) {
+	i, ok := ss.SymbolIndex[name]
+	return i, ok
+}
+
+// Def 回傳第 i 個符號的完整定義；以短名稱設定時由前綴推導角色
+func (ss *SymbolSetting) Def(i int) SymbolDef {
+	if i < len(ss.Symbols) {
+		return ss.Symbols[i]
+	}
+	return SymbolDef{Name: ss.SymbolUsedStr[i], Roles: ss.SymbolRoles[i].Names()}
+}
+
+func validSymbolName(name string) bool {
+	if name == "" || len(name) > MaxSymbolNameLen {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
+
 type Symbol int
 
+// SymbolCustom 自訂名稱的符號（不在短名稱表中），類型由 SymbolRoles 決定
+const SymbolCustom Symbol = -1
+
 const (
 	// Z系列圖標(Zero) : 代表沒有得分圖標 None
 	Z1 Symbol = iota // Z系列圖標 : Zero | None 圖標代表沒有得分圖標
@@ -213,6 +368,14 @@ func ParseSymbol(s string) (Symbol, bool) {
 	return sym, ok
 }
 
+// String 回傳短名稱；自訂符號回傳空字串
+func (s Symbol) String() string {
+	if s < Z1 || s > L9 {
+		return ""
+	}
+	return string("ZSCWHL"[int(s)/9]) + string(rune('1'+int(s)%9))
+}
+
 // IsSymbolNone 回傳符號是否屬於 None 類型。
 func IsSymbolNone(s Symbol) bool { return (s >= Z1) && (s <= Z9) }
 
@@ -264,3 +427,69 @@ func (s Symbol) GetSymbolType() SymbolType {
 	}
 	return SymbolTypeNone
 }
+
+// Role 回傳類型對應的角色旗標
+func (t SymbolType) Role() SymbolRole {
+	if t <= SymbolTypeNone || t > SymbolTypeLow {
+		return 0
+	}
+	return 1 << uint(t-1)
+}
+
+// SymbolRole 符號角色旗標，一個符號可同時具有多個角色
+type SymbolRole uint8
+
+const (
+	SymbolRoleSpecial SymbolRole = 1 << iota // 特殊符號
+	SymbolRoleScatter                        // 分散符號
+	SymbolRoleWild                           // 百搭符號
+	SymbolRoleHigh                           // 高分符號
+	SymbolRoleLow                            // 低分符號
+)
+
+var symbolRoleMap = map[string]SymbolRole{
+	"none":    0,
+	"special": SymbolRoleSpecial,
+	"scatter": SymbolRoleScatter,
+	"wild":    SymbolRoleWild,
+	"high":    SymbolRoleHigh,
+	"low":     SymbolRoleLow,
+}
+
+// symbolRoleOrder 角色輸出順序
+var symbolRoleOrder = []string{"special", "scatter", "wild", "high", "low"}
+
+// ParseSymbolRole 解析角色名稱
+func ParseSymbolRole(s string) (SymbolRole, bool) {
+	r, ok := symbolRoleMap[s]
+	return r, ok
+}
+
+// SymbolRoleNames 回傳所有合法的角色名稱
+func SymbolRoleNames() []string {
+	return append([]string{"none"}, symbolRoleOrder...)
+}
+
+// Names 回傳旗標中包含的角色名稱；無角色時回傳 [none]
+func (r SymbolRole) Names() []string {
+	if r == 0 {
+		return []string{"none"}
+	}
+	names := make([]string, 0, 2)
+	for _, n := range symbolRoleOrder {
+		if r&symbolRoleMap[n] != 0 {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// Type 回傳旗標中最低位角色對應的 SymbolType
+func (r SymbolRole) Type() SymbolType {
+	for t := SymbolType(SymbolTypeSpecial); t <= SymbolTypeLow; t++ {
+		if r&t.Role() != 0 {
+			return t
+		}
+	}
+	return SymbolTypeNone
+}
@@ -0,0 +1,351 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zintix-labs/problab/errs"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigIssue 設定檔中的單一問題，帶有檔案、行列與 YAML 路徑
+type ConfigIssue struct {
+	File    string        `json:"file,omitempty"`
+	Line    int           `json:"line"`
+	Column  int           `json:"column"`
+	Path    string        `json:"path"`
+	Level   errs.ErrLevel `json:"level"` // 語法、結構與初始化問題一律為 Fatal；規則違規依規則嚴重度
+	Message string        `json:"message"`
+}
+
+// String 以 file:line:col: path: message 格式輸出（與編譯器錯誤格式一致，方便編輯器跳轉）
+func (ci ConfigIssue) String() string {
+	var b strings.Builder
+	if ci.File != "" {
+		b.WriteString(ci.File)
+		b.WriteString(":")
+	}
+	fmt.Fprintf(&b, "%d:%d: ", ci.Line, ci.Column)
+	if ci.Path != "" {
+		b.WriteString(ci.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(ci.Message)
+	return b.String()
+}
+
+var yamlErrLine = regexp.MustCompile(`line (\d+):`)
+
+// ValidateYAML 檢查 YAML（或 JSON）設定內容，回傳所有找到的問題（不會在第一個問題就停止）。
+//
+// 流程：
+//  1. 語法解析失敗：回傳語法錯誤（含行號）。
+//  2. 展開 includes 與模式繼承（extends），失敗時回報所在行號。
+//  3. 依 GameSettingSchema 檢查型別、列舉值、必填欄位與未知欄位，收集全部問題。
+//  4. 結構無誤時，再解碼並執行初始化檢查（含 library 引用）；每個初始化錯誤都依 YAML 路徑對回行號。
+//  5. 對初始化成功的模式執行全部語意規則（CheckRules），違規依 YAML 路徑對回行號。
+func ValidateYAML(data []byte) []ConfigIssue {
+	return ValidateYAMLWithRules(data, nil)
+}
+
+// ValidateYAMLWithRules 與 ValidateYAML 相同，但可覆寫規則嚴重度（errs.None 停用）
+func ValidateYAMLWithRules(data []byte, levels RuleLevels) []ConfigIssue {
+	return ValidateConfig(data, "", nil, levels)
+}
+
+// ValidateConfig 與 ValidateYAMLWithRules 相同，並以 load 解析 includes（name 為本檔在來源中的路徑）。
+// 結構檢查作用在展開 includes / extends 之後的設定上。
+func ValidateConfig(data []byte, name string, load ConfigLoader, levels RuleLevels) []ConfigIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		issues := make([]ConfigIssue, 0, 1)
+		for _, msg := range strings.Split(strings.TrimPrefix(err.Error(), "yaml: "), "\n") {
+			ci := ConfigIssue{Level: errs.Fatal, Message: strings.TrimSpace(msg)}
+			if m := yamlErrLine.FindStringSubmatch(msg); m != nil {
+				ci.Line, _ = strconv.Atoi(m[1])
+			}
+			issues = append(issues, ci)
+		}
+		return issues
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return []ConfigIssue{{Line: 1, Column: 1, Level: errs.Fatal, Message: "empty document"}}
+	}
+	root := doc.Content[0]
+	if err := composeNode(root, name, load); err != nil {
+		var ce *composeErr
+		if errors.As(err, &ce) {
+			return []ConfigIssue{ce.ci}
+		}
+		return []ConfigIssue{{Line: root.Line, Column: root.Column, Level: errs.Fatal, Message: err.Error()}}
+	}
+
+	v := &schemaValidator{}
+	v.walk(root, GameSettingSchema(), "")
+	if len(v.issues) > 0 {
+		return v.issues
+	}
+
+	gs, err := decodeSetting(root)
+	if err != nil {
+		return []ConfigIssue{{Line: root.Line, Column: root.Column, Level: errs.Fatal, Message: err.Error()}}
+	}
+	issues := make([]ConfigIssue, 0)
+	for _, e := range initErrors(gs.init()) {
+		path := ""
+		var pe *pathErr
+		if errors.As(e, &pe) {
+			path, e = pe.path, pe.err
+		}
+		n := nodeAt(root, path)
+		issues = append(issues, ConfigIssue{Line: n.Line, Column: n.Column, Path: path, Level: errs.Fatal, Message: issueMessage(e)})
+	}
+
+	// 規則只檢查初始化成功的模式，違規路徑再對回原本的模式索引
+	view, modeIdx := *gs, make([]int, 0, len(gs.GameModeSettings))
+	view.GameModeSettings = make([]GameModeSetting, 0, len(gs.GameModeSettings))
+	for i, m := range gs.GameModeSettings {
+		if !m.initFailed {
+			view.GameModeSettings = append(view.GameModeSettings, m)
+			modeIdx = append(modeIdx, i)
+		}
+	}
+	for _, vi := range CheckRules(&view, levels) {
+		vi.Path = remapModePath(vi.Path, modeIdx)
+		n := nodeAt(root, vi.Path)
+		issues = append(issues, ConfigIssue{
+			Line:    n.Line,
+			Column:  n.Column,
+			Path:    vi.Path,
+			Level:   vi.Level,
+			Message: fmt.Sprintf("[%s %s] %s", errs.ErrLv(vi.Level), vi.Rule, vi.Message),
+		})
+	}
+	return issues
+}
+
+// initErrors 攤平 GameSetting.init 以 errors.Join 收集的錯誤
+func initErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		out := make([]error, 0)
+		for _, e := range j.Unwrap() {
+			out = append(out, initErrors(e)...)
+		}
+		return out
+	}
+	return []error{err}
+}
+
+// issueMessage 將錯誤鏈組成單行訊息（不含 errlv 前綴）
+func issueMessage(err error) string {
+	parts := make([]string, 0, 2)
+	for err != nil {
+		e, ok := err.(*errs.E)
+		if !ok {
+			parts = append(parts, err.Error())
+			break
+		}
+		msg := e.Message
+		if e.Extra != "" {
+			msg += " | extra: " + e.Extra
+		}
+		parts = append(parts, msg)
+		err = e.Cause
+	}
+	return strings.Join(parts, ": ")
+}
+
+// remapModePath 將 game_mode_settings[k] 換回原本的模式索引 idx[k]
+func remapModePath(path string, idx []int) string {
+	const prefix = "game_mode_settings["
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok {
+		return path
+	}
+	k, after, ok := strings.Cut(rest, "]")
+	i, err := strconv.Atoi(k)
+	if !ok || err != nil || i < 0 || i >= len(idx) {
+		return path
+	}
+	return fmt.Sprintf("%s%d]%s", prefix, idx[i], after)
+}
+
+// nodeAt 依 a.b[0].c 形式的路徑取得最接近的節點（找不到時回傳最後一個存在的祖先）
+func nodeAt(root *yaml.Node, path string) *yaml.Node {
+	n := root
+	for _, seg := range strings.Split(path, ".") {
+		if seg == "" {
+			continue
+		}
+		key, rest, _ := strings.Cut(seg, "[")
+		if key != "" {
+			v := mappingValue(n, key)
+			if v == nil {
+				return n
+			}
+			n = v
+		}
+		for rest != "" {
+			idxStr, after, _ := strings.Cut(rest, "]")
+			idx, err := strconv.Atoi(idxStr)
+			if err != nil || n.Kind != yaml.SequenceNode || idx < 0 || idx >= len(n.Content) {
+				return n
+			}
+			n = n.Content[idx]
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return n
+}
+
+type schemaValidator struct {
+	issues []ConfigIssue
+}
+
+func (v *schemaValidator) add(n *yaml.Node, path string, format string, a ...any) {
+	v.issues = append(v.issues, ConfigIssue{
+		Line:    n.Line,
+		Column:  n.Column,
+		Path:    path,
+		Level:   errs.Fatal,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (v *schemaValidator) walk(n *yaml.Node, s *Schema, path string) {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	// null 視同未填寫，必填與否由上層 object 判斷
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+	switch s.Type {
+	case "object":
+		if n.Kind != yaml.MappingNode {
+			v.add(n, path, "expected object, got %s", nodeKind(n))
+			return
+		}
+		seen := make(map[string]bool, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, val := n.Content[i], n.Content[i+1]
+			seen[k.Value] = true
+			if s.Properties == nil {
+				continue
+			}
+			ps, ok := s.Properties[k.Value]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					v.add(k, joinPath(path, k.Value), "unknown field %q", k.Value)
+				}
+				continue
+			}
+			v.walk(val, ps, joinPath(path, k.Value))
+		}
+		for _, r := range s.Required {
+			if !seen[r] || isNull(mappingValue(n, r)) {
+				v.add(n, path, "missing required field %q", r)
+			}
+		}
+	case "array":
+		if n.Kind != yaml.SequenceNode {
+			v.add(n, path, "expected array, got %s", nodeKind(n))
+			return
+		}
+		for i, c := range n.Content {
+			v.walk(c, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!str" {
+			v.add(n, path, "expected string, got %s", nodeKind(n))
+			return
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, n.Value) {
+			v.add(n, path, "invalid value %q, must be one of [%s]", n.Value, strings.Join(s.Enum, ", "))
+		}
+	case "integer":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			v.add(n, path, "expected integer, got %s", nodeKind(n))
+			return
+		}
+		if s.Minimum != nil {
+			if x, err := strconv.ParseInt(n.Value, 0, 64); err == nil && float64(x) < *s.Minimum {
+				v.add(n, path, "must be >= %v, got %s", *s.Minimum, n.Value)
+			}
+		}
+	case "number":
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!int" && n.Tag != "!!float") {
+			v.add(n, path, "expected number, got %s", nodeKind(n))
+		}
+	case "boolean":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!bool" {
+			v.add(n, path, "expected boolean, got %s", nodeKind(n))
+		}
+	}
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func isNull(n *yaml.Node) bool {
+	return n == nil || (n.Kind == yaml.ScalarNode && n.Tag == "!!null")
+}
+
+func nodeKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	case yaml.ScalarNode:
+		switch n.Tag {
+		case "!!str":
+			return fmt.Sprintf("string %q", n.Value)
+		case "!!int":
+			return "integer " + n.Value
+		case "!!float":
+			return "number " + n.Value
+		case "!!bool":
+			return "boolean " + n.Value
+		}
+		return n.Tag + " " + n.Value
+	}
+	return "unknown"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,436 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zintix-labs/problab/errs"
+	"gopkg.in/yaml.v3"
+)
+
+// PatchYAML 以 value 取代 YAML 中 path（a.b[0].c 形式）的內容，最後一段若是尚不存在的 mapping 欄位則新增。
+//
+// 只改寫實際不同的位元組：結構相同時逐一替換不同的純量，結構不同時才重寫該節點（只含純量的陣列以單行輸出）；
+// 其餘內容（排版、註解、純量寫法）原樣保留，內容沒有變化時回傳原本的 data。
+func PatchYAML(data []byte, path string, value any) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errs.Wrap(err, "failed to unmarshall yaml")
+	}
+	if len(doc.Content) == 0 {
+		return nil, errs.NewFatal("empty document")
+	}
+	var v yaml.Node
+	if err := v.Encode(value); err != nil {
+		return nil, errs.Wrap(err, "encode patch value failed")
+	}
+	flowScalarSeqs(&v)
+
+	root := doc.Content[0]
+	p := newYAMLPatcher(data)
+	parent, last := "", path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parent, last = path[:i], path[i+1:]
+	}
+	if strings.Contains(last, "[") {
+		target, ok := lookupNode(root, path)
+		if !ok {
+			return nil, errs.NewFatal(fmt.Sprintf("patch path not found: %s", path))
+		}
+		p.diff(target, &v, nil, inFlow(root, target))
+	} else {
+		pn, ok := lookupNode(root, parent)
+		if !ok || pn.Kind != yaml.MappingNode {
+			return nil, errs.NewFatal(fmt.Sprintf("patch path not found: %s", parent))
+		}
+		if k := mappingKey(pn, last); k != nil {
+			p.diff(mappingValue(pn, last), &v, k, inFlow(root, pn))
+		} else {
+			p.insert(pn, last, &v)
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if len(p.edits) == 0 {
+		return data, nil
+	}
+	out := p.apply()
+
+	// 重新解析確認結果：路徑上的內容須與 value 相同
+	var check yaml.Node
+	if err := yaml.Unmarshal(out, &check); err != nil || len(check.Content) == 0 {
+		return nil, errs.Fatalf("patch %s produced invalid yaml: %v", path, err)
+	}
+	got, ok := lookupNode(check.Content[0], path)
+	var want, have any
+	if !ok || v.Decode(&want) != nil || got.Decode(&have) != nil || !reflect.DeepEqual(want, have) {
+		return nil, errs.Fatalf("patch %s produced unexpected content", path)
+	}
+	return out, nil
+}
+
+// LibraryFile 回傳定義 library.<section>.<key> 的檔案：name 本身，或經 includes（可巢狀）引入的檔案。
+// 回傳的路徑與 includes 展開規則相同，為相對於設定來源根目錄的路徑。
+func LibraryFile(data []byte, name string, load ConfigLoader, section, key string) (string, error) {
+	return libraryFile(data, name, load, section, key, map[string]bool{name: true})
+}
+
+func libraryFile(data []byte, name string, load ConfigLoader, section, key string, seen map[string]bool) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", errs.Wrap(err, fmt.Sprintf("parse %s failed", name))
+	}
+	if len(doc.Content) == 0 {
+		return "", errs.Fatalf("library.%s.%s not found", section, key)
+	}
+	root := doc.Content[0]
+	if mappingValue(mappingValue(mappingValue(root, "library"), section), key) != nil {
+		return name, nil
+	}
+	if inc := mappingValue(root, "includes"); inc != nil && inc.Kind == yaml.SequenceNode && load != nil {
+		for _, item := range inc.Content {
+			file := path.Join(path.Dir(name), item.Value)
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			raw, err := load(file)
+			if err != nil {
+				return "", errs.Wrap(err, fmt.Sprintf("read include %s failed", file))
+			}
+			if f, err := libraryFile(raw, file, load, section, key, seen); err == nil {
+				return f, nil
+			}
+		}
+	}
+	return "", errs.Fatalf("library.%s.%s not found", section, key)
+}
+
+// lookupNode 與 nodeAt 相同，但找不到時回報失敗而不是回傳祖先
+func lookupNode(root *yaml.Node, path string) (*yaml.Node, bool) {
+	n := root
+	for _, seg := range strings.Split(path, ".") {
+		if seg == "" {
+			continue
+		}
+		key, rest, _ := strings.Cut(seg, "[")
+		if key != "" {
+			if n.Kind != yaml.MappingNode {
+				return nil, false
+			}
+			v := mappingValue(n, key)
+			if v == nil {
+				return nil, false
+			}
+			n = v
+		}
+		for rest != "" {
+			idxStr, after, _ := strings.Cut(rest, "]")
+			idx, err := strconv.Atoi(idxStr)
+			if err != nil || n.Kind != yaml.SequenceNode || idx < 0 || idx >= len(n.Content) {
+				return nil, false
+			}
+			n = n.Content[idx]
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return n, true
+}
+
+func mappingKey(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i]
+		}
+	}
+	return nil
+}
+
+// inFlow 回傳 target 是否位於 flow（[...] / {...}）集合之內
+func inFlow(root, target *yaml.Node) bool {
+	var walk func(n *yaml.Node, flow bool) (bool, bool)
+	walk = func(n *yaml.Node, flow bool) (bool, bool) {
+		if n == target {
+			return flow, true
+		}
+		inner := flow || n.Style&yaml.FlowStyle != 0
+		for _, c := range n.Content {
+			if f, ok := walk(c, inner); ok {
+				return f, true
+			}
+		}
+		return false, false
+	}
+	f, _ := walk(root, false)
+	return f
+}
+
+// yamlPatcher 依節點的行列位置計算原始位元組範圍，收集替換後一次套用
+type yamlPatcher struct {
+	src   []byte
+	lines []int // 每行起始位移
+	edits []yamlEdit
+	err   error
+}
+
+type yamlEdit struct {
+	start, end int
+	text       string
+}
+
+func newYAMLPatcher(src []byte) *yamlPatcher {
+	lines := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return &yamlPatcher{src: src, lines: lines}
+}
+
+func (p *yamlPatcher) fail(n *yaml.Node, format string, a ...any) {
+	if p.err == nil {
+		p.err = errs.Fatalf("patch line %d: %s", n.Line, fmt.Sprintf(format, a...))
+	}
+}
+
+// offset 將 yaml 的行列（皆 1 起算，欄以字元計）轉為位元組位移
+func (p *yamlPatcher) offset(line, col int) int {
+	off := p.lines[line-1]
+	for k := 1; k < col && off < len(p.src) && p.src[off] != '\n'; k++ {
+		_, size := utf8.DecodeRune(p.src[off:])
+		off += size
+	}
+	return off
+}
+
+// start 回傳節點內容的起始位移（略過 anchor 與 tag）
+func (p *yamlPatcher) start(n *yaml.Node) int {
+	i := p.offset(n.Line, n.Column)
+	for i < len(p.src) && (p.src[i] == '&' || p.src[i] == '!') {
+		for i < len(p.src) && !isYAMLSpace(p.src[i]) {
+			i++
+		}
+		for i < len(p.src) && isYAMLSpace(p.src[i]) {
+			i++
+		}
+	}
+	return i
+}
+
+func isYAMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// end 回傳節點內容的結束位移（不含）；flow 表示節點位於 flow 集合之內
+func (p *yamlPatcher) end(n *yaml.Node, flow bool) int {
+	src := p.src
+	s := p.start(n)
+	switch {
+	case n.Kind == yaml.AliasNode:
+		return p.offset(n.Line, n.Column) + 1 + len(n.Value)
+	case n.Kind == yaml.ScalarNode:
+		switch {
+		case n.Style&yaml.DoubleQuotedStyle != 0:
+			for i := s + 1; i < len(src); i++ {
+				if src[i] == '\\' {
+					i++
+				} else if src[i] == '"' {
+					return i + 1
+				}
+			}
+		case n.Style&yaml.SingleQuotedStyle != 0:
+			for i := s + 1; i < len(src); i++ {
+				if src[i] == '\'' {
+					if i+1 < len(src) && src[i+1] == '\'' {
+						i++
+						continue
+					}
+					return i + 1
+				}
+			}
+		case n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		default:
+			i := s
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				if src[i] == '#' && i > s && (src[i-1] == ' ' || src[i-1] == '\t') {
+					break
+				}
+				if flow && (src[i] == ',' || src[i] == ']' || src[i] == '}') {
+					break
+				}
+				i++
+			}
+			for i > s && (src[i-1] == ' ' || src[i-1] == '\t') {
+				i--
+			}
+			if string(src[s:i]) == n.Value {
+				return i
+			}
+		}
+		p.fail(n, "scalar %q cannot be patched in place", n.Value)
+		return s
+	case n.Style&yaml.FlowStyle != 0:
+		i := s + 1
+		if len(n.Content) > 0 {
+			i = p.end(n.Content[len(n.Content)-1], true)
+		}
+		for i < len(src) {
+			switch src[i] {
+			case ']', '}':
+				return i + 1
+			case '#':
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			default:
+				i++
+			}
+		}
+		p.fail(n, "unterminated flow collection")
+		return s
+	}
+	if len(n.Content) == 0 {
+		return s
+	}
+	return p.end(n.Content[len(n.Content)-1], false)
+}
+
+// diff 比對 old 與 now，只對不同的部分產生替換；key 為 old 所屬的 mapping 鍵（若有）
+func (p *yamlPatcher) diff(old, now, key *yaml.Node, flow bool) {
+	if old.Kind != now.Kind || old.Kind == yaml.AliasNode {
+		p.replace(old, now, key, flow)
+		return
+	}
+	inner := flow || old.Style&yaml.FlowStyle != 0
+	switch old.Kind {
+	case yaml.ScalarNode:
+		if old.Tag != now.Tag || old.Value != now.Value {
+			p.replace(old, now, key, flow)
+		}
+	case yaml.SequenceNode:
+		if len(old.Content) != len(now.Content) {
+			p.replace(old, now, key, flow)
+			return
+		}
+		for i := range old.Content {
+			p.diff(old.Content[i], now.Content[i], nil, inner)
+		}
+	case yaml.MappingNode:
+		if len(old.Content) != len(now.Content) {
+			p.replace(old, now, key, flow)
+			return
+		}
+		for i := 0; i+1 < len(now.Content); i += 2 {
+			if mappingKey(old, now.Content[i].Value) == nil {
+				p.replace(old, now, key, flow)
+				return
+			}
+		}
+		for i := 0; i+1 < len(now.Content); i += 2 {
+			k := mappingKey(old, now.Content[i].Value)
+			p.diff(mappingValue(old, k.Value), now.Content[i+1], k, inner)
+		}
+	default:
+		p.replace(old, now, key, flow)
+	}
+}
+
+// replace 以 now 重寫 old 的位元組範圍，沿用 old 的縮排
+func (p *yamlPatcher) replace(old, now, key *yaml.Node, flow bool) {
+	s, e := p.start(old), p.end(old, flow)
+	if flow {
+		p.edits = append(p.edits, yamlEdit{s, e, renderYAML(flowNode(now), 0)})
+		return
+	}
+	block := old.Kind != yaml.ScalarNode && old.Kind != yaml.AliasNode && old.Style&yaml.FlowStyle == 0
+	text := renderYAML(now, old.Column-1)
+	if !block && key != nil && strings.Contains(text, "\n") {
+		// 原本寫在鍵後同一行，多行內容改為換行縮排接續
+		ind := key.Column - 1 + 2
+		for s > 0 && (p.src[s-1] == ' ' || p.src[s-1] == '\t') {
+			s--
+		}
+		text = "\n" + strings.Repeat(" ", ind) + renderYAML(now, ind)
+	}
+	p.edits = append(p.edits, yamlEdit{s, e, text})
+}
+
+// insert 在 mapping 末尾新增 key: v
+func (p *yamlPatcher) insert(parent *yaml.Node, key string, v *yaml.Node) {
+	kv := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v}}
+	if parent.Style&yaml.FlowStyle != 0 {
+		e := p.end(parent, false) - 1 // 收尾的 }
+		text := renderYAML(flowNode(kv), 0)
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "{"), "}")
+		if len(parent.Content) > 0 {
+			text = ", " + text
+		}
+		p.edits = append(p.edits, yamlEdit{e, e, text})
+		return
+	}
+	ind := parent.Column - 1
+	e := p.end(parent, false)
+	p.edits = append(p.edits, yamlEdit{e, e, "\n" + strings.Repeat(" ", ind) + renderYAML(kv, ind)})
+}
+
+func (p *yamlPatcher) apply() []byte {
+	slices.SortFunc(p.edits, func(a, b yamlEdit) int { return a.start - b.start })
+	var b bytes.Buffer
+	last := 0
+	for _, e := range p.edits {
+		b.Write(p.src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(p.src[last:])
+	return b.Bytes()
+}
+
+// renderYAML 輸出單一節點（縮排 2），第二行起補上 indent 個空白
+func renderYAML(n *yaml.Node, indent int) string {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	_ = enc.Encode(n)
+	_ = enc.Close()
+	text := strings.TrimSuffix(b.String(), "\n")
+	return strings.ReplaceAll(text, "\n", "\n"+strings.Repeat(" ", indent))
+}
+
+// flowNode 回傳整棵改為 flow 寫法的複本（用於 flow 集合之內）
+func flowNode(n *yaml.Node) *yaml.Node {
+	c := cloneNode(n)
+	var walk func(x *yaml.Node)
+	walk = func(x *yaml.Node) {
+		if x.Kind == yaml.SequenceNode || x.Kind == yaml.MappingNode {
+			x.Style |= yaml.FlowStyle
+		}
+		for _, ch := range x.Content {
+			walk(ch)
+		}
+	}
+	walk(c)
+	return c
+}
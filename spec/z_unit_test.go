@@ -0,0 +1,669 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zintix-labs/problab/demo/demo_configs"
+	"github.com/zintix-labs/problab/errs"
+)
+
+const validYAML = `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0, 1]
+            - symbols: [0, 1]
+            - symbols: [0, 1]
+    symbol_setting:
+      symbol_used: [H1, W1]
+      pay_table: [[0, 0, 5], [0, 0, 0]]
+    hit_setting:
+      bet_type: way_ltr
+`
+
+func TestValidateYAMLValid(t *testing.T) {
+	if issues := ValidateYAML([]byte(validYAML)); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateYAMLReportsAll(t *testing.T) {
+	bad := strings.NewReplacer(
+		"bet_type: way_ltr", "bet_type: ways",
+		"columns: 3", "columns: x",
+		"symbol_used: [H1, W1]", "symbol_used: [H1, Q1]",
+		"logic_key: k", "logic_kye: k",
+	).Replace(validYAML)
+
+	issues := ValidateYAML([]byte(bad))
+	want := map[string]int{
+		"game_mode_settings[0].hit_setting.bet_type":          20,
+		"game_mode_settings[0].screen_setting.columns":        7,
+		"game_mode_settings[0].symbol_setting.symbol_used[1]": 17,
+		"logic_kye": 3,
+	}
+	got := map[string]int{}
+	for _, ci := range issues {
+		got[ci.Path] = ci.Line
+	}
+	for path, line := range want {
+		if got[path] != line {
+			t.Fatalf("expected issue at %s line %d, got %v", path, line, issues)
+		}
+	}
+	if len(issues) != 5 { // 4 個欄位問題 + 缺少 logic_key
+		t.Fatalf("expected 5 issues, got %d: %v", len(issues), issues)
+	}
+}
+
+func TestGameSettingSchemaEnums(t *testing.T) {
+	s := GameSettingSchema()
+	hit := s.Properties["game_mode_settings"].Items.Properties["hit_setting"]
+	if len(hit.Properties["bet_type"].Enum) != len(betTypeMap) {
+		t.Fatalf("bet_type enum mismatch: %v", hit.Properties["bet_type"].Enum)
+	}
+	sym := s.Properties["game_mode_settings"].Items.Properties["symbol_setting"].Properties["symbol_used"]
+	if sym.Items == nil || len(sym.Items.Enum) != len(symbolMap) || sym.Items.Enum[0] != "Z1" {
+		t.Fatalf("symbol_used enum mismatch: %+v", sym.Items)
+	}
+}
+
+func TestReelSymbolEqualsCountRejected(t *testing.T) {
+	bad := strings.Replace(validYAML, "- symbols: [0, 1]\n            - symbols: [0, 1]\n            - symbols: [0, 1]", "- symbols: [0, 1]\n            - symbols: [0, 2]\n            - symbols: [0, 1]", 1)
+	if _, err := GetGameSettingByYAML([]byte(bad)); err == nil {
+		t.Fatalf("expected reel symbol == symbol count to be rejected")
+	}
+}
+
+func TestCheckRulesLevels(t *testing.T) {
+	bad := strings.NewReplacer(
+		"bet_type: way_ltr", "bet_type: cluster",
+		"[0, 0, 0]]", "[0, 0, 9]]",
+	).Replace(validYAML)
+	gs, err := GetGameSettingByYAML([]byte(bad))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	vs := CheckRules(gs, nil)
+	if len(vs) != 1 || vs[0].Rule != "cluster_wild_pay" || vs[0].Path != "game_mode_settings[0].symbol_setting.pay_table[1]" {
+		t.Fatalf("unexpected violations: %v", vs)
+	}
+	if HasFatal(vs) {
+		t.Fatalf("cluster_wild_pay must default to warn")
+	}
+	vs = CheckRules(gs, RuleLevels{"cluster_wild_pay": errs.Fatal})
+	if !HasFatal(vs) {
+		t.Fatalf("expected level override to fatal, got %v", vs)
+	}
+	if vs = CheckRules(gs, RuleLevels{"cluster_wild_pay": errs.None}); len(vs) != 0 {
+		t.Fatalf("expected rule disabled, got %v", vs)
+	}
+	if err := (RuleLevels{"no_such_rule": errs.Warn}).Valid(); err == nil {
+		t.Fatalf("expected unknown rule error")
+	}
+	for _, name := range []string{"symbol_count_max", "pay_table_width", "line_table_width", "line_table_bounds", "reel_symbols", "reel_weights"} {
+		if err := (RuleLevels{name: errs.None}).Valid(); err == nil {
+			t.Fatalf("expected invariant %s to reject downgrade", name)
+		}
+	}
+	if err := (RuleLevels{"line_table_masked": errs.Warn}).Valid(); err != nil {
+		t.Fatalf("line_table_masked should be downgradable: %v", err)
+	}
+	short := strings.NewReplacer("bet_type: way_ltr", "bet_type: line_ltr\n      line_table: [[0, 0]]").Replace(validYAML)
+	if issues := ValidateYAMLWithRules([]byte(short), RuleLevels{"line_table_width": errs.None}); len(issues) != 1 || issues[0].Level != errs.Fatal {
+		t.Fatalf("expected invariant to ignore the override, got %v", issues)
+	}
+}
+
+func TestSymbolDefs(t *testing.T) {
+	custom := strings.Replace(validYAML, "symbol_used: [H1, W1]", `symbols:
+        - {name: DRAGON, roles: [high], display: {label: Dragon, color: "#c00"}}
+        - {name: COIN, roles: [scatter, wild]}`, 1)
+	if issues := ValidateYAML([]byte(custom)); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+	gs, err := GetGameSettingByYAML([]byte(custom))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	ss := &gs.GameModeSettings[0].SymbolSetting
+	if ss.SymbolCount != 2 || ss.SymbolUsed[1] != SymbolCustom {
+		t.Fatalf("unexpected symbols: %+v", ss.SymbolUsed)
+	}
+	if ss.RoleMask(SymbolRoleWild) != 0b10 || ss.RoleMask(SymbolRoleScatter) != 0b10 {
+		t.Fatalf("unexpected role masks: %v", ss.SymbolRoles)
+	}
+	if ss.SymbolTypes[1] != SymbolTypeScatter {
+		t.Fatalf("primary type must follow first role, got %v", ss.SymbolTypes[1])
+	}
+	if i, ok := ss.Lookup("COIN"); !ok || i != 1 || ss.Def(0).Display.Label != "Dragon" {
+		t.Fatalf("lookup/def mismatch")
+	}
+
+	noRole := strings.Replace(custom, "roles: [high], ", "", 1)
+	if _, err := GetGameSettingByYAML([]byte(noRole)); err == nil {
+		t.Fatalf("expected custom symbol without roles to fail")
+	}
+	short := strings.Replace(custom, "name: DRAGON, roles: [high], ", "name: H1, ", 1)
+	gs, err = GetGameSettingByYAML([]byte(short))
+	if err != nil {
+		t.Fatalf("short name without roles should infer roles: %v", err)
+	}
+	if !gs.GameModeSettings[0].SymbolSetting.HasRole(0, SymbolRoleHigh) {
+		t.Fatalf("expected H1 to infer high role")
+	}
+}
+
+const sharedYAML = `library:
+  reel_sets:
+    base: {weight: 2, reels: [{symbols: [0, 1]}, {symbols: [0, 1]}, {symbols: [0, 1]}]}
+  pay_tables:
+    low: [[0, 0, 5], [0, 0, 0]]
+    high: [[0, 0, 50], [0, 0, 0]]
+`
+
+const composedYAML = `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 1000
+includes: [shared.yaml]
+game_mode_settings:
+  - name: base
+    screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group: [{ref: base}]
+    symbol_setting:
+      symbol_used: [H1, W1]
+      pay_table_ref: low
+    hit_setting: {bet_type: way_ltr}
+  - extends: base
+    symbol_setting: {pay_table_ref: high}
+`
+
+func TestComposeIncludesAndExtends(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shared.yaml": {Data: []byte(sharedYAML)},
+		"game.yaml":   {Data: []byte(composedYAML)},
+	}
+	if !IsLibraryConfig([]byte(sharedYAML)) || IsLibraryConfig([]byte(composedYAML)) {
+		t.Fatalf("library detection mismatch")
+	}
+	gs, err := GetGameSettingFromFS(fsys, "game.yaml")
+	if err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	if len(gs.GameModeSettings) != 2 {
+		t.Fatalf("expected 2 modes, got %d", len(gs.GameModeSettings))
+	}
+	free := gs.GameModeSettings[1]
+	if free.SymbolSetting.PayTable[0][2] != 50 || gs.GameModeSettings[0].SymbolSetting.PayTable[0][2] != 5 {
+		t.Fatalf("extends override mismatch: %v", free.SymbolSetting.PayTable)
+	}
+	rs, ok := free.GenScreenSetting.ReelSetByName("base")
+	if !ok || rs.Weight != 2 || len(rs.Reels) != 3 || free.ScreenSetting.Columns != 3 {
+		t.Fatalf("inherited reel set mismatch: %+v", free.GenScreenSetting.ReelSetGroup)
+	}
+	if &rs.Reels[0].ReelSymbols[0] == &gs.GameModeSettings[0].GenScreenSetting.ReelSetGroup[0].Reels[0].ReelSymbols[0] {
+		t.Fatalf("modes must not share reel storage")
+	}
+
+	// 展開後的設定可獨立重新載入，且再次展開結果一致
+	exp, err := gs.Expanded()
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+	again, err := GetGameSettingByYAML(exp)
+	if err != nil {
+		t.Fatalf("reload expanded failed: %v\n%s", err, exp)
+	}
+	exp2, _ := again.Expanded()
+	if string(exp) != string(exp2) {
+		t.Fatalf("expanded config not stable:\n%s\n---\n%s", exp, exp2)
+	}
+	fp1, _ := gs.Fingerprint()
+	fp2, _ := again.Fingerprint()
+	if fp1 != fp2 || !strings.HasPrefix(fp1, FingerprintPrefix) {
+		t.Fatalf("fingerprint must not depend on composition: %s vs %s", fp1, fp2)
+	}
+
+	// ref 與內嵌同名的 reel set / pay table 指紋相同；不具名的內嵌 reel set 則不同
+	inline := strings.Replace(composedYAML, "includes: [shared.yaml]\n", "", 1)
+	inline = strings.Replace(inline, "pay_table_ref: low", "pay_table: [[0, 0, 5], [0, 0, 0]]", 1)
+	inline = strings.Replace(inline, "{pay_table_ref: high}", "{pay_table: [[0, 0, 50], [0, 0, 0]]}", 1)
+	named := strings.Replace(inline, "[{ref: base}]", "[{name: base, weight: 2, reels: [{symbols: [0, 1]}, {symbols: [0, 1]}, {symbols: [0, 1]}]}]", 1)
+	ings, err := GetGameSettingByYAML([]byte(named))
+	if err != nil {
+		t.Fatalf("parse inline config failed: %v", err)
+	}
+	if fp3, _ := ings.Fingerprint(); fp3 != fp1 {
+		t.Fatalf("ref and inline configs must share a fingerprint: %s vs %s", fp1, fp3)
+	}
+	unnamed := strings.Replace(named, "{name: base, ", "{", 1)
+	ungs, err := GetGameSettingByYAML([]byte(unnamed))
+	if err != nil {
+		t.Fatalf("parse unnamed config failed: %v", err)
+	}
+	if fp4, _ := ungs.Fingerprint(); fp4 == fp1 {
+		t.Fatalf("reel set name is part of the fingerprint")
+	}
+
+	// ref 未填 weight 時沿用 library，明確寫出的 weight: 0 保留
+	zero := strings.Replace(composedYAML, "[{ref: base}]", "[{ref: base}, {ref: base, name: off, weight: 0}]", 1)
+	zfs := fstest.MapFS{"shared.yaml": {Data: []byte(sharedYAML)}, "game.yaml": {Data: []byte(zero)}}
+	zgs, err := GetGameSettingFromFS(zfs, "game.yaml")
+	if err != nil {
+		t.Fatalf("compose with zero weight failed: %v", err)
+	}
+	if rsg := zgs.GameModeSettings[0].GenScreenSetting.ReelSetGroup; rsg[0].Weight != 2 || rsg[1].Weight != 0 || rsg[1].Name != "off" {
+		t.Fatalf("explicit zero weight must be kept: %+v", rsg)
+	}
+
+	if _, err := GetGameSettingByYAML([]byte(composedYAML)); err == nil {
+		t.Fatalf("expected includes without a config source to fail")
+	}
+	cyc := fstest.MapFS{
+		"a.yaml":    {Data: []byte("includes: [b.yaml]\n")},
+		"b.yaml":    {Data: []byte("includes: [a.yaml]\n")},
+		"game.yaml": {Data: []byte(strings.Replace(composedYAML, "[shared.yaml]", "[a.yaml]", 1))},
+	}
+	if _, err := GetGameSettingFromFS(cyc, "game.yaml"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected include cycle error, got %v", err)
+	}
+	issues := ValidateConfig([]byte(strings.Replace(composedYAML, "extends: base", "extends: nope", 1)), "game.yaml", FSLoader(fsys), nil)
+	if len(issues) != 1 || issues[0].Line != 17 {
+		t.Fatalf("expected unknown extends at line 17, got %v", issues)
+	}
+}
+
+func TestReelsAndPayTableCSV(t *testing.T) {
+	gs, err := GetGameSettingByYAML([]byte(validYAML))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	ss := &gs.GameModeSettings[0].SymbolSetting
+	csvIn := "Stop,Reel 1,Reel 2,Reel 3,Weight 3\n0,H1,W1,H1,3\n1,W1,,W1,1\n2,H1,,,\n"
+	reels, err := ReadReelsCSV(strings.NewReader(csvIn), ss)
+	if err != nil {
+		t.Fatalf("read reels failed: %v", err)
+	}
+	if len(reels) != 3 || len(reels[0].ReelSymbols) != 3 || len(reels[1].ReelSymbols) != 1 || reels[2].ReelWeights[0] != 3 || reels[1].ReelWeights != nil {
+		t.Fatalf("unexpected reels: %+v", reels)
+	}
+	var out bytes.Buffer
+	if err := WriteReelsCSV(&out, reels, ss); err != nil {
+		t.Fatalf("write reels failed: %v", err)
+	}
+	if out.String() != csvIn {
+		t.Fatalf("reels csv round trip mismatch:\n%s", out.String())
+	}
+	if _, err := ReadReelsCSV(strings.NewReader("Reel 1\nH1\nQ9\n"), ss); err == nil || !strings.Contains(err.Error(), "csv:3") {
+		t.Fatalf("expected unknown symbol error at line 3, got %v", err)
+	}
+
+	pt, err := ReadPayTableCSV(strings.NewReader("Symbol,1,2,3\nH1,0,0,7\n"), ss)
+	if err != nil || len(pt) != 2 || pt[0][2] != 7 || pt[1][2] != 0 {
+		t.Fatalf("unexpected pay table %v: %v", pt, err)
+	}
+	patched, err := PatchYAML([]byte(validYAML), "game_mode_settings[0].symbol_setting.pay_table", pt)
+	if err != nil {
+		t.Fatalf("patch failed: %v", err)
+	}
+	gs2, err := GetGameSettingByYAML(patched)
+	if err != nil || gs2.GameModeSettings[0].SymbolSetting.PayTable[0][2] != 7 {
+		t.Fatalf("patched config mismatch: %v\n%s", err, patched)
+	}
+	if _, err := PatchYAML([]byte(validYAML), "game_mode_settings[3].symbol_setting.pay_table", pt); err == nil {
+		t.Fatalf("expected missing path error")
+	}
+}
+
+func TestPatchYAMLInPlace(t *testing.T) {
+	raw, err := demo_configs.FS.ReadFile("game_0_demonormal.yaml")
+	if err != nil {
+		t.Fatalf("read demo config: %v", err)
+	}
+	gs, err := GetGameSettingByYAML(raw)
+	if err != nil {
+		t.Fatalf("parse demo config: %v", err)
+	}
+	// 與 cmd/sheet 相同：匯出 CSV 再匯入，內容未變時檔案逐位元組相同
+	out := raw
+	for i := range gs.GameModeSettings {
+		m := &gs.GameModeSettings[i]
+		for j, rs := range m.GenScreenSetting.ReelSetGroup {
+			var b bytes.Buffer
+			if err := WriteReelsCSV(&b, rs.Reels, &m.SymbolSetting); err != nil {
+				t.Fatalf("write reels: %v", err)
+			}
+			reels, err := ReadReelsCSV(&b, &m.SymbolSetting)
+			if err != nil {
+				t.Fatalf("read reels: %v", err)
+			}
+			if out, err = PatchYAML(out, fmt.Sprintf("game_mode_settings[%d].gen_screen_setting.reel_set_group[%d].reels", i, j), reels); err != nil {
+				t.Fatalf("patch reels: %v", err)
+			}
+		}
+		var b bytes.Buffer
+		if err := WritePayTableCSV(&b, &m.SymbolSetting); err != nil {
+			t.Fatalf("write pay table: %v", err)
+		}
+		pt, err := ReadPayTableCSV(&b, &m.SymbolSetting)
+		if err != nil {
+			t.Fatalf("read pay table: %v", err)
+		}
+		if out, err = PatchYAML(out, fmt.Sprintf("game_mode_settings[%d].symbol_setting.pay_table", i), pt); err != nil {
+			t.Fatalf("patch pay table: %v", err)
+		}
+	}
+	if !bytes.Equal(out, raw) {
+		t.Fatalf("no-op round trip changed the file")
+	}
+
+	// 改一個停點、加長一條輪帶：只有對應的行改變，註解與其他行保留
+	reels := gs.GameModeSettings[0].GenScreenSetting.ReelSetGroup[0].Reels
+	reels[1].ReelSymbols[0] = 3
+	reels[2].ReelSymbols = append(reels[2].ReelSymbols, 4)
+	for k := range reels {
+		reels[k].ReelWeights = nil
+	}
+	out, err = PatchYAML(raw, "game_mode_settings[0].gen_screen_setting.reel_set_group[0].reels", reels)
+	if err != nil {
+		t.Fatalf("patch reels: %v", err)
+	}
+	before, after := strings.Split(string(raw), "\n"), strings.Split(string(out), "\n")
+	if len(before) != len(after) {
+		t.Fatalf("line count changed: %d -> %d", len(before), len(after))
+	}
+	changed := 0
+	for k := range before {
+		if before[k] != after[k] {
+			changed++
+		}
+	}
+	if changed != 2 || !strings.Contains(string(out), "# Reel[1]") {
+		t.Fatalf("expected exactly 2 changed lines, got %d", changed)
+	}
+	gs2, err := GetGameSettingByYAML(out)
+	if err != nil {
+		t.Fatalf("parse patched config: %v", err)
+	}
+	if got := gs2.GameModeSettings[0].GenScreenSetting.ReelSetGroup[0].Reels; got[1].ReelSymbols[0] != 3 || len(got[2].ReelSymbols) != len(reels[2].ReelSymbols) {
+		t.Fatalf("patched reels mismatch")
+	}
+
+	// 結構不同（block 改寫、新增欄位）仍能得到正確內容
+	out, err = PatchYAML([]byte(validYAML), "game_mode_settings[0].gen_screen_setting.reel_set_group", []ReelSet{{Name: "a", Weight: 2, Reels: []Reel{{ReelSymbols: []int16{1}}, {ReelSymbols: []int16{0}}, {ReelSymbols: []int16{1, 0}}}}})
+	if err != nil {
+		t.Fatalf("patch reel set group: %v", err)
+	}
+	if gs2, err = GetGameSettingByYAML(out); err != nil || gs2.GameModeSettings[0].GenScreenSetting.ReelSetGroup[0].Name != "a" {
+		t.Fatalf("patched reel set group mismatch: %v\n%s", err, out)
+	}
+	out, err = PatchYAML([]byte(validYAML), "game_mode_settings[0].screen_setting.damp", 2)
+	if err != nil || !strings.Contains(string(out), "{columns: 3, rows: 1, damp: 2}") {
+		t.Fatalf("expected field added to flow mapping: %v\n%s", err, out)
+	}
+
+	// library 區塊定義在 includes 的檔案時，回傳該檔案
+	fsys := fstest.MapFS{"shared.yaml": {Data: []byte(sharedYAML)}}
+	if f, err := LibraryFile([]byte(composedYAML), "game.yaml", FSLoader(fsys), "reel_sets", "base"); err != nil || f != "shared.yaml" {
+		t.Fatalf("expected shared.yaml, got %q: %v", f, err)
+	}
+	if _, err := LibraryFile([]byte(composedYAML), "game.yaml", FSLoader(fsys), "pay_tables", "nope"); err == nil {
+		t.Fatalf("expected missing library block error")
+	}
+}
+
+func TestIrregularMask(t *testing.T) {
+	parse := func(mask string) *GameSetting {
+		y := strings.Replace(validYAML, "{columns: 3, rows: 1}", "{columns: 3, rows: 2, mask: "+mask+"}", 1)
+		gs, err := GetGameSettingByYAML([]byte(y))
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		return gs
+	}
+	gs := parse("[1, 0, 1, 1, 1, 1]")
+	if vs := CheckRules(gs, nil); len(vs) != 0 {
+		t.Fatalf("partial mask must be accepted for ways, got %v", vs)
+	}
+	ss := gs.GameModeSettings[0].ScreenSetting
+	if !ss.Irregular || ss.ColHeight(0) != 2 || ss.ColHeight(1) != 1 || ss.ColCells[1][0] != 4 || ss.Open(1) {
+		t.Fatalf("unexpected derived mask data: %+v", ss)
+	}
+	vs := CheckRules(parse("[1, 0, 1, 1, 0, 1]"), nil)
+	if len(vs) != 1 || vs[0].Rule != "mask_bet_type" {
+		t.Fatalf("expected empty column violation, got %v", vs)
+	}
+}
+
+func TestReelWeightsRejected(t *testing.T) {
+	for _, w := range []string{"weights: [0, 0]", "weights: [-1, 1]"} {
+		bad := []byte(strings.Replace(validYAML, "- symbols: [0, 1]", "- {symbols: [0, 1], "+w+"}", 1))
+		if _, err := GetGameSettingByYAML(bad); err == nil || !strings.Contains(err.Error(), "reel_set_group[0].reels[0]") {
+			t.Fatalf("expected reel weight error for %s, got %v", w, err)
+		}
+		if issues := ValidateYAML(bad); len(issues) != 1 || issues[0].Level != errs.Fatal {
+			t.Fatalf("expected one fatal issue for %s, got %v", w, issues)
+		}
+	}
+}
+
+func TestValidateYAMLReportsEveryProblem(t *testing.T) {
+	// 模式 0 同時有五個語意問題；模式 1 有一個初始化錯誤
+	bad := `game_name: t
+game_id: 1
+logic_key: k
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 2}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0, 7]
+            - {symbols: [0, 1], weights: [0, 0]}
+            - symbols: [0, 1]
+    symbol_setting:
+      symbol_used: [H1, W1]
+      pay_table: [[0, 0, 5], [0, 0]]
+    hit_setting:
+      bet_type: line_ltr
+      line_table: [[0, 0, 0], [0, 2, 0], [1, 1]]
+  - screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - {symbols: [0, 1], weights: [1]}
+            - symbols: [0, 1]
+            - symbols: [0, 1]
+    symbol_setting:
+      symbol_used: [H1, W1]
+      pay_table: [[0, 0, 5], [0, 0, 0]]
+    hit_setting:
+      bet_type: way_ltr
+`
+	lineOf := func(sub string) int {
+		return strings.Count(bad[:strings.Index(bad, sub)], "\n") + 1
+	}
+	want := map[string]int{
+		"game_mode_settings[0].gen_screen_setting.reel_set_group[0].reels[0].symbols[1]": lineOf("[0, 7]"),
+		"game_mode_settings[0].gen_screen_setting.reel_set_group[0].reels[1].weights":    lineOf("weights: [0, 0]"),
+		"game_mode_settings[0].symbol_setting.pay_table[1]":                              lineOf("pay_table:"),
+		"game_mode_settings[0].hit_setting.line_table[1][1]":                             lineOf("line_table:"),
+		"game_mode_settings[0].hit_setting.line_table[2]":                                lineOf("line_table:"),
+		"game_mode_settings[1].gen_screen_setting.reel_set_group[0].reels[0]":            lineOf("weights: [1]"),
+	}
+	issues := ValidateYAML([]byte(bad))
+	got := map[string]int{}
+	for _, ci := range issues {
+		got[ci.Path] = ci.Line
+	}
+	for path, line := range want {
+		if got[path] != line {
+			t.Fatalf("expected issue at %s line %d, got %v", path, line, issues)
+		}
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), issues)
+	}
+	if _, err := GetGameSettingByYAML([]byte(bad)); err == nil {
+		t.Fatalf("expected config to be rejected")
+	}
+}
+
+func TestReelHeights(t *testing.T) {
+	heights := strings.NewReplacer(
+		"{columns: 3, rows: 1}", "{columns: 3, rows: 4}",
+		"gen_reel_type: GenReelByReelIdx", `gen_reel_type: GenReelByHeight
+      reel_heights:
+        - {heights: [1, 2, 3]}
+        - {heights: [2, 3], weights: [1, 3]}
+        - {heights: [3]}
+      top_reel: {columns: [1, 2], reel: {symbols: [0, 1]}}`,
+	).Replace(validYAML)
+	if issues := ValidateYAML([]byte(heights)); len(issues) != 0 {
+		t.Fatalf("expected valid config, got %v", issues)
+	}
+	gs, err := GetGameSettingByYAML([]byte(heights))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	g := gs.GameModeSettings[0].GenScreenSetting
+	if g.MaxHeight() != 3 || g.TopRows() != 1 {
+		t.Fatalf("unexpected heights: max=%d top=%d", g.MaxHeight(), g.TopRows())
+	}
+
+	if _, err := GetGameSettingByYAML([]byte(strings.Replace(heights, "rows: 4", "rows: 3", 1))); err == nil {
+		t.Fatalf("expected rows too small error")
+	}
+	if _, err := GetGameSettingByYAML([]byte(strings.Replace(heights, "columns: [1, 2]", "columns: [1, 3]", 1))); err == nil {
+		t.Fatalf("expected top reel column error")
+	}
+	for _, w := range []string{"weights: [0, 0]", "weights: [-1, 3]"} {
+		bad := []byte(strings.Replace(heights, "weights: [1, 3]", w, 1))
+		if _, err := GetGameSettingByYAML(bad); err == nil {
+			t.Fatalf("expected error for %s", w)
+		}
+		if issues := ValidateYAML(bad); len(issues) == 0 {
+			t.Fatalf("expected issues for %s", w)
+		}
+	}
+	line := strings.Replace(heights, "bet_type: way_ltr", "bet_type: line_ltr\n      line_table: [[1, 1, 1]]", 1)
+	gs, err = GetGameSettingByYAML([]byte(line))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	found := false
+	for _, v := range CheckRules(gs, nil) {
+		found = found || v.Rule == "reel_heights"
+	}
+	if !found {
+		t.Fatalf("expected reel_heights violation for line bet type")
+	}
+}
+
+func TestMultiplierSettings(t *testing.T) {
+	y := strings.Replace(validYAML, "bet_type: way_ltr", "bet_type: way_ltr\n      multiplier_mode: add", 1)
+	gs, err := GetGameSettingByYAML([]byte(y))
+	if err != nil || !gs.GameModeSettings[0].HitSetting.MultAdd {
+		t.Fatalf("expected additive multiplier mode, err=%v", err)
+	}
+	if issues := ValidateYAML([]byte(strings.Replace(y, "mode: add", "mode: sum", 1))); len(issues) != 1 {
+		t.Fatalf("expected invalid multiplier_mode issue, got %v", issues)
+	}
+}
+
+func TestClusterSettings(t *testing.T) {
+	cl := strings.Replace(validYAML, "bet_type: way_ltr", "bet_type: cluster\n      cluster: {adjacency: 8, pay_tiers: [2, 3]}", 1)
+	gs, err := GetGameSettingByYAML([]byte(cl))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	found := false
+	for _, v := range CheckRules(gs, nil) {
+		found = found || v.Rule == "cluster_pay_tiers"
+	}
+	if !found {
+		t.Fatalf("expected cluster_pay_tiers violation for 3-wide pay table")
+	}
+	for _, bad := range []string{
+		strings.Replace(cl, "bet_type: cluster", "bet_type: way_ltr", 1),
+		strings.Replace(cl, "adjacency: 8", "adjacency: 6", 1),
+		strings.Replace(cl, "pay_tiers: [2, 3]", "pay_tiers: [3, 2]", 1),
+		strings.Replace(cl, "adjacency: 8", "topology: hex_odd_col, adjacency: 4", 1),
+	} {
+		if _, err := GetGameSettingByYAML([]byte(bad)); err == nil {
+			t.Fatalf("expected error for:\n%s", bad)
+		}
+	}
+}
+
+func TestHoldAndWinSetting(t *testing.T) {
+	hw := strings.Replace(validYAML, "bet_type: way_ltr", "bet_type: way_ltr\n    hold_and_win: {respins: 3, coins: [W1], coin_values: [1, 5], coin_weights: [3, 1]}", 1)
+	gs, err := GetGameSettingByYAML([]byte(hw))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	set := gs.GameModeSettings[0].HoldAndWin
+	if set == nil || set.CoinMask != 1<<1 || len(set.CoinValueLUT) != 4 {
+		t.Fatalf("unexpected hold_and_win setting: %+v", set)
+	}
+	if issues := ValidateYAML([]byte(hw)); len(issues) != 0 {
+		t.Fatalf("expected schema to accept hold_and_win, got %v", issues)
+	}
+	for _, bad := range []string{
+		strings.Replace(hw, "respins: 3", "respins: 0", 1),
+		strings.Replace(hw, "coins: [W1]", "coins: [H9]", 1),
+		strings.Replace(hw, "coins: [W1]", "coins: [W1], collectors: [W1]", 1),
+		strings.Replace(hw, "coin_weights: [3, 1]", "coin_weights: [3]", 1),
+		strings.Replace(hw, "coin_weights: [3, 1]", "coin_weights: [0, 0]", 1),
+	} {
+		if _, err := GetGameSettingByYAML([]byte(bad)); err == nil {
+			t.Fatalf("expected error for:\n%s", bad)
+		}
+	}
+}
+
+func TestGameSettingByJSONFixedNumbers(t *testing.T) {
+	// JSON 與 YAML 共用解碼流程：fixed 的整數為 int（不是 encoding/json 的 float64）
+	raw := `{"game_name": "t", "game_id": 1, "logic_key": "k", "bet_units": [10], "max_win_limit": 1000,
+  "fixed": {"rounds": 3, "ratio": 1.5},
+  "game_mode_settings": [{
+    "screen_setting": {"columns": 3, "rows": 1},
+    "gen_screen_setting": {"gen_reel_type": "GenReelByReelIdx",
+      "reel_set_group": [{"weight": 1, "reels": [{"symbols": [0, 1]}, {"symbols": [0, 1]}, {"symbols": [0, 1]}]}]},
+    "symbol_setting": {"symbol_used": ["H1", "W1"], "pay_table": [[0, 0, 5], [0, 0, 0]]},
+    "hit_setting": {"bet_type": "way_ltr"}}]}`
+	gs, err := GetGameSettingByJSON([]byte(raw))
+	if err != nil {
+		t.Fatalf("parse json failed: %v", err)
+	}
+	if v, ok := gs.Fixed["rounds"].(int); !ok || v != 3 {
+		t.Fatalf("expected int rounds, got %T %v", gs.Fixed["rounds"], gs.Fixed["rounds"])
+	}
+	if v, ok := gs.Fixed["ratio"].(float64); !ok || v != 1.5 {
+		t.Fatalf("expected float64 ratio, got %T %v", gs.Fixed["ratio"], gs.Fixed["ratio"])
+	}
+	if _, err := GetGameSettingByJSON([]byte(raw[:len(raw)-1])); err == nil {
+		t.Fatalf("expected invalid json to fail")
+	}
+}
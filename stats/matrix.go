@@ -0,0 +1,178 @@
+// Copyright 2025 Zintix Labs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/zintix-labs/problab/spec"
+	"golang.org/x/text/message"
+)
+
+// RTPTolerance RTP 容許範圍：|RTP - Target| <= Tolerance 視為通過
+type RTPTolerance struct {
+	Target    float64 `json:"rtp" yaml:"rtp"`
+	Tolerance float64 `json:"tolerance" yaml:"tolerance"`
+}
+
+// Contains 判斷 rtp 是否落在容許範圍內
+func (t RTPTolerance) Contains(rtp float64) bool {
+	return math.Abs(rtp-t.Target) <= t.Tolerance
+}
+
+// MatrixCell 批次模擬矩陣中單一 game × bet mode 的結果
+type MatrixCell struct {
+	GameName    string        `json:"GameName"`
+	GameId      spec.GID      `json:"GameId"`
+	BetMode     int           `json:"BetMode"`
+	Seed        int64         `json:"Seed"`
+	Rounds      int           `json:"Rounds"`
+	RTP         float64       `json:"RTP"`
+	RtpCI       CI            `json:"RtpCI"`
+	Std         float64       `json:"Std"`
+	HitRate     float64       `json:"HitRate"`
+	TriggerRate float64       `json:"TriggerRate"`
+	MaxWin      int           `json:"MaxWin"`
+	MaxWinMult  float64       `json:"MaxWinMult"`
+	Tolerance   *RTPTolerance `json:"Tolerance,omitempty"`
+	Pass        bool          `json:"Pass"`
+}
+
+// MatrixReport 批次模擬矩陣報表
+type MatrixReport struct {
+	Cells []MatrixCell `json:"Cells"`
+}
+
+// NewMatrixCell 由已完成的 StatReport 擷取矩陣欄位
+func NewMatrixCell(st *StatReport, seed int64) MatrixCell {
+	st.Done()
+	return MatrixCell{
+		GameName:    st.Summary.GameName,
+		GameId:      st.Summary.GameId,
+		BetMode:     st.Summary.BetMode,
+		Seed:        seed,
+		Rounds:      st.Summary.Rounds,
+		RTP:         st.Summary.RTP,
+		RtpCI:       st.Summary.RtpCI,
+		Std:         st.Summary.Std,
+		HitRate:     st.Summary.HitRate,
+		TriggerRate: st.Summary.TriggerRate,
+		MaxWin:      st.Summary.MaxWin,
+		MaxWinMult:  st.Summary.MaxWinMult,
+		Pass:        true,
+	}
+}
+
+// Check 依各遊戲容許範圍判定每格是否通過；無設定者以 def 判定，def 為 nil 則視為通過
+//
+// 回傳未通過的格數
+func (m *MatrixReport) Check(byGame map[spec.GID]RTPTolerance, def *RTPTolerance) int {
+	for i := range m.Cells {
+		c := &m.Cells[i]
+		c.Tolerance = nil
+		if t, ok := byGame[c.GameId]; ok {
+			c.Tolerance = &t
+		} else if def != nil {
+			t := *def
+			c.Tolerance = &t
+		}
+		c.Pass = c.Tolerance == nil || c.Tolerance.Contains(c.RTP)
+	}
+	return m.Failed()
+}
+
+// Failed 回傳未通過的格數
+func (m *MatrixReport) Failed() int {
+	n := 0
+	for _, c := range m.Cells {
+		if !c.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteWith 以指定 render 輸出
+func (m *MatrixReport) WriteWith(w io.Writer, rep MatrixReportRender) error {
+	return rep.Write(w, m)
+}
+
+// StdOut 以表格輸出矩陣
+func (m *MatrixReport) StdOut() {
+	fmt.Println(m.fmtMatrix())
+}
+
+func (m *MatrixReport) fmtMatrix() string {
+	p := message.NewPrinter(lang)
+	head := []string{"GID", "Game", "Mode", "Rounds", "RTP", "RTP 95% CI", "STD", "Hit Rate", "Trigger Rate", "Max Win", "Target", "Result"}
+	rows := make([][]string, 0, len(m.Cells))
+	for _, c := range m.Cells {
+		target := "-"
+		if c.Tolerance != nil {
+			target = p.Sprintf("%.2f%% ± %.2f%%", 100.0*c.Tolerance.Target, 100.0*c.Tolerance.Tolerance)
+		}
+		result := "PASS"
+		if !c.Pass {
+			result = "FAIL"
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", c.GameId),
+			c.GameName,
+			fmt.Sprintf("%d", c.BetMode),
+			p.Sprintf("%d", c.Rounds),
+			p.Sprintf("%.2f %%", 100.0*c.RTP),
+			p.Sprintf("[%.2f%%,%.2f%%]", 100.0*c.RtpCI.Lo, 100.0*c.RtpCI.Hi),
+			p.Sprintf("%.3f", c.Std),
+			p.Sprintf("%.2f %%", 100.0*c.HitRate),
+			p.Sprintf("%.4f %%", 100.0*c.TriggerRate),
+			p.Sprintf("x%.2f", c.MaxWinMult),
+			target,
+			result,
+		})
+	}
+
+	width := make([]int, len(head))
+	for i, h := range head {
+		width[i] = runewidth.StringWidth(h)
+	}
+	for _, r := range rows {
+		for i, v := range r {
+			width[i] = max(width[i], runewidth.StringWidth(v))
+		}
+	}
+	divider := "+"
+	for _, w := range width {
+		divider += strings.Repeat("-", w+2) + "+"
+	}
+	divider += "\n"
+	line := func(r []string) string {
+		s := "|"
+		for i, v := range r {
+			s += " " + v + blank(width[i]-runewidth.StringWidth(v)) + " |"
+		}
+		return s + "\n"
+	}
+
+	out := divider + line(head) + divider
+	for _, r := range rows {
+		out += line(r)
+	}
+	out += divider
+	return out
+}
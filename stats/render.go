@@ -62,6 +62,24 @@ func (yr *YAMLEstimatorRender) Write(w io.Writer, e *EstimatorPlayers) error {
 	return forceReadableList(w, e)
 }
 
+type MatrixReportRender interface {
+	Write(w io.Writer, m *MatrixReport) error
+}
+
+// Json渲染
+type JsonMatrixReportRender struct{}
+
+func (jr *JsonMatrixReportRender) Write(w io.Writer, m *MatrixReport) error {
+	return json.NewEncoder(w).Encode(m)
+}
+
+// YAML渲染
+type YAMLMatrixReportRender struct{}
+
+func (yr *YAMLMatrixReportRender) Write(w io.Writer, m *MatrixReport) error {
+	return forceReadableList(w, m)
+}
+
 // YAML 內層方法
 func forceReadableList[T any](w io.Writer, t *T) error {
 	var node yaml.Node
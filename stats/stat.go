@@ -64,6 +64,9 @@ type SummaryReport struct {
 	NoWinRounds int      `json:"NoWinRounds"`
 	HitRate     float64  `json:"HitRate"`
 	Rounds      int      `json:"Rounds"`
+	MaxWin      int      `json:"MaxWin"`
+	MaxWinMult  float64  `json:"MaxWinMult"`
+	Fingerprint string   `json:"Fingerprint,omitempty"` // 產生本報表的設定檔指紋
 }
 
 // MultReport 贏倍統計
@@ -235,10 +238,11 @@ func (s *StatReport) fmtBasic() ([]string, map[string]string) {
 		"Free Win":     p.Sprintf("%d", s.Summary.FreeWin),
 		"NoWin Rounds": p.Sprintf("%d", s.Summary.NoWinRounds),
 		"Trigger":      p.Sprintf("%d", s.Summary.Trigger),
+		"Max Win":      p.Sprintf("%d (x%.2f)", s.Summary.MaxWin, s.Summary.MaxWinMult),
 		"STD":          p.Sprintf("%.3f", s.Summary.Std),
 		"CV":           p.Sprintf("%.3f", s.Summary.Cv),
 	}
-	keys := []string{"Game Name", "Game ID", "Total Rounds", "Total RTP", "RTP 95% CI", "Total Bet", "Total Win", "Base Win", "Free Win", "NoWin Rounds", "Trigger", "STD", "CV"}
+	keys := []string{"Game Name", "Game ID", "Total Rounds", "Total RTP", "RTP 95% CI", "Total Bet", "Total Win", "Base Win", "Free Win", "NoWin Rounds", "Trigger", "Max Win", "STD", "CV"}
 	return keys, basic
 }
 
@@ -163,6 +163,41 @@ func TestEstimatorRtpAndSession(t *testing.T) {
 	}
 }
 
+func TestMatrixReportCheck(t *testing.T) {
+	m := &stats.MatrixReport{Cells: []stats.MatrixCell{
+		{GameId: 0, BetMode: 0, RTP: 0.960},
+		{GameId: 0, BetMode: 1, RTP: 0.980},
+		{GameId: 1, BetMode: 0, RTP: 0.500},
+	}}
+	// 無任何容許範圍：全部通過
+	if failed := m.Check(nil, nil); failed != 0 || m.Failed() != 0 {
+		t.Fatalf("expected all pass without tolerance, got %d", failed)
+	}
+
+	// game 0 ±1%：0.96 通過（邊界內）、0.98 失敗；game 1 無設定、無預設 → 通過
+	byGame := map[spec.GID]stats.RTPTolerance{0: {Target: 0.965, Tolerance: 0.01}}
+	if failed := m.Check(byGame, nil); failed != 1 || m.Failed() != 1 {
+		t.Fatalf("expected 1 failure, got %d", failed)
+	}
+	if !m.Cells[0].Pass || m.Cells[1].Pass || !m.Cells[2].Pass || m.Cells[2].Tolerance != nil {
+		t.Fatalf("unexpected pass flags: %+v", m.Cells)
+	}
+
+	// 預設容許範圍套用到沒有個別設定的遊戲
+	def := &stats.RTPTolerance{Target: 0.9, Tolerance: 0.05}
+	if failed := m.Check(byGame, def); failed != 2 {
+		t.Fatalf("expected 2 failures with default tolerance, got %d", failed)
+	}
+	if tol := m.Cells[2].Tolerance; tol == nil || *tol != *def || tol == def {
+		t.Fatalf("default tolerance must be copied per cell, got %+v", tol)
+	}
+
+	// 重新判定會覆蓋先前結果
+	if failed := m.Check(nil, &stats.RTPTolerance{Target: 0.5, Tolerance: 1}); failed != 0 || m.Failed() != 0 {
+		t.Fatalf("expected re-check to reset results, got %d", failed)
+	}
+}
+
 // --- helpers ---
 
 func max0(x float64) float64 {
@@ -0,0 +1,310 @@
+package problab_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/zintix-labs/problab"
+	"github.com/zintix-labs/problab/demo/demo_configs"
+	"github.com/zintix-labs/problab/demo/demo_logic"
+	"github.com/zintix-labs/problab/dto"
+	"github.com/zintix-labs/problab/sdk/buf"
+	"github.com/zintix-labs/problab/sdk/calc"
+	"github.com/zintix-labs/problab/sdk/core"
+	"github.com/zintix-labs/problab/sdk/flow"
+	"github.com/zintix-labs/problab/spec"
+	"github.com/zintix-labs/problab/stats"
+)
+
+// payAnywhereYAML 盤面固定為 [H2, H1, H2]：每局 H2 兩顆，依 pay_table 派 2 倍
+const payAnywhereYAML = `game_name: pay_anywhere
+game_id: 7
+logic_key: flow
+bet_units: [10]
+max_win_limit: 1000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 1}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [1]
+            - symbols: [0]
+            - symbols: [1]
+    symbol_setting:
+      symbol_used: [H1, H2]
+      pay_table: [[0, 0, 4], [0, 2, 3]]
+    hit_setting:
+      bet_type: pay_anywhere_root_test
+fixed:
+  steps:
+    - mode: 0
+`
+
+// calcPayAnywhere 同一圖標出現次數達門檻即派彩，命中格子記入 HitsFlat
+func calcPayAnywhere(betMult int, screen []int16, gmr *buf.GameModeResult, sc *calc.ScreenCalculator) {
+	hits := sc.Custom.([][]int16)
+	for s := range hits {
+		hits[s] = hits[s][:0]
+	}
+	for i, s := range screen {
+		if uint(s) < uint(len(hits)) {
+			hits[s] = append(hits[s], int16(i))
+		}
+	}
+	for s, h := range hits {
+		n := len(h)
+		if n == 0 || sc.MinPayCount(s) == 0 || n < sc.MinPayCount(s) {
+			continue
+		}
+		win := sc.PayTableFlat[sc.PayTableIndex[s]+n-1] * betMult
+		gmr.RecordDetail(win, int16(s), 0, n, 0, 0, h)
+	}
+}
+
+func TestCustomBetTypeSpinAndSim(t *testing.T) {
+	_, err := calc.RegisterBetType("pay_anywhere_root_test", calc.Evaluator{
+		Calc: calcPayAnywhere,
+		Init: func(sc *calc.ScreenCalculator) {
+			hits := make([][]int16, sc.SymbolSetting.SymbolCount)
+			for s := range hits {
+				hits[s] = make([]int16, 0, sc.ScreenSize)
+			}
+			sc.Custom = hits
+		},
+	})
+	if err != nil {
+		t.Fatalf("register bet type: %v", err)
+	}
+	lab, err := problab.NewAuto(core.Default(),
+		problab.Configs(fstest.MapFS{"pay_anywhere.yaml": {Data: []byte(payAnywhereYAML)}}),
+		problab.Logics(flow.Logics))
+	if err != nil {
+		t.Fatalf("new problab: %v", err)
+	}
+
+	m, err := lab.NewMachine(7, false)
+	if err != nil {
+		t.Fatalf("new machine: %v", err)
+	}
+	res, err := m.Spin(&dto.SpinRequest{GameName: "pay_anywhere", GameId: 7, Bet: 10, BetMult: 1})
+	if err != nil {
+		t.Fatalf("spin: %v", err)
+	}
+	if res.TotalWin != 2 || len(res.GameModes) != 1 || len(res.GameModes[0].ActResults) != 2 {
+		t.Fatalf("unexpected spin result: %+v", res)
+	}
+	d := res.GameModes[0].ActResults[1].Details
+	if len(d) != 1 || d[0].SymbolID != 1 || d[0].Count != 2 || d[0].Win != 2 {
+		t.Fatalf("unexpected details: %+v", d)
+	}
+	if len(d[0].HitMap) != 2 || d[0].HitMap[0] != 0 || d[0].HitMap[1] != 2 {
+		t.Fatalf("unexpected hits: %v", d[0].HitMap)
+	}
+
+	sim, err := lab.NewSimulatorWithSeed(spec.GID(7), 1)
+	if err != nil {
+		t.Fatalf("new simulator: %v", err)
+	}
+	rep, _, err := sim.Sim(0, 100, false)
+	if err != nil {
+		t.Fatalf("sim: %v", err)
+	}
+	if rep.Summary.TotalWin != 200 || rep.Summary.TotalBet != 1000 {
+		t.Fatalf("unexpected sim totals: win=%d bet=%d", rep.Summary.TotalWin, rep.Summary.TotalBet)
+	}
+}
+
+// matrixYAML 隨機輪帶的 way 遊戲（兩個 bet mode），供批次模擬測試
+const matrixYAML = `game_name: matrix_GID
+game_id: GID
+logic_key: flow
+bet_units: [10, 20]
+max_win_limit: 100000
+game_mode_settings:
+  - screen_setting: {columns: 3, rows: 2}
+    gen_screen_setting:
+      gen_reel_type: GenReelByReelIdx
+      reel_set_group:
+        - weight: 1
+          reels:
+            - symbols: [0, 1, 2, 1, 0, 2]
+            - symbols: [1, 0, 2, 2, 0, 1]
+            - symbols: [2, 1, 0, 0, 1, 2]
+    symbol_setting:
+      symbol_used: [H1, H2, L1]
+      pay_table: [[0, 0, 20], [0, 0, 10], [0, 0, 5]]
+    hit_setting:
+      bet_type: way_ltr
+fixed:
+  steps:
+    - mode: 0
+`
+
+func TestSimMatrix(t *testing.T) {
+	cfgs := fstest.MapFS{}
+	for _, gid := range []string{"1", "2"} {
+		cfgs["matrix_"+gid+".yaml"] = &fstest.MapFile{Data: []byte(strings.ReplaceAll(matrixYAML, "GID", gid))}
+	}
+	lab, err := problab.NewAuto(core.Default(), problab.Configs(cfgs), problab.Logics(flow.Logics))
+	if err != nil {
+		t.Fatalf("new problab: %v", err)
+	}
+	plan := problab.MatrixPlan{Rounds: 300, Workers: 1, Seed: 42}
+	first, err := lab.SimMatrix(plan)
+	if err != nil {
+		t.Fatalf("sim matrix: %v", err)
+	}
+	if len(first.Cells) != 4 {
+		t.Fatalf("expected 2 games x 2 modes, got %d", len(first.Cells))
+	}
+	for _, c := range first.Cells {
+		if c.Seed != problab.MatrixSeed(42, c.GameId, c.BetMode) || c.Rounds != 300 || c.RTP <= 0 || !c.Pass {
+			t.Fatalf("unexpected cell: %+v", c)
+		}
+	}
+
+	// 同一基準種子結果相同；單跑其中一格與批次中的該格一致
+	again, err := lab.SimMatrix(plan)
+	if err != nil {
+		t.Fatalf("sim matrix again: %v", err)
+	}
+	if !reflect.DeepEqual(first.Cells, again.Cells) {
+		t.Fatalf("same seed must reproduce the matrix:\n%+v\n%+v", first.Cells, again.Cells)
+	}
+	last := first.Cells[len(first.Cells)-1]
+	one, err := lab.SimMatrix(problab.MatrixPlan{GIDs: []spec.GID{last.GameId}, BetModes: []int{last.BetMode}, Rounds: 300, Workers: 1, Seed: 42})
+	if err != nil || len(one.Cells) != 1 || !reflect.DeepEqual(one.Cells[0], last) {
+		t.Fatalf("single cell must match the batch cell: %v %+v", err, one)
+	}
+	other, _ := lab.SimMatrix(problab.MatrixPlan{GIDs: []spec.GID{last.GameId}, Rounds: 300, Workers: 1, Seed: 43})
+	if other.Cells[0].Seed == last.Seed {
+		t.Fatalf("different base seed must derive a different seed")
+	}
+
+	// 容許範圍判定
+	first.Check(nil, &stats.RTPTolerance{Target: -1, Tolerance: 0.5})
+	if first.Failed() != len(first.Cells) {
+		t.Fatalf("expected every cell to fail an impossible target, got %d", first.Failed())
+	}
+
+	if _, err := lab.SimMatrix(problab.MatrixPlan{GIDs: []spec.GID{1}, BetModes: []int{2}, Rounds: 10, Workers: 1}); err == nil {
+		t.Fatalf("expected out of range bet mode error")
+	}
+	if _, err := lab.SimMatrix(problab.MatrixPlan{Rounds: 0, Workers: 1}); err == nil {
+		t.Fatalf("expected rounds error")
+	}
+}
+
+func TestMatrixSeed(t *testing.T) {
+	seen := map[int64]bool{}
+	for gid := spec.GID(0); gid < 8; gid++ {
+		for mode := range 4 {
+			s := problab.MatrixSeed(7, gid, mode)
+			if s != problab.MatrixSeed(7, gid, mode) || s < 0 {
+				t.Fatalf("seed must be stable and non-negative, got %d", s)
+			}
+			if seen[s] {
+				t.Fatalf("duplicate seed for gid %d mode %d", gid, mode)
+			}
+			seen[s] = true
+		}
+	}
+}
+
+func TestFingerprintStampedAndChecked(t *testing.T) {
+	// 不掛優化文件，讓正式 Spin 走一般 RNG 路徑
+	lab, err := problab.NewAuto(core.Default(), problab.Configs(demo_configs.FS), problab.Logics(demo_logic.Logics))
+	if err != nil {
+		t.Fatalf("new problab failed: %v", err)
+	}
+	ent, ok := lab.EntryById(0)
+	if !ok || ent.Fingerprint == "" {
+		t.Fatalf("catalog entry must carry fingerprint: %+v", ent)
+	}
+	fp := ent.Fingerprint
+	gs, err := spec.GetGameSettingFromFS(demo_configs.FS, ent.ConfigName)
+	if err != nil {
+		t.Fatalf("load setting failed: %v", err)
+	}
+	if want, _ := problab.ConfigFingerprint(gs, nil); want != fp {
+		t.Fatalf("entry fingerprint %q, want %q", fp, want)
+	}
+
+	sim, err := lab.NewSimulatorWithSeed(0, 7)
+	if err != nil {
+		t.Fatalf("new simulator failed: %v", err)
+	}
+	st, _, err := sim.SimMP(0, 100, 2, false)
+	if err != nil {
+		t.Fatalf("sim failed: %v", err)
+	}
+	if st.Summary.Fingerprint != fp {
+		t.Fatalf("report fingerprint %q, want %q", st.Summary.Fingerprint, fp)
+	}
+
+	m, err := lab.NewMachineWithSeed(0, 1, false)
+	if err != nil {
+		t.Fatalf("new machine failed: %v", err)
+	}
+	req := &dto.SpinRequest{GameName: ent.Name, GameId: 0, Bet: m.BetUnits[0], BetMult: 1}
+	res, err := m.Spin(req)
+	if err != nil {
+		t.Fatalf("spin failed: %v", err)
+	}
+	if res.Fingerprint != fp {
+		t.Fatalf("spin fingerprint %q, want %q", res.Fingerprint, fp)
+	}
+	req.StartState = &dto.StartState{StartCoreSnapB64U: res.State.StartCoreSnapB64U}
+	req.Fingerprint = res.Fingerprint
+	if _, err := m.Spin(req); err != nil {
+		t.Fatalf("replay with matching fingerprint failed: %v", err)
+	}
+	req.Fingerprint = "sha256:00"
+	if _, err := m.Spin(req); err == nil {
+		t.Fatalf("expected fingerprint mismatch on replay")
+	}
+}
+
+func TestVariantsDefaultAndPoolMetrics(t *testing.T) {
+	variant := func(v string) *fstest.MapFile {
+		y := strings.ReplaceAll(matrixYAML, "GID", "1")
+		if v != "" {
+			y = "variant: " + v + "\n" + y
+		}
+		return &fstest.MapFile{Data: []byte(y)}
+	}
+
+	// 只有具名變體、缺少預設設定檔
+	_, err := problab.NewAuto(core.Default(),
+		problab.Configs(fstest.MapFS{"a.yaml": variant("rtp92"), "b.yaml": variant("rtp96")}),
+		problab.Logics(flow.Logics))
+	if err == nil || !strings.Contains(err.Error(), "missing default variant") {
+		t.Fatalf("expected missing default variant error, got %v", err)
+	}
+
+	lab, err := problab.NewAuto(core.Default(),
+		problab.Configs(fstest.MapFS{"a.yaml": variant("rtp92"), "b.yaml": variant("rtp96"), "z.yaml": variant("")}),
+		problab.Logics(flow.Logics))
+	if err != nil {
+		t.Fatalf("new problab: %v", err)
+	}
+	if ent, _ := lab.EntryById(1); ent.Variant != "" || ent.ConfigName != "z.yaml" {
+		t.Fatalf("default variant must be the config without variant: %+v", ent)
+	}
+	rt, err := lab.BuildRuntime(1)
+	if err != nil {
+		t.Fatalf("build runtime: %v", err)
+	}
+	defer rt.Close()
+	ms, ok := rt.PoolMetrics(1)
+	if !ok || len(ms) != 3 || ms[0].Variant != "" || ms[1].Variant != "rtp92" || ms[2].Variant != "rtp96" {
+		t.Fatalf("pool metrics must cover every variant: %+v", ms)
+	}
+	if _, ok := rt.PoolMetrics(9); ok {
+		t.Fatalf("unknown gid must not report metrics")
+	}
+}